@@ -3,6 +3,7 @@ package config
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -19,7 +20,68 @@ type Config struct {
 	DBSSLMode      string `mapstructure:"POSTGRES_SSLMODE"`
 	COOKIEDOMAIN   string `mapstructure:"COOKIE_DOMAIN"`
 
-	ClientOrigin string `mapstructure:"CLIENT_ORIGIN"`
+	RequestTimeout time.Duration `mapstructure:"REQUEST_TIMEOUT"`
+	DBQueryTimeout time.Duration `mapstructure:"DB_QUERY_TIMEOUT"`
+
+	// PageSizeDefault is the number of items a list endpoint returns when
+	// the client sends no limit, PageSizeMax the largest limit accepted.
+	PageSizeDefault int `mapstructure:"PAGE_SIZE_DEFAULT"`
+	PageSizeMax     int `mapstructure:"PAGE_SIZE_MAX"`
+
+	// FeedFanOutLimit is the number of followers above which an author's
+	// posts are no longer copied into home timelines but read on request.
+	FeedFanOutLimit int `mapstructure:"FEED_FANOUT_LIMIT"`
+
+	// FeedRankers lists the rankers of the top feed, separated by commas;
+	// viewers are split evenly between them. It defaults to "baseline".
+	FeedRankers string `mapstructure:"FEED_RANKERS"`
+
+	// AdminUserIDs lists the IDs of the admins, separated by commas. Admins
+	// may delete any post and inspect the ranking scores of the top feed.
+	AdminUserIDs string `mapstructure:"ADMIN_USER_IDS"`
+
+	// ClientOrigin lists the browser origins allowed to call the API,
+	// separated by commas. It cannot be "*" as browsers send the session
+	// cookies. CORSMaxAge is how long preflights are cached.
+	ClientOrigin string        `mapstructure:"CLIENT_ORIGIN"`
+	CORSMaxAge   time.Duration `mapstructure:"CORS_MAX_AGE"`
+
+	// LogFormat is "json" or "text"; production logs JSON by default.
+	// LogLevel is debug, info, warn or error.
+	LogFormat string `mapstructure:"LOG_FORMAT"`
+	LogLevel  string `mapstructure:"LOG_LEVEL"`
+
+	// MetricsToken, when set, must be sent as a bearer token to scrape
+	// /metrics.
+	MetricsToken string `mapstructure:"METRICS_TOKEN"`
+
+	// TracingEndpoint is the OTLP/HTTP URL traces are exported to, such as
+	// http://localhost:4318 for a local collector; tracing is off when it
+	// is empty. TracingSampleRatio is the fraction of traces kept.
+	TracingEndpoint    string  `mapstructure:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	TracingServiceName string  `mapstructure:"OTEL_SERVICE_NAME"`
+	TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+
+	// Environment is "production" in production. Cookies are only marked
+	// Secure there, so local clients can log in over plain HTTP.
+	// CookieSameSite overrides the SameSite mode (lax, strict or none).
+	Environment    string `mapstructure:"APP_ENV"`
+	CookieSameSite string `mapstructure:"COOKIE_SAMESITE"`
+
+	// RateLimitStore is "memory" to keep rate limit buckets per process
+	// rather than in the database. The limits are requests per minute;
+	// zero selects the default.
+	RateLimitStore   string `mapstructure:"RATE_LIMIT_STORE"`
+	RateLimitPerIP   int    `mapstructure:"RATE_LIMIT_PER_IP"`
+	RateLimitPerUser int    `mapstructure:"RATE_LIMIT_PER_USER"`
+	RateLimitAuth    int    `mapstructure:"RATE_LIMIT_AUTH"`
+	RateLimitUploads int    `mapstructure:"RATE_LIMIT_UPLOADS"`
+
+	// Proxies lists the addresses or CIDRs of the reverse proxies in front
+	// of the server, separated by commas. Only their X-Forwarded-For header
+	// is believed when rate limiting by client IP; none are trusted by
+	// default.
+	Proxies string `mapstructure:"TRUSTED_PROXIES"`
 
 	TokenSecret    string        `mapstructure:"TOKEN_SECRET"`
 	TokenExpiresIn time.Duration `mapstructure:"TOKEN_EXPIRED_IN"`
@@ -37,6 +99,44 @@ type Config struct {
 	AWS_BUCKET_NAME       string `mapstructure:"AWS_BUCKET_NAME"`
 }
 
+// IsProduction reports whether the server runs in production.
+func (c Config) IsProduction() bool {
+	return strings.EqualFold(strings.TrimSpace(c.Environment), "production")
+}
+
+// AllowedOrigins returns the origins listed in ClientOrigin.
+func (c Config) AllowedOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(c.ClientOrigin, ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// TrustedProxies returns the proxies listed in Proxies.
+func (c Config) TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(c.Proxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// IsAdmin reports whether userID is listed in AdminUserIDs.
+func (c Config) IsAdmin(userID string) bool {
+	for _, id := range strings.Split(c.AdminUserIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" && strings.EqualFold(id, userID) {
+			return true
+		}
+	}
+	return false
+}
+
 func LoadConfig(path string) (Config, error) {
 	var config Config
 	viper.AddConfigPath(path)
@@ -68,6 +168,5 @@ func ConnectDB(cfg *Config) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to ping the database: %v", err)
 	}
 
-	fmt.Println("🚀 Connected Successfully to the Database")
 	return db, nil
 }
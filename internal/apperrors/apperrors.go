@@ -0,0 +1,134 @@
+// Package apperrors defines the error kinds shared by repositories, services
+// and handlers. Code compares kinds with errors.Is; the error handler
+// middleware turns them into HTTP statuses and stable error codes.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Every *Error wraps exactly one of them.
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrForbidden     = errors.New("forbidden")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrValidation    = errors.New("validation failed")
+	ErrRateLimited   = errors.New("rate limited")
+	ErrUnprocessable = errors.New("unprocessable")
+	ErrTooLarge      = errors.New("too large")
+)
+
+// Error is a domain error. Code and Message are safe to show to clients;
+// Err is the underlying cause and is only logged.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError describes one invalid input field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As.
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// New creates an error of the given kind with a stable code and a message.
+func New(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap classifies err as kind, using the kind's default code and message.
+func Wrap(kind, err error) *Error {
+	return &Error{Kind: kind, Code: DefaultCode(kind), Message: defaultMessages[kind], Err: err}
+}
+
+func NotFound(code, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(ErrUnauthorized, code, message)
+}
+
+// RateLimited reports a client that sent too many requests.
+func RateLimited() *Error {
+	return Wrap(ErrRateLimited, nil)
+}
+
+// Unprocessable reports a well-formed request that cannot be carried out as
+// sent.
+func Unprocessable(code, message string) *Error {
+	return New(ErrUnprocessable, code, message)
+}
+
+// TooLarge reports a request body above the size the server accepts.
+func TooLarge(code, message string) *Error {
+	return New(ErrTooLarge, code, message)
+}
+
+// Validation reports invalid input, optionally with per-field details.
+func Validation(message string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: DefaultCode(ErrValidation), Message: message, Fields: fields}
+}
+
+// InvalidField reports a single invalid input field.
+func InvalidField(field, message string) *Error {
+	return Validation("Invalid "+field, FieldError{Field: field, Message: message})
+}
+
+var defaultCodes = map[error]string{
+	ErrNotFound:      "not_found",
+	ErrConflict:      "conflict",
+	ErrForbidden:     "forbidden",
+	ErrUnauthorized:  "unauthorized",
+	ErrValidation:    "validation_failed",
+	ErrRateLimited:   "rate_limited",
+	ErrUnprocessable: "unprocessable",
+	ErrTooLarge:      "too_large",
+}
+
+var defaultMessages = map[error]string{
+	ErrNotFound:      "Resource not found",
+	ErrConflict:      "Resource already exists",
+	ErrForbidden:     "You are not allowed to perform this action",
+	ErrUnauthorized:  "Authentication required",
+	ErrValidation:    "Invalid input",
+	ErrRateLimited:   "Too many requests, slow down",
+	ErrUnprocessable: "The request cannot be processed",
+	ErrTooLarge:      "The request is too large",
+}
+
+// DefaultCode returns the code reported for kind when an error has none.
+func DefaultCode(kind error) string {
+	if code, ok := defaultCodes[kind]; ok {
+		return code
+	}
+	return "internal_error"
+}
@@ -1,36 +1,119 @@
 package controllers
 
 import (
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/session"
 )
 
 type AuthController struct {
 	AuthService *services.AuthService
-	Config      config.Config
+	Cookies     session.Cookies
+}
+
+// RegisterRoutes mounts the authentication endpoints.
+func (c *AuthController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	public.POST("/auth/register", c.Register)
+	public.POST("/auth/login", c.Login)
+	public.POST("/auth/verify-otp", c.VerifyOTP)
+	public.POST("/auth/forgot-password", c.ForgotPassword)
+	public.POST("/auth/reset-password", c.ResetPassword)
+	protected.POST("/auth/logout", c.LogoutUser)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (c *AuthController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/auth/register", Successor: "/auth/register", Handler: c.Register},
+		{Method: http.MethodPost, Path: "/auth/login", Successor: "/auth/login", Handler: c.Login},
+		{Method: http.MethodPost, Path: "/auth/verify-otp", Successor: "/auth/verify-otp", Handler: c.VerifyOTP},
+		{Method: http.MethodPost, Path: "/auth/forgot-password", Successor: "/auth/forgot-password", Handler: c.ForgotPassword},
+		{Method: http.MethodPost, Path: "/auth/reset-password", Successor: "/auth/reset-password", Handler: c.ResetPassword},
+		{Method: http.MethodGet, Path: "/auth/logout", SuccessorMethod: http.MethodPost, Successor: "/auth/logout", Handler: c.LogoutUser, Protected: true},
+	}
+}
+
+// OpenAPI documents the authentication endpoints.
+func (c *AuthController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/auth/register",
+			Tag:       "authentication",
+			Summary:   "Register a user and email a verification OTP",
+			Public:    true,
+			Body:      dto.RegisterRequest{},
+			Responses: map[int]any{http.StatusCreated: dto.MessageResponse{}},
+			Errors:    []int{http.StatusConflict},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/auth/login",
+			Tag:       "authentication",
+			Summary:   "Log in with an email or username",
+			Public:    true,
+			Body:      dto.LoginRequest{},
+			Responses: map[int]any{http.StatusOK: dto.LoginResponse{}},
+			Errors:    []int{http.StatusForbidden},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/auth/verify-otp",
+			Tag:       "authentication",
+			Summary:   "Verify the email address with the OTP",
+			Public:    true,
+			Body:      dto.VerifyOTPRequest{},
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/auth/forgot-password",
+			Tag:       "authentication",
+			Summary:   "Email an OTP for resetting the password",
+			Public:    true,
+			Body:      dto.ForgotPasswordRequest{},
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/auth/reset-password",
+			Tag:       "authentication",
+			Summary:   "Set a new password using the emailed OTP",
+			Public:    true,
+			Body:      dto.ResetPasswordRequest{},
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/auth/logout",
+			Tag:       "authentication",
+			Summary:   "Clear the session cookie",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+		},
+	}
 }
 
 // Register handles user registration and sends OTP.
 func (c *AuthController) Register(ctx *gin.Context) {
-	var payload struct {
-		Email    string `json:"email"`
-		Username string `json:"username"`
-		Password string `json:"password"`
-	}
+	var payload dto.RegisterRequest
 
-	// Bind the JSON body to the payload struct.
-	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	// Bind and validate the JSON body.
+	if err := dto.BindJSON(ctx, &payload); err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	// Register the user and send the OTP.
-	if err := c.AuthService.RegisterUserWithOTP(ctx, payload.Email, payload.Username, payload.Password); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.AuthService.RegisterUserWithOTP(ctx.Request.Context(), payload.Email, payload.Username, payload.Password); err != nil {
+		ctx.Error(err)
 		return
 	}
 
@@ -42,48 +125,35 @@ func (c *AuthController) Register(ctx *gin.Context) {
 
 // Login handles user login and returns a token and user ID.
 func (c *AuthController) Login(ctx *gin.Context) {
-	var payload struct {
-		EmailOrUsername string `json:"emailOrUsername" binding:"required"` // accept either
-		Password        string `json:"password" binding:"required"`
-	}
+	var payload dto.LoginRequest
 
 	// Bind JSON input
-	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	if err := dto.BindJSON(ctx, &payload); err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	// Call the service to log in the user and get a token and user ID
-	token, userID, err := c.AuthService.LoginUser(ctx, payload.EmailOrUsername, payload.Password)
+	token, userID, err := c.AuthService.LoginUser(ctx.Request.Context(), payload.EmailOrUsername, payload.Password)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	// Set token as a secure cookie (1 day expiry)
-	ctx.SetCookie("token", token, 3600*24, "/", c.Config.COOKIEDOMAIN, false, true)
+	// Start the browser session (1 day expiry)
+	csrf, err := c.Cookies.Start(ctx, token, 3600*24)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
 	// Return the token and user ID in the response
-	ctx.JSON(http.StatusOK, gin.H{
-		"token":  token,
-		"userID": userID,
-	})
+	ctx.JSON(http.StatusOK, dto.LoginResponse{Token: token, UserID: userID, CSRFToken: csrf})
 }
 
 func (c *AuthController) LogoutUser(ctx *gin.Context) {
-	// Load config
-	// cfg, err := config.LoadConfig(".")
-	// if err != nil {
-	// 	log.Printf("failed to load config: %v", err)
-	// 	ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Server configuration error"})
-	// 	return
-	// }
-
-	// Clear the token cookie by setting it with a negative expiry
-	ctx.SetCookie("token", "", -1, "/", c.Config.COOKIEDOMAIN, false, true)
-
-	// Log the message to the console
-	log.Println(`{"message": "Logged out successfully"}`)
+	// Clear the session cookies
+	c.Cookies.End(ctx)
 
 	// Return the response
 	ctx.JSON(http.StatusOK, gin.H{
@@ -93,20 +163,17 @@ func (c *AuthController) LogoutUser(ctx *gin.Context) {
 
 // VerifyOTP handles OTP verification.
 func (c *AuthController) VerifyOTP(ctx *gin.Context) {
-	var payload struct {
-		Email string `json:"email"`
-		OTP   string `json:"otp"`
-	}
+	var payload dto.VerifyOTPRequest
 
 	// Bind the request body.
-	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	if err := dto.BindJSON(ctx, &payload); err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	// Verify the OTP.
-	if err := c.AuthService.VerifyOTP(ctx, payload.Email, payload.OTP); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.AuthService.VerifyOTP(ctx.Request.Context(), payload.Email, payload.OTP); err != nil {
+		ctx.Error(err)
 		return
 	}
 
@@ -116,19 +183,17 @@ func (c *AuthController) VerifyOTP(ctx *gin.Context) {
 
 // ForgotPassword handles OTP generation for password reset.
 func (c *AuthController) ForgotPassword(ctx *gin.Context) {
-	var payload struct {
-		Email string `json:"email"`
-	}
+	var payload dto.ForgotPasswordRequest
 
 	// Bind the request body.
-	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	if err := dto.BindJSON(ctx, &payload); err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	// Generate OTP.
-	if err := c.AuthService.ForgotPassword(ctx, payload.Email); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.AuthService.ForgotPassword(ctx.Request.Context(), payload.Email); err != nil {
+		ctx.Error(err)
 		return
 	}
 
@@ -138,21 +203,17 @@ func (c *AuthController) ForgotPassword(ctx *gin.Context) {
 
 // ResetPassword handles password reset.
 func (c *AuthController) ResetPassword(ctx *gin.Context) {
-	var payload struct {
-		Email       string `json:"email"`
-		OTP         string `json:"otp"`
-		NewPassword string `json:"new_password"`
-	}
+	var payload dto.ResetPasswordRequest
 
 	// Bind the request body.
-	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	if err := dto.BindJSON(ctx, &payload); err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	// Reset the password.
-	if err := c.AuthService.ResetPassword(ctx, payload.Email, payload.OTP, payload.NewPassword); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.AuthService.ResetPassword(ctx.Request.Context(), payload.Email, payload.OTP, payload.NewPassword); err != nil {
+		ctx.Error(err)
 		return
 	}
 
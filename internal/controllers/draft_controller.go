@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+type DraftController struct {
+	DraftService *services.DraftService
+}
+
+// RegisterRoutes mounts the draft and scheduled post endpoints.
+func (c *DraftController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/drafts", c.SaveDraft)
+	protected.GET("/drafts", c.ListDrafts)
+	protected.GET("/drafts/:draft_id", c.GetDraft)
+	protected.PUT("/drafts/:draft_id", c.UpdateDraft)
+	protected.DELETE("/drafts/:draft_id", c.DeleteDraft)
+	protected.POST("/drafts/:draft_id/publish", c.PublishDraft)
+}
+
+// OpenAPI documents the draft and scheduled post endpoints.
+func (c *DraftController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/drafts",
+			Tag:       "posts",
+			Summary:   "Save a draft, or schedule a post with a future publish_at; you are notified when a scheduled post is published",
+			Body:      dto.SaveDraftRequest{},
+			Responses: map[int]any{http.StatusCreated: models.PostDraft{}},
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/drafts",
+			Tag:     "posts",
+			Summary: "List your drafts or your scheduled posts, newest first",
+			Query: append([]openapi.Param{
+				{Name: "scheduled", Description: "true to list your scheduled posts instead of your drafts", Type: "boolean"},
+			}, pageQuery...),
+			Responses: map[int]any{http.StatusOK: dto.DraftPage{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/drafts/:draft_id",
+			Tag:       "posts",
+			Summary:   "Get one of your drafts or scheduled posts",
+			Responses: map[int]any{http.StatusOK: models.PostDraft{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPut,
+			Path:      "/drafts/:draft_id",
+			Tag:       "posts",
+			Summary:   "Replace one of your drafts or scheduled posts; set publish_at to schedule it or leave it out to keep it as a draft",
+			Body:      dto.SaveDraftRequest{},
+			Responses: map[int]any{http.StatusOK: models.PostDraft{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodDelete,
+			Path:      "/drafts/:draft_id",
+			Tag:       "posts",
+			Summary:   "Discard one of your drafts or cancel a scheduled post",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/drafts/:draft_id/publish",
+			Tag:       "posts",
+			Summary:   "Publish one of your drafts or scheduled posts right away",
+			Responses: map[int]any{http.StatusCreated: dto.PostView{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+	}
+}
+
+func (c *DraftController) SaveDraft(ctx *gin.Context) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var input dto.SaveDraftRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	draft, err := c.DraftService.SaveDraft(ctx.Request.Context(), input.Draft(userID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, draft)
+}
+
+func (c *DraftController) ListDrafts(ctx *gin.Context) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var scheduled bool
+	switch ctx.DefaultQuery("scheduled", "false") {
+	case "true":
+		scheduled = true
+	case "false":
+	default:
+		ctx.Error(apperrors.InvalidField("scheduled", "must be true or false"))
+		return
+	}
+
+	page, err := pageParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	drafts, err := c.DraftService.ListDrafts(ctx.Request.Context(), userID, scheduled, page)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.NewDraftPage(drafts))
+}
+
+func (c *DraftController) GetDraft(ctx *gin.Context) {
+	userID, draftID, err := draftParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	draft, err := c.DraftService.GetDraft(ctx.Request.Context(), userID, draftID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, draft)
+}
+
+func (c *DraftController) UpdateDraft(ctx *gin.Context) {
+	userID, draftID, err := draftParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var input dto.SaveDraftRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	changes := input.Draft(userID)
+	changes.ID = draftID
+	draft, err := c.DraftService.UpdateDraft(ctx.Request.Context(), userID, changes)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, draft)
+}
+
+func (c *DraftController) DeleteDraft(ctx *gin.Context) {
+	userID, draftID, err := draftParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := c.DraftService.DeleteDraft(ctx.Request.Context(), userID, draftID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Draft deleted successfully"})
+}
+
+func (c *DraftController) PublishDraft(ctx *gin.Context) {
+	userID, draftID, err := draftParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	post, err := c.DraftService.PublishDraft(ctx.Request.Context(), userID, draftID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.NewPostView(post, viewer(ctx)))
+}
+
+// draftParams reads the current user and the draft they act on.
+func draftParams(ctx *gin.Context) (userID, draftID uuid.UUID, err error) {
+	if userID, err = currentUserID(ctx); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if draftID, err = uuid.Parse(ctx.Param("draft_id")); err != nil {
+		return uuid.Nil, uuid.Nil, apperrors.InvalidField("draft_id", "must be a valid UUID")
+	}
+	return userID, draftID, nil
+}
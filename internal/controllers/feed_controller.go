@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+// Modes of the home feed.
+const (
+	FeedModeLatest = "latest"
+	FeedModeTop    = "top"
+)
+
+type FeedController struct {
+	FeedService *services.FeedService
+
+	// IsAdmin reports whether a user may see the ranking scores of the top
+	// feed. Nobody may when it is nil.
+	IsAdmin func(userID string) bool
+}
+
+// RegisterRoutes mounts the home feed endpoint.
+func (c *FeedController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.GET("/feed", c.GetHomeFeed)
+}
+
+// OpenAPI documents the home feed endpoint.
+func (c *FeedController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:  http.MethodGet,
+			Path:    "/feed",
+			Tag:     "posts",
+			Summary: "List posts of the current user and the people they follow, newest first or best first, or popular posts while there are none",
+			Query: append([]openapi.Param{
+				{Name: "mode", Description: "latest (default) to page through posts newest first, top for the best posts by ranking score on a single page"},
+				{Name: "debug", Description: "true to include the ranking score of every post of a top feed; admins only", Type: "boolean"},
+			}, pageQuery...),
+			Responses: map[int]any{http.StatusOK: dto.HomeFeedPage{}},
+			Errors:    []int{http.StatusForbidden},
+		},
+	}
+}
+
+func (c *FeedController) GetHomeFeed(ctx *gin.Context) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	page, err := pageParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	switch mode := ctx.DefaultQuery("mode", FeedModeLatest); mode {
+	case FeedModeLatest:
+		feed, err := c.FeedService.Home(ctx.Request.Context(), userID, page)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, dto.HomeFeedPage{FeedPage: dto.NewFeedPage(feed.Page), Source: feed.Source})
+
+	case FeedModeTop:
+		if page.After != nil {
+			ctx.Error(apperrors.InvalidField("cursor", "The top feed is a single page"))
+			return
+		}
+		debug := ctx.Query("debug") == "true"
+		if debug && (c.IsAdmin == nil || !c.IsAdmin(userID.String())) {
+			ctx.Error(apperrors.Forbidden("admin_only", "Only admins may see ranking scores"))
+			return
+		}
+
+		feed, err := c.FeedService.Top(ctx.Request.Context(), userID, page.Limit)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		res := dto.HomeFeedPage{FeedPage: dto.NewFeedPage(feed.Page), Source: feed.Source, Ranker: feed.Ranker}
+		if debug {
+			res = res.WithScores(feed.Scores)
+		}
+		ctx.JSON(http.StatusOK, res)
+
+	default:
+		ctx.Error(apperrors.InvalidField("mode", "Mode must be latest or top"))
+	}
+}
@@ -5,7 +5,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
 )
 
@@ -13,26 +17,79 @@ type FollowController struct {
 	FollowService *services.FollowService
 }
 
+// RegisterRoutes mounts the follow graph endpoints.
+func (controller *FollowController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/users/:user_id/followers", controller.FollowUser)
+	protected.DELETE("/users/:user_id/followers", controller.UnfollowUser)
+	protected.GET("/users/:user_id/followers", controller.GetFollowers)
+	protected.GET("/users/:user_id/followings", controller.GetFollowings)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (controller *FollowController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/user/:user_id/follow", Successor: "/users/:user_id/followers", Handler: controller.FollowUser, Protected: true},
+		{Method: http.MethodPost, Path: "/user/:user_id/unfollow", SuccessorMethod: http.MethodDelete, Successor: "/users/:user_id/followers", Handler: controller.UnfollowUser, Protected: true},
+		{Method: http.MethodGet, Path: "/user/:user_id/followers", Successor: "/users/:user_id/followers", Handler: controller.GetFollowers, Protected: true},
+		{Method: http.MethodGet, Path: "/user/:user_id/followings", Successor: "/users/:user_id/followings", Handler: controller.GetFollowings, Protected: true},
+	}
+}
+
+// OpenAPI documents the follow graph endpoints.
+func (controller *FollowController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/users/:user_id/followers",
+			Tag:       "follows",
+			Summary:   "Follow a user as the current user",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodDelete,
+			Path:      "/users/:user_id/followers",
+			Tag:       "follows",
+			Summary:   "Unfollow a user as the current user",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/users/:user_id/followers",
+			Tag:       "follows",
+			Summary:   "List the ids of a user's followers",
+			Responses: map[int]any{http.StatusOK: dto.FollowersResponse{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/users/:user_id/followings",
+			Tag:       "follows",
+			Summary:   "List the ids of the users a user follows",
+			Responses: map[int]any{http.StatusOK: dto.FollowingsResponse{}},
+		},
+	}
+}
+
 // FollowUser handles the request for a user to follow another user
 func (controller *FollowController) FollowUser(ctx *gin.Context) {
 	user := ctx.MustGet("user").(models.User)
 
 	followerID, err := uuid.Parse(user.ID) // Convert string to uuid.UUID
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	followedIDStr := ctx.Param("followed_id")
+	followedIDStr := ctx.Param("user_id")
 	followedID, err := uuid.Parse(followedIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid followed user ID"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	err = controller.FollowService.FollowUser(followerID, followedID)
+	err = controller.FollowService.FollowUser(ctx.Request.Context(), followerID, followedID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -44,20 +101,20 @@ func (controller *FollowController) UnfollowUser(ctx *gin.Context) {
 
 	followerID, err := uuid.Parse(user.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	followedIDStr := ctx.Param("followed_id")
+	followedIDStr := ctx.Param("user_id")
 	followedID, err := uuid.Parse(followedIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid followed user ID"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	err = controller.FollowService.UnfollowUser(followerID, followedID)
+	err = controller.FollowService.UnfollowUser(ctx.Request.Context(), followerID, followedID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -69,17 +126,23 @@ func (controller *FollowController) GetFollowers(ctx *gin.Context) {
 	userIDStr := ctx.Param("user_id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	followers, err := controller.FollowService.GetFollowers(userID)
+	page, err := pageParams(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"followers": followers})
+	followers, err := controller.FollowService.GetFollowers(ctx.Request.Context(), userID, page)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.FollowersResponse{Followers: followers.Items, NextCursor: followers.NextCursor})
 }
 
 // GetFollowings handles the request to get all users a user is following
@@ -87,15 +150,21 @@ func (controller *FollowController) GetFollowings(ctx *gin.Context) {
 	userIDStr := ctx.Param("user_id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
+		return
+	}
+
+	page, err := pageParams(ctx)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	followings, err := controller.FollowService.GetFollowings(userID)
+	followings, err := controller.FollowService.GetFollowings(ctx.Request.Context(), userID, page)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"followings": followings})
+	ctx.JSON(http.StatusOK, dto.FollowingsResponse{Followings: followings.Items, NextCursor: followings.NextCursor})
 }
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/entities"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+// Bounds of the trending hashtags query.
+const (
+	defaultTrendingHours = 24
+	maxTrendingHours     = 7 * 24
+	defaultTrendingLimit = 10
+	maxTrendingLimit     = 50
+)
+
+type HashtagController struct {
+	EntityService *services.EntityService
+}
+
+// RegisterRoutes mounts the hashtag endpoints.
+func (c *HashtagController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.GET("/hashtags/trending", c.GetTrending)
+	protected.GET("/hashtags/:tag/posts", c.GetPostsByHashtag)
+}
+
+// OpenAPI documents the hashtag endpoints.
+func (c *HashtagController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodGet,
+			Path:      "/hashtags/:tag/posts",
+			Tag:       "posts",
+			Summary:   "List the posts using a hashtag, newest first; the tag is matched case-insensitively, with or without its #",
+			Query:     pageQuery,
+			Responses: map[int]any{http.StatusOK: dto.FeedPage{}},
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/hashtags/trending",
+			Tag:     "posts",
+			Summary: "List the hashtags used by the most posts and comments over a recent window, most used first",
+			Query: []openapi.Param{
+				{Name: "window", Description: fmt.Sprintf("Length of the window in hours, 1 to %d; defaults to %d", maxTrendingHours, defaultTrendingHours), Type: "integer"},
+				{Name: "limit", Description: fmt.Sprintf("Maximum number of hashtags, 1 to %d; defaults to %d", maxTrendingLimit, defaultTrendingLimit), Type: "integer"},
+			},
+			Responses: map[int]any{http.StatusOK: dto.TrendingHashtagsResponse{}},
+		},
+	}
+}
+
+func (c *HashtagController) GetPostsByHashtag(ctx *gin.Context) {
+	tag := entities.NormalizeTag(ctx.Param("tag"))
+	if tag == "" {
+		ctx.Error(apperrors.InvalidField("tag", "must be a hashtag"))
+		return
+	}
+
+	page, err := pageParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	posts, err := c.EntityService.PostsByHashtag(ctx.Request.Context(), viewerID(ctx), tag, page)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.NewFeedPage(posts))
+}
+
+func (c *HashtagController) GetTrending(ctx *gin.Context) {
+	hours, err := intQuery(ctx, "window", defaultTrendingHours, maxTrendingHours)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	limit, err := intQuery(ctx, "limit", defaultTrendingLimit, maxTrendingLimit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	trending, err := c.EntityService.Trending(ctx.Request.Context(), time.Duration(hours)*time.Hour, limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.TrendingHashtagsResponse{WindowHours: hours, Hashtags: trending})
+}
+
+// intQuery reads an integer query parameter between 1 and max, def when it
+// is absent.
+func intQuery(ctx *gin.Context, name string, def, max int) (int, error) {
+	s := ctx.Query(name)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > max {
+		return 0, apperrors.InvalidField(name, fmt.Sprintf("must be between 1 and %d", max))
+	}
+	return n, nil
+}
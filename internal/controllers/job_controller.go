@@ -1,14 +1,14 @@
 package controllers
 
 import (
-	"context"
-	"log"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
 )
 
@@ -16,57 +16,60 @@ type JobController struct {
 	JobService *services.JobService
 }
 
-func (jc *JobController) CreateJobPost(ctx *gin.Context) {
-	var input struct {
-		UserID          string `json:"user_id" binding:"required"`
-		JobTitle        string `json:"job_title" binding:"required"`
-		CompanyName     string `json:"company_name" binding:"required"`
-		JobDescription  string `json:"job_description" binding:"required"`
-		JobApplyURL     string `json:"job_apply_url"`
-		Location        string `json:"location"`
-		LastDateToApply string `json:"last_date_to_apply"` // Expecting format: YYYY-MM-DD
+// RegisterRoutes mounts the job post endpoints.
+func (jc *JobController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/jobs", jc.CreateJobPost)
+	protected.GET("/jobs", jc.GetAllJobPosts)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (jc *JobController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/posts/job", Successor: "/jobs", Handler: jc.CreateJobPost, Protected: true},
+		{Method: http.MethodGet, Path: "/posts/all-job", Successor: "/jobs", Handler: jc.GetAllJobPosts, Protected: true},
 	}
+}
 
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
-		return
+// OpenAPI documents the job post endpoints.
+func (jc *JobController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/jobs",
+			Tag:       "jobs",
+			Summary:   "Publish a job post",
+			Body:      dto.CreateJobPostRequest{},
+			Responses: map[int]any{http.StatusCreated: dto.MessageResponse{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/jobs",
+			Tag:       "jobs",
+			Summary:   "List job posts, newest first",
+			Query:     pageQuery,
+			Responses: map[int]any{http.StatusOK: dto.JobPage{}},
+		},
 	}
+}
 
-	userID, err := uuid.Parse(input.UserID)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+func (jc *JobController) CreateJobPost(ctx *gin.Context) {
+	var input dto.CreateJobPostRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	var lastDate time.Time
-	if input.LastDateToApply != "" {
-		lastDate, err = time.Parse("2006-01-02", input.LastDateToApply)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
-			return
-		}
-	} else {
-		lastDate = time.Now().AddDate(0, 1, 0) // Default 1 month
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
 
-	now := time.Now()
-
-	jobPost := &models.JobPost{
-		UserID:          userID,
-		JobTitle:        input.JobTitle,
-		CompanyName:     input.CompanyName,
-		JobDescription:  input.JobDescription,
-		JobApplyURL:     input.JobApplyURL,
-		Location:        input.Location,
-		LastDateToApply: lastDate,
-		PostDate:        now,
-		CreatedAt:       now,
-	}
+	jobPost := input.JobPost(userID, time.Now())
 
-	_, err = jc.JobService.CreateJobPost(context.Background(), jobPost)
+	_, err = jc.JobService.CreateJobPost(ctx.Request.Context(), jobPost)
 	if err != nil {
-		log.Printf("CreateJobPost error: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job post"})
+		ctx.Error(fmt.Errorf("failed to create job post: %w", err))
 		return
 	}
 
@@ -74,11 +77,17 @@ func (jc *JobController) CreateJobPost(ctx *gin.Context) {
 }
 
 func (jc *JobController) GetAllJobPosts(ctx *gin.Context) {
-	jobPosts, err := jc.JobService.GetAllJobPosts(context.Background())
+	page, err := pageParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	jobPosts, err := jc.JobService.GetAllJobPosts(ctx.Request.Context(), page)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job posts"})
+		ctx.Error(fmt.Errorf("failed to retrieve job posts: %w", err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, jobPosts)
+	ctx.JSON(http.StatusOK, dto.NewJobPage(jobPosts))
 }
@@ -1,11 +1,15 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
 )
 
@@ -17,16 +21,51 @@ func NewNotificationController(service *services.NotificationService) *Notificat
 	return &NotificationController{NotificationService: service}
 }
 
-func (c *NotificationController) CreateNotification(ctx *gin.Context) {
-	var notif models.Notification
+// RegisterRoutes mounts the notification endpoints.
+func (c *NotificationController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/notifications", c.CreateNotification)
+	protected.GET("/users/:user_id/notifications", c.GetNotifications)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (c *NotificationController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/notifications/create", Successor: "/notifications", Handler: c.CreateNotification, Protected: true},
+		{Method: http.MethodGet, Path: "/notifications/:user_id", Successor: "/users/:user_id/notifications", Handler: c.GetNotifications, Protected: true},
+	}
+}
 
-	if err := ctx.ShouldBindJSON(&notif); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+// OpenAPI documents the notification endpoints.
+func (c *NotificationController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/notifications",
+			Tag:       "notifications",
+			Summary:   "Create a notification",
+			Body:      dto.CreateNotificationRequest{},
+			Responses: map[int]any{http.StatusCreated: dto.MessageResponse{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/users/:user_id/notifications",
+			Tag:       "notifications",
+			Summary:   "List a user's notifications, newest first",
+			Query:     pageQuery,
+			Responses: map[int]any{http.StatusOK: dto.NotificationPage{}},
+		},
+	}
+}
+
+func (c *NotificationController) CreateNotification(ctx *gin.Context) {
+	var input dto.CreateNotificationRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	if err := c.NotificationService.CreateNotification(ctx, &notif); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create notification"})
+	if err := c.NotificationService.CreateNotification(ctx.Request.Context(), input.Notification()); err != nil {
+		ctx.Error(fmt.Errorf("failed to create notification: %w", err))
 		return
 	}
 
@@ -37,20 +76,21 @@ func (c *NotificationController) GetNotifications(ctx *gin.Context) {
 	userIDStr := ctx.Param("user_id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	notifications, err := c.NotificationService.GetNotificationsForUser(ctx, userID)
+	page, err := pageParams(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		ctx.Error(err)
 		return
 	}
 
-	if len(notifications) == 0 {
-		ctx.JSON(http.StatusOK, gin.H{"message": "No notifications found"})
+	notifications, err := c.NotificationService.GetNotificationsForUser(ctx.Request.Context(), userID, page)
+	if err != nil {
+		ctx.Error(fmt.Errorf("failed to fetch notifications: %w", err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, notifications)
+	ctx.JSON(http.StatusOK, dto.NewNotificationPage(notifications))
 }
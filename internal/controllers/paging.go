@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+)
+
+// pageQuery documents the query parameters read by pageParams.
+var pageQuery = []openapi.Param{
+	{Name: "limit", Description: "Maximum number of items to return", Type: "integer"},
+	{Name: "cursor", Description: "next_cursor of the previous page"},
+}
+
+// pageParams reads the page requested by the limit and cursor query
+// parameters.
+func pageParams(ctx *gin.Context) (pagination.Params, error) {
+	return pagination.Parse(ctx.Query("limit"), ctx.Query("cursor"))
+}
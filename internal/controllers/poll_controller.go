@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+type PollController struct {
+	PollService *services.PollService
+}
+
+// RegisterRoutes mounts the poll endpoints.
+func (c *PollController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/polls", c.CreatePoll)
+	protected.GET("/posts/:post_id/poll", c.GetPoll)
+	protected.POST("/posts/:post_id/poll/votes", c.Vote)
+}
+
+// OpenAPI documents the poll endpoints.
+func (c *PollController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/polls",
+			Tag:       "posts",
+			Summary:   "Publish a poll post asking a question with 2 to 4 options until ends_at; you are notified when it ends. Without a visibility the poll gets your profile's default_visibility",
+			Body:      dto.CreatePollRequest{},
+			Responses: map[int]any{http.StatusCreated: dto.PostView{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/posts/:post_id/poll",
+			Tag:       "posts",
+			Summary:   "Get the poll of a post; the votes of its options are shown once you voted or the poll ended",
+			Responses: map[int]any{http.StatusOK: dto.PollView{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/posts/:post_id/poll/votes",
+			Tag:       "posts",
+			Summary:   "Vote once in a poll, for one option or several in a multiple choice poll, and see its results",
+			Body:      dto.VoteRequest{},
+			Responses: map[int]any{http.StatusCreated: dto.PollView{}},
+			Errors:    []int{http.StatusNotFound, http.StatusConflict},
+		},
+	}
+}
+
+func (c *PollController) CreatePoll(ctx *gin.Context) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var input dto.CreatePollRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	post, err := c.PollService.CreatePoll(ctx.Request.Context(), input.ContentPost(userID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.NewPostView(post, viewer(ctx)))
+}
+
+func (c *PollController) GetPoll(ctx *gin.Context) {
+	userID, postID, err := shareParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	poll, err := c.PollService.GetPoll(ctx.Request.Context(), userID, postID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.NewPollView(poll, time.Now()))
+}
+
+func (c *PollController) Vote(ctx *gin.Context) {
+	userID, postID, err := shareParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var input dto.VoteRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	poll, err := c.PollService.Vote(ctx.Request.Context(), userID, postID, input.Options())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.NewPollView(poll, time.Now()))
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// maxAttachments is the number of files a post may carry.
+const maxAttachments = 10
+
+// attachmentTypes lists the MIME types accepted as post attachments.
+var attachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"video/mp4":       true,
+	"video/quicktime": true,
+	"video/webm":      true,
+	"application/pdf": true,
+}
+
+// attachmentFiles returns the files of a new post in order: the legacy
+// single media_url file first, then the attachments fields.
+func attachmentFiles(ctx *gin.Context) ([]*multipart.FileHeader, error) {
+	if ctx.ContentType() != binding.MIMEMultipartPOSTForm {
+		// Posts without files may be sent urlencoded.
+		return nil, nil
+	}
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return nil, apperrors.InvalidField("attachments", "must be a valid multipart form")
+	}
+	files := append(append([]*multipart.FileHeader(nil), form.File["media_url"]...), form.File["attachments"]...)
+	if len(files) > maxAttachments {
+		return nil, apperrors.InvalidField("attachments", fmt.Sprintf("at most %d files are allowed", maxAttachments))
+	}
+	return files, nil
+}
+
+// uploadAttachments checks the type of every file, reads the dimensions of
+// images and the duration and frame size of MP4 and QuickTime videos, and
+// uploads them. Alt texts are taken from the form in the same order as the
+// files. When a file is rejected or fails to upload, the ones uploaded
+// before it are removed again.
+func (pc *PostController) uploadAttachments(ctx *gin.Context, userID uuid.UUID, input dto.CreatePostRequest, files []*multipart.FileHeader) ([]models.PostAttachment, error) {
+	attachments := make([]models.PostAttachment, 0, len(files))
+	for i, fh := range files {
+		a, err := pc.uploadAttachment(ctx, userID, fh)
+		if err != nil {
+			pc.discardAttachments(ctx, attachments)
+			return nil, err
+		}
+		if i < len(input.AltText) {
+			a.AltText = strings.TrimSpace(input.AltText[i])
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, nil
+}
+
+// discardAttachments removes the uploaded files of a post that was not
+// created. Files that could not be removed are only logged, to be cleaned up
+// by hand.
+func (pc *PostController) discardAttachments(ctx *gin.Context, attachments []models.PostAttachment) {
+	reqCtx := ctx.Request.Context()
+	for _, a := range attachments {
+		if err := pc.Uploader.DeleteFile(reqCtx, a.URL); err != nil {
+			logging.FromContext(reqCtx).WarnContext(reqCtx, "deleting post media failed", "url", a.URL, "error", err)
+		}
+	}
+}
+
+func (pc *PostController) uploadAttachment(ctx *gin.Context, userID uuid.UUID, fh *multipart.FileHeader) (models.PostAttachment, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return models.PostAttachment{}, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	defer file.Close()
+
+	mimeType, err := sniffType(file, fh)
+	if err != nil {
+		return models.PostAttachment{}, err
+	}
+	a := models.PostAttachment{MimeType: mimeType, SizeBytes: fh.Size}
+	switch mimeType {
+	case "video/mp4", "video/quicktime":
+		if meta, ok := readVideoMeta(file, fh.Size); ok {
+			a.DurationSeconds = &meta.DurationSeconds
+			if meta.Width > 0 && meta.Height > 0 {
+				a.Width, a.Height = &meta.Width, &meta.Height
+			}
+		}
+	default:
+		if cfg, _, err := image.DecodeConfig(file); err == nil {
+			a.Width, a.Height = &cfg.Width, &cfg.Height
+		}
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return models.PostAttachment{}, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	fh.Header.Set("Content-Type", mimeType)
+	key := fmt.Sprintf("post-media/%s_%d_%s", userID, time.Now().UnixNano(), fh.Filename)
+	a.URL, err = pc.Uploader.UploadFile(ctx.Request.Context(), file, fh, key)
+	if err != nil {
+		return models.PostAttachment{}, fmt.Errorf("failed to upload media to S3: %w", err)
+	}
+
+	logging.FromContext(ctx.Request.Context()).InfoContext(ctx.Request.Context(), "post media uploaded", "key", key, "mime_type", mimeType)
+	return a, nil
+}
+
+// sniffType detects the MIME type of a file from its content, trusting the
+// declared type only where content sniffing cannot tell, and rejects types
+// that are not accepted. It leaves the file at its start.
+func sniffType(file multipart.File, fh *multipart.FileHeader) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	mimeType, _, _ := strings.Cut(http.DetectContentType(head[:n]), ";")
+	if mimeType == "application/octet-stream" {
+		mimeType, _, _ = strings.Cut(fh.Header.Get("Content-Type"), ";")
+	}
+	if !attachmentTypes[mimeType] {
+		return "", apperrors.InvalidField("attachments", fmt.Sprintf("%s: only images, videos and PDF documents can be attached", fh.Filename))
+	}
+	return mimeType, nil
+}
@@ -5,7 +5,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
 )
 
@@ -13,47 +17,79 @@ type PostCommentController struct {
 	PostCommentService *services.PostCommentService
 }
 
+// RegisterRoutes mounts the post comment endpoints.
+func (controller *PostCommentController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/posts/:post_id/comments", controller.CommentOnPost)
+	protected.GET("/posts/:post_id/comments", controller.GetPostComments)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (controller *PostCommentController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/post/:post_id/comment", Successor: "/posts/:post_id/comments", Handler: controller.CommentOnPost, Protected: true},
+		{Method: http.MethodGet, Path: "/post/:post_id/comments", Successor: "/posts/:post_id/comments", Handler: controller.GetPostComments, Protected: true},
+	}
+}
+
+// OpenAPI documents the post comment endpoints.
+func (controller *PostCommentController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/posts/:post_id/comments",
+			Tag:       "posts",
+			Summary:   "Comment on a post as the current user",
+			Body:      dto.CreateCommentRequest{},
+			Responses: map[int]any{http.StatusCreated: dto.MessageResponse{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/posts/:post_id/comments",
+			Tag:       "posts",
+			Summary:   "List the comments on a post, newest first",
+			Query:     pageQuery,
+			Responses: map[int]any{http.StatusOK: dto.CommentsResponse{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+	}
+}
+
 func (controller *PostCommentController) CommentOnPost(ctx *gin.Context) {
 	user, ok := ctx.Get("user")
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		ctx.Error(apperrors.Unauthorized("not_logged_in", "User not authenticated"))
 		return
 	}
 
 	usr, ok := user.(models.User)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user data"})
+		ctx.Error(apperrors.Unauthorized("invalid_token", "Invalid user data"))
 		return
 	}
 
 	userID, err := uuid.Parse(usr.ID)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperrors.Unauthorized("invalid_token", "Invalid user ID"))
 		return
 	}
 
 	postIDStr := ctx.Param("post_id")
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		ctx.Error(apperrors.InvalidField("post_id", "must be a valid UUID"))
 		return
 	}
 
-	var input struct {
-		Comment string `json:"comment" binding:"required,min=1,max=500"`
-	}
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var input dto.CreateCommentRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	err = controller.PostCommentService.CommentOnPost(userID, postID, input.Comment)
+	err = controller.PostCommentService.CommentOnPost(ctx.Request.Context(), userID, postID, input.Comment)
 	if err != nil {
-		if err.Error() == "post not found" {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		ctx.Error(err)
 		return
 	}
 
@@ -64,25 +100,21 @@ func (controller *PostCommentController) GetPostComments(ctx *gin.Context) {
 	postIDStr := ctx.Param("post_id")
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		ctx.Error(apperrors.InvalidField("post_id", "must be a valid UUID"))
 		return
 	}
 
-	exists, err := controller.PostCommentService.PostCommentRepository.PostExists(postID)
+	page, err := pageParams(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking post existence"})
-		return
-	}
-	if !exists {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		ctx.Error(err)
 		return
 	}
 
-	comments, err := controller.PostCommentService.GetPostComments(postID)
+	comments, err := controller.PostCommentService.GetPostComments(ctx.Request.Context(), viewerID(ctx), postID, page)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"comments": comments})
+	ctx.JSON(http.StatusOK, dto.CommentsResponse{Comments: comments.Items, NextCursor: comments.NextCursor})
 }
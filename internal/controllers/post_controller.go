@@ -1,146 +1,345 @@
 package controllers
 
 import (
-	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/sagar-rathod-devops/do-host-network-backend/config"
-	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
-	"github.com/sagar-rathod-devops/do-host-network-backend/utils"
 )
 
 type PostController struct {
 	PostService *services.PostService
+	Uploader    Uploader
+
+	// IsAdmin reports whether a user may delete the posts of others.
+	// Nobody may when it is nil.
+	IsAdmin func(userID string) bool
 }
 
-func NewPostController(service *services.PostService) *PostController {
+func NewPostController(service *services.PostService, uploader Uploader) *PostController {
 	return &PostController{
 		PostService: service,
+		Uploader:    uploader,
+	}
+}
+
+// RegisterRoutes mounts the content post endpoints.
+func (pc *PostController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/posts", pc.CreatePost)
+	protected.GET("/users/:user_id/posts", pc.GetPostsByUserID)
+	protected.GET("/posts", pc.GetAllContentPosts)
+	protected.PATCH("/posts/:post_id", pc.UpdatePost)
+	protected.DELETE("/posts/:post_id", pc.DeletePost)
+	protected.PUT("/posts/:post_id/visibility", pc.SetVisibility)
+	protected.GET("/posts/:post_id/revisions", pc.GetRevisions)
+	protected.POST("/posts/:post_id/reposts", pc.Repost)
+	protected.DELETE("/posts/:post_id/reposts", pc.Unrepost)
+	protected.POST("/posts/:post_id/quotes", pc.Quote)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (pc *PostController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/posts/content", Successor: "/posts", Handler: pc.CreatePost, Protected: true},
+		{Method: http.MethodGet, Path: "/posts/user/:user_id", Successor: "/users/:user_id/posts", Handler: pc.GetPostsByUserID, Protected: true},
+		{Method: http.MethodGet, Path: "/posts/all-content", Successor: "/posts", Handler: pc.GetAllContentPosts, Protected: true},
+	}
+}
+
+// OpenAPI documents the content post endpoints.
+func (pc *PostController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:  http.MethodPost,
+			Path:    "/posts",
+			Tag:     "posts",
+			Summary: "Publish a post with up to 10 images, videos or PDF documents; repeat attachments and alt_text once per file. The size, image and video dimensions and MP4 or QuickTime durations of attachments are read from the files themselves. Without a visibility the post gets your profile's default_visibility",
+			Form:    dto.CreatePostRequest{}, Files: []string{"attachments", "media_url"},
+			Responses: map[int]any{http.StatusCreated: dto.PostCreatedResponse{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/users/:user_id/posts",
+			Tag:       "posts",
+			Summary:   "List the posts of a user you can see, newest first",
+			Query:     pageQuery,
+			Responses: map[int]any{http.StatusOK: dto.PostPage{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/posts",
+			Tag:       "posts",
+			Summary:   "List the posts you can see with author details and counts, newest first",
+			Query:     pageQuery,
+			Responses: map[int]any{http.StatusOK: dto.FeedPage{}},
+		},
+		{
+			Method:    http.MethodPatch,
+			Path:      "/posts/:post_id",
+			Tag:       "posts",
+			Summary:   "Edit the text of one of your posts; the previous text is kept as a revision",
+			Body:      dto.UpdatePostRequest{},
+			Responses: map[int]any{http.StatusOK: dto.PostView{}},
+			Errors:    []int{http.StatusForbidden, http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodDelete,
+			Path:      "/posts/:post_id",
+			Tag:       "posts",
+			Summary:   "Delete one of your posts, or any post as an admin, with its likes, comments and media",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+			Errors:    []int{http.StatusForbidden, http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPut,
+			Path:      "/posts/:post_id/visibility",
+			Tag:       "posts",
+			Summary:   "Change who can see one of your posts: everyone, your followers or only you",
+			Body:      dto.SetVisibilityRequest{},
+			Responses: map[int]any{http.StatusOK: dto.PostView{}},
+			Errors:    []int{http.StatusForbidden, http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/posts/:post_id/revisions",
+			Tag:       "posts",
+			Summary:   "List the earlier versions of an edited post, latest first",
+			Query:     pageQuery,
+			Responses: map[int]any{http.StatusOK: dto.RevisionPage{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/posts/:post_id/reposts",
+			Tag:       "posts",
+			Summary:   "Share a public post with your followers as is; reposting a repost shares its original",
+			Responses: map[int]any{http.StatusCreated: dto.PostView{}},
+			Errors:    []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			Method:    http.MethodDelete,
+			Path:      "/posts/:post_id/reposts",
+			Tag:       "posts",
+			Summary:   "Undo your repost of a post",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/posts/:post_id/quotes",
+			Tag:       "posts",
+			Summary:   "Share a public post with your followers together with your own commentary",
+			Body:      dto.QuotePostRequest{},
+			Responses: map[int]any{http.StatusCreated: dto.PostView{}},
+			Errors:    []int{http.StatusForbidden, http.StatusNotFound},
+		},
 	}
 }
 
 func (pc *PostController) CreatePost(ctx *gin.Context) {
-	fmt.Println("🚀 Received request to create post")
+	// 1. Parse and validate form-data fields
+	var input dto.CreatePostRequest
+	if err := dto.BindForm(ctx, &input); err != nil {
+		ctx.Error(err)
+		return
+	}
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
-	// 1. Parse form-data fields
-	userIDStr := ctx.PostForm("user_id")
-	postContent := ctx.PostForm("post_content")
+	// 2. Get uploaded attachments (optional)
+	files, err := attachmentFiles(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
-	if strings.TrimSpace(userIDStr) == "" || strings.TrimSpace(postContent) == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user_id and post_content are required"})
+	// 3. Check their types and upload them
+	attachments, err := pc.uploadAttachments(ctx, userID, input, files)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	// 2. Parse user ID to UUID
-	userID, err := uuid.Parse(userIDStr)
+	// 4. Create post model
+	post := input.ContentPost(userID, attachments)
+
+	// 5. Save post to DB, removing the uploaded media if that fails
+	createdPost, err := pc.PostService.CreatePost(ctx.Request.Context(), post)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id format"})
+		pc.discardAttachments(ctx, attachments)
+		ctx.Error(fmt.Errorf("failed to create post: %w", err))
 		return
 	}
 
-	// 3. Get uploaded media file (optional)
-	fileHeader, err := ctx.FormFile("media_url")
-	mediaURL := "" // string, default empty
+	// 6. Prepare response
+	ctx.JSON(http.StatusCreated, dto.PostCreatedResponse{
+		Message:     "Post created successfully",
+		PostContent: createdPost.PostContent,
+		MediaURL:    createdPost.MediaURL,
+		Visibility:  createdPost.Visibility,
+		Attachments: dto.NewAttachmentViews(createdPost.Attachments),
+		Entities:    dto.NewEntities(createdPost.Entities),
+	})
+}
 
-	if err == nil && fileHeader != nil {
-		file, err := fileHeader.Open()
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open media file"})
-			return
-		}
-		defer file.Close()
+func (c *PostController) GetAllContentPosts(ctx *gin.Context) {
+	page, err := pageParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
-		fmt.Println("📁 Media file received:", fileHeader.Filename)
+	posts, err := c.PostService.GetAllContentPosts(ctx.Request.Context(), viewerID(ctx), page)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
-		// 4. Load config & initialize uploader
-		cfg, err := config.LoadConfig(".")
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load config"})
-			return
-		}
+	ctx.JSON(http.StatusOK, dto.NewFeedPage(posts))
+}
 
-		uploader, err := utils.NewS3Uploader(cfg)
-		if err != nil || uploader == nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize S3 uploader"})
-			return
-		}
+func (c *PostController) GetPostsByUserID(ctx *gin.Context) {
+	userIDParam := ctx.Param("user_id")
+	userID, err := uuid.Parse(userIDParam)
+	if err != nil {
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
+		return
+	}
 
-		// 5. Generate S3 key and upload
-		key := fmt.Sprintf("post-media/%s_%d_%s", userID, time.Now().Unix(), fileHeader.Filename)
-		url, err := uploader.UploadFile(file, fileHeader, key)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload media to S3"})
-			return
-		}
+	page, err := pageParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	posts, err := c.PostService.GetPostsByUserID(ctx.Request.Context(), viewerID(ctx), userID, page)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.NewPostPage(posts, viewer(ctx)))
+}
+
+func (c *PostController) UpdatePost(ctx *gin.Context) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	postID, err := uuid.Parse(ctx.Param("post_id"))
+	if err != nil {
+		ctx.Error(apperrors.InvalidField("post_id", "must be a valid UUID"))
+		return
+	}
 
-		fmt.Println("✅ Media uploaded successfully:", url)
-		mediaURL = url // assign string directly
-	} else {
-		fmt.Println("⚠️ No media uploaded or error reading media:", err)
+	var input dto.UpdatePostRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
+		return
 	}
 
-	// 6. Create post model
-	post := &models.ContentPost{
-		UserID:      userID,
-		PostContent: strings.TrimSpace(postContent),
-		MediaURL:    mediaURL, // string, no pointer
+	post, err := c.PostService.UpdatePost(ctx.Request.Context(), userID, postID, strings.TrimSpace(input.PostContent))
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
 
-	// 7. Save post to DB
-	createdPost, err := pc.PostService.CreatePost(context.Background(), post)
+	ctx.JSON(http.StatusOK, dto.NewPostView(post, viewer(ctx)))
+}
+
+func (c *PostController) DeletePost(ctx *gin.Context) {
+	userID, err := currentUserID(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+		ctx.Error(err)
+		return
+	}
+	postID, err := uuid.Parse(ctx.Param("post_id"))
+	if err != nil {
+		ctx.Error(apperrors.InvalidField("post_id", "must be a valid UUID"))
 		return
 	}
 
-	// 8. Prepare response
-	if createdPost.MediaURL != "" {
-		ctx.JSON(http.StatusCreated, gin.H{
-			"message":      "Post created successfully",
-			"post_content": createdPost.PostContent,
-			"media_url":    createdPost.MediaURL,
-		})
-	} else {
-		ctx.JSON(http.StatusCreated, gin.H{
-			"message":      "Post created successfully",
-			"post_content": createdPost.PostContent,
-		})
+	asAdmin := c.IsAdmin != nil && c.IsAdmin(userID.String())
+	post, err := c.PostService.DeletePost(ctx.Request.Context(), userID, postID, asAdmin)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
+
+	// The post is gone either way; media that could not be removed is only
+	// logged, to be cleaned up by hand.
+	urls := []string{post.MediaURL}
+	for _, a := range post.Attachments {
+		urls = append(urls, a.URL)
+	}
+	slices.Sort(urls)
+	reqCtx := ctx.Request.Context()
+	for _, url := range slices.Compact(urls) {
+		if url == "" {
+			continue
+		}
+		if err := c.Uploader.DeleteFile(reqCtx, url); err != nil {
+			logging.FromContext(reqCtx).WarnContext(reqCtx, "deleting post media failed", "post_id", post.ID, "url", url, "error", err)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Post deleted successfully"})
 }
 
-func (c *PostController) GetAllContentPosts(ctx *gin.Context) {
-	posts, err := c.PostService.GetAllContentPosts(context.Background())
+func (c *PostController) SetVisibility(ctx *gin.Context) {
+	userID, postID, err := shareParams(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, posts)
+	var input dto.SetVisibilityRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	post, err := c.PostService.SetVisibility(ctx.Request.Context(), userID, postID, input.Visibility)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.NewPostView(post, viewer(ctx)))
 }
 
-func (c *PostController) GetPostsByUserID(ctx *gin.Context) {
-	userIDParam := ctx.Param("user_id")
-	userID, err := uuid.Parse(userIDParam)
+func (c *PostController) GetRevisions(ctx *gin.Context) {
+	postID, err := uuid.Parse(ctx.Param("post_id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperrors.InvalidField("post_id", "must be a valid UUID"))
 		return
 	}
 
-	posts, err := c.PostService.GetPostsByUserID(userID)
+	page, err := pageParams(ctx)
 	if err != nil {
-		if err.Error() == "no posts found for this user" {
-			ctx.JSON(http.StatusNotFound, gin.H{"message": "No posts found for this user"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user posts"})
+		ctx.Error(err)
+		return
+	}
+
+	revisions, err := c.PostService.GetRevisions(ctx.Request.Context(), viewerID(ctx), postID, page)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, posts)
+	ctx.JSON(http.StatusOK, dto.NewRevisionPage(revisions))
 }
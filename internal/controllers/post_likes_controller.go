@@ -5,7 +5,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
 )
 
@@ -13,6 +17,50 @@ type PostLikeController struct {
 	PostLikeService *services.PostLikeService
 }
 
+// RegisterRoutes mounts the post like endpoints.
+func (controller *PostLikeController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/posts/:post_id/likes", controller.LikePost)
+	protected.DELETE("/posts/:post_id/likes", controller.UnlikePost)
+	protected.GET("/posts/:post_id/likes", controller.GetPostLikes)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (controller *PostLikeController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/post/:post_id/like", Successor: "/posts/:post_id/likes", Handler: controller.LikePost, Protected: true},
+		{Method: http.MethodPost, Path: "/post/:post_id/unlike", SuccessorMethod: http.MethodDelete, Successor: "/posts/:post_id/likes", Handler: controller.UnlikePost, Protected: true},
+		{Method: http.MethodGet, Path: "/post/:post_id/likes", Successor: "/posts/:post_id/likes", Handler: controller.GetPostLikes, Protected: true},
+	}
+}
+
+// OpenAPI documents the post like endpoints.
+func (controller *PostLikeController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/posts/:post_id/likes",
+			Tag:       "posts",
+			Summary:   "Like a post as the current user",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+			Errors:    []int{http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			Method:    http.MethodDelete,
+			Path:      "/posts/:post_id/likes",
+			Tag:       "posts",
+			Summary:   "Remove the current user's like",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/posts/:post_id/likes",
+			Tag:       "posts",
+			Summary:   "List the users who liked a post",
+			Responses: map[int]any{http.StatusOK: dto.LikesResponse{}},
+		},
+	}
+}
+
 // LikePost handles POST request for liking a post
 func (controller *PostLikeController) LikePost(ctx *gin.Context) {
 	user := ctx.MustGet("user").(models.User)
@@ -20,19 +68,19 @@ func (controller *PostLikeController) LikePost(ctx *gin.Context) {
 	postIDStr := ctx.Param("post_id")
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		ctx.Error(apperrors.InvalidField("post_id", "must be a valid UUID"))
 		return
 	}
 
 	uuidUserID, err := uuid.Parse(userID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	err = controller.PostLikeService.LikePost(uuidUserID, postID)
+	err = controller.PostLikeService.LikePost(ctx.Request.Context(), uuidUserID, postID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -46,19 +94,19 @@ func (controller *PostLikeController) UnlikePost(ctx *gin.Context) {
 	postIDStr := ctx.Param("post_id")
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		ctx.Error(apperrors.InvalidField("post_id", "must be a valid UUID"))
 		return
 	}
 
 	uuidUserID, err := uuid.Parse(userID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	err = controller.PostLikeService.UnlikePost(uuidUserID, postID)
+	err = controller.PostLikeService.UnlikePost(ctx.Request.Context(), uuidUserID, postID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -70,15 +118,21 @@ func (controller *PostLikeController) GetPostLikes(ctx *gin.Context) {
 	postIDStr := ctx.Param("post_id")
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		ctx.Error(apperrors.InvalidField("post_id", "must be a valid UUID"))
+		return
+	}
+
+	page, err := pageParams(ctx)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	likes, err := controller.PostLikeService.GetPostLikes(postID)
+	likes, err := controller.PostLikeService.GetPostLikes(ctx.Request.Context(), viewerID(ctx), postID, page)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"likes": likes})
+	ctx.JSON(http.StatusOK, dto.LikesResponse{Likes: likes.Items, NextCursor: likes.NextCursor})
 }
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+)
+
+// shareParams reads the current user and the post they act on.
+func shareParams(ctx *gin.Context) (userID, postID uuid.UUID, err error) {
+	if userID, err = currentUserID(ctx); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if postID, err = uuid.Parse(ctx.Param("post_id")); err != nil {
+		return uuid.Nil, uuid.Nil, apperrors.InvalidField("post_id", "must be a valid UUID")
+	}
+	return userID, postID, nil
+}
+
+func (c *PostController) Repost(ctx *gin.Context) {
+	userID, postID, err := shareParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	post, err := c.PostService.Repost(ctx.Request.Context(), userID, postID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.NewPostView(post, viewer(ctx)))
+}
+
+func (c *PostController) Unrepost(ctx *gin.Context) {
+	userID, postID, err := shareParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := c.PostService.Unrepost(ctx.Request.Context(), userID, postID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Repost removed successfully"})
+}
+
+func (c *PostController) Quote(ctx *gin.Context) {
+	userID, postID, err := shareParams(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var input dto.QuotePostRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	post, err := c.PostService.Quote(ctx.Request.Context(), userID, postID, strings.TrimSpace(input.PostContent))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.NewPostView(post, viewer(ctx)))
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// videoMeta is what the movie header of an MP4 or QuickTime file says about
+// the video: its duration and the frame size of its first visual track.
+// Width and Height are zero for files without one.
+type videoMeta struct {
+	DurationSeconds float64
+	Width, Height   int
+}
+
+// box is an ISO base media box: its four-character type and the range of
+// its payload in the file.
+type box struct {
+	typ        string
+	start, end int64
+}
+
+// readVideoMeta reads the metadata of an MP4 or QuickTime file of the given
+// size from its moov box, which may come before or after the media data.
+// ok is false when the file has no readable movie header.
+func readVideoMeta(r io.ReaderAt, size int64) (meta videoMeta, ok bool) {
+	moov, ok := findBox(r, box{start: 0, end: size}, "moov")
+	if !ok {
+		return videoMeta{}, false
+	}
+	mvhd, ok := findBox(r, moov, "mvhd")
+	if !ok {
+		return videoMeta{}, false
+	}
+	// Version 1 headers widen the times and the duration to 64 bits.
+	var timescale uint32
+	var duration uint64
+	if hdr, ok := readBox(r, mvhd, 0, 20); ok && hdr[0] == 0 {
+		timescale = binary.BigEndian.Uint32(hdr[12:16])
+		duration = uint64(binary.BigEndian.Uint32(hdr[16:20]))
+	} else if hdr, ok := readBox(r, mvhd, 0, 32); ok && hdr[0] == 1 {
+		timescale = binary.BigEndian.Uint32(hdr[20:24])
+		duration = binary.BigEndian.Uint64(hdr[24:32])
+	}
+	if timescale == 0 {
+		return videoMeta{}, false
+	}
+	meta.DurationSeconds = float64(duration) / float64(timescale)
+
+	// Audio tracks have a zero size, so the first track with a size is
+	// the video.
+	eachBox(r, moov, func(b box) bool {
+		if b.typ != "trak" {
+			return true
+		}
+		tkhd, ok := findBox(r, b, "tkhd")
+		if !ok {
+			return true
+		}
+		// Width and height are 16.16 fixed point numbers at the end of
+		// the header, which version 1 moves 12 bytes further in.
+		version, ok := readBox(r, tkhd, 0, 1)
+		if !ok {
+			return true
+		}
+		offset := int64(76)
+		if version[0] == 1 {
+			offset = 88
+		}
+		dims, ok := readBox(r, tkhd, offset, 8)
+		if !ok {
+			return true
+		}
+		meta.Width = int(binary.BigEndian.Uint32(dims[0:4]) >> 16)
+		meta.Height = int(binary.BigEndian.Uint32(dims[4:8]) >> 16)
+		return meta.Width == 0 || meta.Height == 0
+	})
+	return meta, true
+}
+
+// readBox reads n bytes of the payload of b from offset on. ok is false
+// when they lie outside the box.
+func readBox(r io.ReaderAt, b box, offset, n int64) ([]byte, bool) {
+	if b.start+offset+n > b.end {
+		return nil, false
+	}
+	buf := make([]byte, n)
+	if _, err := r.ReadAt(buf, b.start+offset); err != nil {
+		return nil, false
+	}
+	return buf, true
+}
+
+// findBox returns the first child of parent with the given type.
+func findBox(r io.ReaderAt, parent box, typ string) (found box, ok bool) {
+	eachBox(r, parent, func(b box) bool {
+		if b.typ == typ {
+			found, ok = b, true
+		}
+		return !ok
+	})
+	return found, ok
+}
+
+// eachBox calls fn for the children of parent in order until fn returns
+// false or a box header cannot be read.
+func eachBox(r io.ReaderAt, parent box, fn func(box) bool) {
+	for offset := parent.start; offset+8 <= parent.end; {
+		var hdr [16]byte
+		if _, err := r.ReadAt(hdr[:8], offset); err != nil {
+			return
+		}
+		size := int64(binary.BigEndian.Uint32(hdr[0:4]))
+		headerLen := int64(8)
+		switch size {
+		case 0:
+			// The box runs to the end of its parent.
+			size = parent.end - offset
+		case 1:
+			// The size follows the type as a 64-bit number.
+			if _, err := r.ReadAt(hdr[8:16], offset+8); err != nil {
+				return
+			}
+			size = int64(binary.BigEndian.Uint64(hdr[8:16]))
+			headerLen = 16
+		}
+		if size < headerLen || size > parent.end-offset {
+			return
+		}
+		if !fn(box{typ: string(hdr[4:8]), start: offset + headerLen, end: offset + size}) {
+			return
+		}
+		offset += size
+	}
+}
@@ -1,40 +1,74 @@
 package controllers
 
 import (
+	"context"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sagar-rathod-devops/do-host-network-backend/config"
-	"github.com/sagar-rathod-devops/do-host-network-backend/utils"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 )
 
+// Uploader stores an uploaded file under the given key and returns its public
+// URL. DeleteFile removes a file by the URL UploadFile returned.
+type Uploader interface {
+	UploadFile(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, key string) (string, error)
+	DeleteFile(ctx context.Context, url string) error
+}
+
 type UploadController struct {
-	Uploader *utils.S3Uploader
+	Uploader Uploader
 }
 
-func NewUploadController(cfg config.Config) (*UploadController, error) {
-	uploader, err := utils.NewS3Uploader(cfg)
-	if err != nil {
-		return nil, err
+func NewUploadController(uploader Uploader) *UploadController {
+	return &UploadController{Uploader: uploader}
+}
+
+// RegisterRoutes mounts the generic upload endpoint.
+func (ctrl *UploadController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/uploads", ctrl.UploadFile)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (ctrl *UploadController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/user/upload", Successor: "/uploads", Handler: ctrl.UploadFile, Protected: true},
+	}
+}
+
+// OpenAPI documents the generic upload endpoint.
+func (ctrl *UploadController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/uploads",
+			Tag:       "uploads",
+			Summary:   "Upload a file to object storage",
+			Files:     []string{"file"},
+			Responses: map[int]any{http.StatusOK: dto.UploadResponse{}},
+		},
 	}
-	return &UploadController{Uploader: uploader}, nil
 }
 
 func (ctrl *UploadController) UploadFile(c *gin.Context) {
 	file, fileHeader, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		c.Error(apperrors.InvalidField("file", "is required"))
 		return
 	}
 
 	// You can customize the S3 key (file path in the bucket) as needed
 	key := fileHeader.Filename
 
-	url, err := ctrl.Uploader.UploadFile(file, fileHeader, key)
+	url, err := ctrl.Uploader.UploadFile(c.Request.Context(), file, fileHeader, key)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload file", "details": err.Error()})
+		c.Error(fmt.Errorf("failed to upload file: %w", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"url": url})
+	c.JSON(http.StatusOK, dto.UploadResponse{URL: url})
 }
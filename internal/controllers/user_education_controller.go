@@ -1,13 +1,16 @@
 package controllers
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
 )
 
@@ -15,40 +18,78 @@ type UserEducationController struct {
 	Service *services.UserEducationService
 }
 
-// POST /api/education
-func (c *UserEducationController) Create(ctx *gin.Context) {
-	var input struct {
-		UserID          uuid.UUID `json:"user_id"`
-		Degree          string    `json:"degree"`
-		InstitutionName string    `json:"institution_name"`
-		FieldOfStudy    string    `json:"field_of_study"`
-		Grade           string    `json:"grade"`
-		Year            string    `json:"year"` // e.g., "2023"
+// RegisterRoutes mounts the user education endpoints.
+func (c *UserEducationController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/education", c.Create)
+	protected.GET("/users/:user_id/education", c.GetByUser)
+	protected.PUT("/education/:id", c.Update)
+	protected.DELETE("/education/:id", c.Delete)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (c *UserEducationController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/user/education", Successor: "/education", Handler: c.Create, Protected: true},
+		{Method: http.MethodGet, Path: "/user/education/:user_id", Successor: "/users/:user_id/education", Handler: c.GetByUser, Protected: true},
+		{Method: http.MethodPut, Path: "/user/education/:id", Successor: "/education/:id", Handler: c.Update, Protected: true},
+		{Method: http.MethodDelete, Path: "/user/education/:id", Successor: "/education/:id", Handler: c.Delete, Protected: true},
 	}
+}
 
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
-		return
+// OpenAPI documents the user education endpoints.
+func (c *UserEducationController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/education",
+			Tag:       "education",
+			Summary:   "Add an education entry",
+			Body:      dto.CreateEducationRequest{},
+			Responses: map[int]any{http.StatusCreated: dto.MessageResponse{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/users/:user_id/education",
+			Tag:       "education",
+			Summary:   "List a user's education",
+			Responses: map[int]any{http.StatusOK: []models.UserEducation{}},
+		},
+		{
+			Method:    http.MethodPut,
+			Path:      "/education/:id",
+			Tag:       "education",
+			Summary:   "Update an education entry",
+			Body:      dto.UpdateEducationRequest{},
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+		},
+		{
+			Method:    http.MethodDelete,
+			Path:      "/education/:id",
+			Tag:       "education",
+			Summary:   "Delete an education entry",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+		},
 	}
+}
 
-	if input.UserID == uuid.Nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+// POST /api/education
+func (c *UserEducationController) Create(ctx *gin.Context) {
+	var input dto.CreateEducationRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	education := &models.UserEducation{
-		ID:              uuid.New(),
-		UserID:          input.UserID,
-		Degree:          input.Degree,
-		InstitutionName: input.InstitutionName,
-		FieldOfStudy:    input.FieldOfStudy,
-		Grade:           input.Grade,
-		Year:            input.Year,
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
 
-	if err := c.Service.Create(context.Background(), education); err != nil {
-		fmt.Printf("DEBUG: Failed to create education entry: %v\n", err) // <--- add this line
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create education entry", "details": err.Error()})
+	education := input.NewUserEducation(userID)
+
+	if err := c.Service.Create(ctx.Request.Context(), education); err != nil {
+		ctx.Error(fmt.Errorf("failed to create education entry: %w", err))
 		return
 	}
 
@@ -60,13 +101,13 @@ func (c *UserEducationController) GetByUser(ctx *gin.Context) {
 	userIDStr := ctx.Param("user_id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	educations, err := c.Service.GetByUserID(context.Background(), userID)
+	educations, err := c.Service.GetByUserID(ctx.Request.Context(), userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -78,34 +119,20 @@ func (c *UserEducationController) Update(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	eduID, err := uuid.Parse(idStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid education ID"})
+		ctx.Error(apperrors.InvalidField("id", "must be a valid UUID"))
 		return
 	}
 
-	var input struct {
-		Degree          string `json:"degree"`
-		InstitutionName string `json:"institution_name"`
-		FieldOfStudy    string `json:"field_of_study"`
-		Grade           string `json:"grade"`
-		Year            string `json:"year"`
-	}
-
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+	var input dto.UpdateEducationRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	updatedEdu := &models.UserEducation{
-		ID:              eduID,
-		Degree:          input.Degree,
-		InstitutionName: input.InstitutionName,
-		FieldOfStudy:    input.FieldOfStudy,
-		Grade:           input.Grade,
-		Year:            input.Year,
-	}
+	updatedEdu := input.UserEducation(eduID)
 
-	if err := c.Service.Update(context.Background(), updatedEdu); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update education", "details": err.Error()})
+	if err := c.Service.Update(ctx.Request.Context(), updatedEdu); err != nil {
+		ctx.Error(fmt.Errorf("failed to update education: %w", err))
 		return
 	}
 
@@ -117,12 +144,12 @@ func (c *UserEducationController) Delete(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	eduID, err := uuid.Parse(idStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid education ID"})
+		ctx.Error(apperrors.InvalidField("id", "must be a valid UUID"))
 		return
 	}
 
-	if err := c.Service.Delete(context.Background(), eduID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete education", "details": err.Error()})
+	if err := c.Service.Delete(ctx.Request.Context(), eduID); err != nil {
+		ctx.Error(fmt.Errorf("failed to delete education: %w", err))
 		return
 	}
 
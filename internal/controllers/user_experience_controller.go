@@ -1,13 +1,16 @@
 package controllers
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
 )
 
@@ -19,22 +22,76 @@ func NewUserExperienceController(service *services.UserExperienceService) *UserE
 	return &UserExperienceController{UserExperienceService: service}
 }
 
-func (c *UserExperienceController) Create(ctx *gin.Context) {
-	var input models.UserExperience
+// RegisterRoutes mounts the user experience endpoints.
+func (c *UserExperienceController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/experience", c.Create)
+	protected.GET("/users/:user_id/experience", c.GetByUserID)
+	protected.PUT("/experience/:id", c.Update)
+	protected.DELETE("/experience/:id", c.Delete)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (c *UserExperienceController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/user/experience", Successor: "/experience", Handler: c.Create, Protected: true},
+		{Method: http.MethodGet, Path: "/user/experience/:user_id", Successor: "/users/:user_id/experience", Handler: c.GetByUserID, Protected: true},
+		{Method: http.MethodPut, Path: "/user/experience/:id", Successor: "/experience/:id", Handler: c.Update, Protected: true},
+		{Method: http.MethodDelete, Path: "/user/experience/:id", Successor: "/experience/:id", Handler: c.Delete, Protected: true},
+	}
+}
 
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+// OpenAPI documents the user experience endpoints.
+func (c *UserExperienceController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/experience",
+			Tag:       "experience",
+			Summary:   "Add a work experience entry",
+			Body:      dto.CreateExperienceRequest{},
+			Responses: map[int]any{http.StatusCreated: dto.MessageResponse{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/users/:user_id/experience",
+			Tag:       "experience",
+			Summary:   "List a user's work experience",
+			Responses: map[int]any{http.StatusOK: []models.UserExperience{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPut,
+			Path:      "/experience/:id",
+			Tag:       "experience",
+			Summary:   "Update a work experience entry",
+			Body:      dto.UpdateExperienceRequest{},
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+		},
+		{
+			Method:    http.MethodDelete,
+			Path:      "/experience/:id",
+			Tag:       "experience",
+			Summary:   "Delete a work experience entry",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+		},
+	}
+}
+
+func (c *UserExperienceController) Create(ctx *gin.Context) {
+	var input dto.CreateExperienceRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	if input.UserID == uuid.Nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	if err := c.UserExperienceService.Create(context.Background(), &input); err != nil {
-		fmt.Printf("DEBUG: Failed to create user experience: %v\n", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user experience", "details": err.Error()})
+	if err := c.UserExperienceService.Create(ctx.Request.Context(), input.NewUserExperience(userID)); err != nil {
+		ctx.Error(fmt.Errorf("failed to create user experience: %w", err))
 		return
 	}
 
@@ -45,18 +102,18 @@ func (c *UserExperienceController) GetByUserID(ctx *gin.Context) {
 	userIDStr := ctx.Param("user_id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	experiences, err := c.UserExperienceService.GetByUserID(context.Background(), userID)
+	experiences, err := c.UserExperienceService.GetByUserID(ctx.Request.Context(), userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	if len(experiences) == 0 {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "No user experience found"})
+		ctx.Error(apperrors.NotFound("experience_not_found", "No user experience found"))
 		return
 	}
 
@@ -67,19 +124,18 @@ func (c *UserExperienceController) GetByUserID(ctx *gin.Context) {
 func (c *UserExperienceController) Update(ctx *gin.Context) {
 	experienceID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid experience ID"})
+		ctx.Error(apperrors.InvalidField("id", "must be a valid UUID"))
 		return
 	}
 
-	var input models.UserExperience
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+	var input dto.UpdateExperienceRequest
+	if err := dto.BindJSON(ctx, &input); err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	input.ID = experienceID
-	if err := c.UserExperienceService.Update(context.Background(), &input); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user experience", "details": err.Error()})
+	if err := c.UserExperienceService.Update(ctx.Request.Context(), input.UserExperience(experienceID)); err != nil {
+		ctx.Error(fmt.Errorf("failed to update user experience: %w", err))
 		return
 	}
 
@@ -90,12 +146,12 @@ func (c *UserExperienceController) Update(ctx *gin.Context) {
 func (c *UserExperienceController) Delete(ctx *gin.Context) {
 	experienceID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid experience ID"})
+		ctx.Error(apperrors.InvalidField("id", "must be a valid UUID"))
 		return
 	}
 
-	if err := c.UserExperienceService.Delete(context.Background(), experienceID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user experience", "details": err.Error()})
+	if err := c.UserExperienceService.Delete(ctx.Request.Context(), experienceID); err != nil {
+		ctx.Error(fmt.Errorf("failed to delete user experience: %w", err))
 		return
 	}
 
@@ -1,169 +1,174 @@
 package controllers
 
 import (
-	"context"
 	"fmt"
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/sagar-rathod-devops/do-host-network-backend/config"
-	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
-	"github.com/sagar-rathod-devops/do-host-network-backend/utils"
 )
 
 type UserProfileController struct {
 	UserProfileService *services.UserProfileService
+	Uploader           Uploader
 }
 
-func NewUserProfileController(profileService *services.UserProfileService) *UserProfileController {
+func NewUserProfileController(profileService *services.UserProfileService, uploader Uploader) *UserProfileController {
 	return &UserProfileController{
 		UserProfileService: profileService,
+		Uploader:           uploader,
+	}
+}
+
+// RegisterRoutes mounts the user profile endpoints.
+func (ctrl *UserProfileController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/profiles", ctrl.Create)
+	protected.GET("/profiles/:user_id", ctrl.GetByUserID)
+	protected.GET("/profiles", ctrl.GetAll)
+	protected.PUT("/profiles/:user_id", ctrl.Update)
+	protected.DELETE("/profiles/:user_id", ctrl.Delete)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (ctrl *UserProfileController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/user/profile", Successor: "/profiles", Handler: ctrl.Create, Protected: true},
+		{Method: http.MethodGet, Path: "/user/profile/:user_id", Successor: "/profiles/:user_id", Handler: ctrl.GetByUserID, Protected: true},
+		{Method: http.MethodGet, Path: "/user/profile", Successor: "/profiles", Handler: ctrl.GetAll, Protected: true},
+		{Method: http.MethodPut, Path: "/user/profile/update/:user_id", Successor: "/profiles/:user_id", Handler: ctrl.Update, Protected: true},
+		{Method: http.MethodDelete, Path: "/user/profile/delete/:user_id", Successor: "/profiles/:user_id", Handler: ctrl.Delete, Protected: true},
+	}
+}
+
+// OpenAPI documents the user profile endpoints.
+func (ctrl *UserProfileController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:  http.MethodPost,
+			Path:    "/profiles",
+			Tag:     "profiles",
+			Summary: "Create a profile with an optional image",
+			Form:    dto.CreateUserProfileRequest{}, Files: []string{"profile_image"},
+			Responses: map[int]any{http.StatusCreated: dto.ProfileCreatedResponse{}},
+			Errors:    []int{http.StatusConflict},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/profiles/:user_id",
+			Tag:       "profiles",
+			Summary:   "Get a user's profile",
+			Responses: map[int]any{http.StatusOK: dto.ProfileView{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/profiles",
+			Tag:       "profiles",
+			Summary:   "List profiles, newest first",
+			Query:     pageQuery,
+			Responses: map[int]any{http.StatusOK: dto.ProfilePage{}},
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/profiles/:user_id",
+			Tag:     "profiles",
+			Summary: "Replace a user's profile",
+			Form:    dto.UpdateUserProfileRequest{}, Files: []string{"profile_image"},
+			Responses: map[int]any{http.StatusOK: dto.ProfileView{}},
+		},
+		{
+			Method:    http.MethodDelete,
+			Path:      "/profiles/:user_id",
+			Tag:       "profiles",
+			Summary:   "Delete a user's profile",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+		},
 	}
 }
 
 func (ctrl *UserProfileController) Create(ctx *gin.Context) {
-	fmt.Println("🚀 Received request to create user profile")
-
-	// 1. Parse form-data fields
-	var input struct {
-		UserID              string `form:"user_id" binding:"required"`
-		FullName            string `form:"full_name" binding:"required"`
-		Designation         string `form:"designation"`
-		Organization        string `form:"organization"`
-		ProfessionalSummary string `form:"professional_summary"`
-		Location            string `form:"location"`
-		Email               string `form:"email" binding:"required"`
-		ContactNumber       string `form:"contact_number"`
-	}
-
-	if err := ctx.ShouldBind(&input); err != nil {
-		fmt.Println("❌ Failed to bind form fields:", err.Error())
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// 1. Parse and validate form-data fields
+	var input dto.CreateUserProfileRequest
+	if err := dto.BindForm(ctx, &input); err != nil {
+		ctx.Error(err)
 		return
 	}
-	fmt.Println("✅ Form fields parsed successfully")
-
-	// 2. Parse UUID
-	uid, err := uuid.Parse(input.UserID)
+	uid, err := currentUserID(ctx)
 	if err != nil {
-		fmt.Println("❌ Invalid UUID format:", err.Error())
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format"})
+		ctx.Error(err)
 		return
 	}
-	fmt.Println("✅ UserID UUID parsed:", uid.String())
 
-	// 3. Get file
+	// 2. Get file
 	fileHeader, err := ctx.FormFile("profile_image")
 	var profileImageURL *string
 
 	if err == nil && fileHeader != nil {
 		file, err := fileHeader.Open()
 		if err != nil {
-			fmt.Println("❌ Failed to open profile image file:", err.Error())
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open profile image"})
+			ctx.Error(fmt.Errorf("failed to open profile image: %w", err))
 			return
 		}
 		defer file.Close()
 
-		fmt.Println("📁 File received:", fileHeader.Filename)
-
-		// 4. Load config & uploader
-		cfg, err := config.LoadConfig(".")
-		if err != nil {
-			fmt.Println("❌ Failed to load config:", err.Error())
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load config"})
-			return
-		}
-
-		uploader, err := utils.NewS3Uploader(cfg)
-		if err != nil || uploader == nil {
-			fmt.Println("❌ Failed to create S3 uploader:", err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create S3 uploader"})
-			return
-		}
-
-		// 5. Upload to S3
+		// 3. Upload to S3
 		key := fmt.Sprintf("profile-images/%s_%d_%s", uid, time.Now().Unix(), fileHeader.Filename)
-		url, err := uploader.UploadFile(file, fileHeader, key)
+		url, err := ctrl.Uploader.UploadFile(ctx.Request.Context(), file, fileHeader, key)
 		if err != nil {
-			fmt.Println("❌ Failed to upload image to S3:", err.Error())
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload profile image"})
+			ctx.Error(fmt.Errorf("failed to upload profile image: %w", err))
 			return
 		}
 
-		fmt.Println("✅ Image uploaded to S3 successfully. URL:", url)
+		logging.FromContext(ctx.Request.Context()).InfoContext(ctx.Request.Context(), "profile image uploaded", "key", key)
 		profileImageURL = &url
-	} else {
-		fmt.Println("⚠️ No image uploaded or error reading image:", err)
-	}
-
-	// 6. Create user profile model
-	profile := &models.UserProfile{
-		UserID:              uid,
-		ProfileImage:        profileImageURL,
-		FullName:            cleanString(input.FullName),
-		Designation:         stringPtr(input.Designation),
-		Organization:        stringPtr(input.Organization),
-		ProfessionalSummary: stringPtr(input.ProfessionalSummary),
-		Location:            stringPtr(input.Location),
-		Email:               cleanString(input.Email),
-		ContactNumber:       stringPtr(input.ContactNumber),
-	}
-
-	// 7. Save to DB
-	fmt.Println("💾 Saving user profile to database")
-	if _, err := ctrl.UserProfileService.Create(context.Background(), profile); err != nil {
-		fmt.Println("❌ Failed to create user profile:", err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user profile"})
-		return
 	}
 
-	fmt.Println("✅ User profile created successfully:", profile.ID.String())
-	ctx.JSON(http.StatusCreated, gin.H{
-		"message": "User profile created successfully",
-		"profile": profile,
-	})
-}
+	// 4. Create user profile model
+	profile := input.UserProfile(uid)
+	profile.ProfileImage = profileImageURL
 
-func cleanString(s string) string {
-	s = strings.TrimSpace(s)
-	unquoted, err := strconv.Unquote(s)
-	if err != nil {
-		return s
+	// 5. Save to DB
+	if _, err := ctrl.UserProfileService.Create(ctx.Request.Context(), profile); err != nil {
+		ctx.Error(fmt.Errorf("failed to create user profile: %w", err))
+		return
 	}
-	return unquoted
-}
 
-func stringPtr(s string) *string {
-	cleaned := cleanString(s)
-	if cleaned == "" {
-		return nil
-	}
-	return &cleaned
+	ctx.JSON(http.StatusCreated, dto.ProfileCreatedResponse{
+		Message: "User profile created successfully",
+		Profile: dto.NewProfileView(profile, viewer(ctx)),
+	})
 }
 
 func (ctrl *UserProfileController) GetByUserID(ctx *gin.Context) {
 	userID := ctx.Param("user_id")
-	profile, err := ctrl.UserProfileService.GetByUserID(context.Background(), userID)
+	profile, err := ctrl.UserProfileService.GetByUserID(ctx.Request.Context(), userID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Profile not found"})
+		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, profile)
+	ctx.JSON(http.StatusOK, dto.NewProfileView(profile, viewer(ctx)))
 }
 
 func (ctrl *UserProfileController) GetAll(ctx *gin.Context) {
-	profiles, err := ctrl.UserProfileService.GetAll(context.Background())
+	page, err := pageParams(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch profiles"})
+		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, profiles)
+
+	profiles, err := ctrl.UserProfileService.GetAll(ctx.Request.Context(), page)
+	if err != nil {
+		ctx.Error(fmt.Errorf("failed to fetch profiles: %w", err))
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.NewProfilePage(profiles, viewer(ctx)))
 }
 
 func (ctrl *UserProfileController) Update(ctx *gin.Context) {
@@ -171,57 +176,41 @@ func (ctrl *UserProfileController) Update(ctx *gin.Context) {
 	userIDStr := ctx.Param("user_id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
 	// Parse multipart form (max 10 MB)
 	if err := ctx.Request.ParseMultipartForm(10 << 20); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form", "details": err.Error()})
+		ctx.Error(apperrors.Validation("Malformed multipart form"))
 		return
 	}
 
-	// Prepare the updated profile struct
-	updated := &models.UserProfile{
-		UserID:              userID,
-		FullName:            ctx.PostForm("full_name"),
-		Designation:         stringPtr(ctx.PostForm("designation")),
-		Organization:        stringPtr(ctx.PostForm("organization")),
-		ProfessionalSummary: stringPtr(ctx.PostForm("professional_summary")),
-		Location:            stringPtr(ctx.PostForm("location")),
-		Email:               ctx.PostForm("email"),
-		ContactNumber:       stringPtr(ctx.PostForm("contact_number")),
-		UpdatedAt:           time.Now(),
+	var input dto.UpdateUserProfileRequest
+	if err := dto.BindForm(ctx, &input); err != nil {
+		ctx.Error(err)
+		return
 	}
 
+	// Prepare the updated profile struct
+	updated := input.UserProfile(userID)
+	updated.UpdatedAt = time.Now()
+
 	// Handle profile_image upload to S3
 	fileHeader, err := ctx.FormFile("profile_image")
 	if err == nil && fileHeader != nil {
 		file, err := fileHeader.Open()
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open uploaded image", "details": err.Error()})
+			ctx.Error(fmt.Errorf("failed to open uploaded image: %w", err))
 			return
 		}
 		defer file.Close()
 
-		// Load config and initialize uploader
-		cfg, err := config.LoadConfig(".")
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load config", "details": err.Error()})
-			return
-		}
-
-		uploader, err := utils.NewS3Uploader(cfg)
-		if err != nil || uploader == nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create S3 uploader", "details": err.Error()})
-			return
-		}
-
 		// Upload file to S3
 		key := fmt.Sprintf("profile-images/%s_%d_%s", userID.String(), time.Now().Unix(), fileHeader.Filename)
-		url, err := uploader.UploadFile(file, fileHeader, key)
+		url, err := ctrl.Uploader.UploadFile(ctx.Request.Context(), file, fileHeader, key)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image to S3", "details": err.Error()})
+			ctx.Error(fmt.Errorf("failed to upload image to S3: %w", err))
 			return
 		}
 
@@ -229,20 +218,20 @@ func (ctrl *UserProfileController) Update(ctx *gin.Context) {
 	}
 
 	// Call service to update the profile in DB
-	updatedProfile, err := ctrl.UserProfileService.Update(context.Background(), userID.String(), updated)
+	updatedProfile, err := ctrl.UserProfileService.Update(ctx.Request.Context(), userID.String(), updated)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile", "details": err.Error()})
+		ctx.Error(fmt.Errorf("failed to update profile: %w", err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, updatedProfile)
+	ctx.JSON(http.StatusOK, dto.NewProfileView(updatedProfile, viewer(ctx)))
 }
 
 func (ctrl *UserProfileController) Delete(ctx *gin.Context) {
 	userID := ctx.Param("user_id")
 
-	if err := ctrl.UserProfileService.Delete(context.Background(), userID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete profile", "details": err.Error()})
+	if err := ctrl.UserProfileService.Delete(ctx.Request.Context(), userID); err != nil {
+		ctx.Error(fmt.Errorf("failed to delete profile: %w", err))
 		return
 	}
 
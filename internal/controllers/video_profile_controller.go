@@ -1,7 +1,6 @@
 package controllers
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,55 +8,110 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
-	"github.com/sagar-rathod-devops/do-host-network-backend/utils"
 )
 
 type VideoProfileController struct {
 	VideoProfileService *services.VideoProfileService
+	Uploader            Uploader
+}
+
+// RegisterRoutes mounts the video profile endpoints.
+func (vc *VideoProfileController) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/videos", vc.UploadVideo)
+	protected.GET("/users/:user_id/videos", vc.GetVideoProfilesByUser)
+	protected.PUT("/videos/:id", vc.UpdateVideo)
+	protected.DELETE("/videos/:id", vc.DeleteVideo)
+	protected.GET("/videos/stream", vc.StreamVideo)
+}
+
+// LegacyRoutes maps the pre-v1 paths onto the same handlers.
+func (vc *VideoProfileController) LegacyRoutes() []deprecation.Alias {
+	return []deprecation.Alias{
+		{Method: http.MethodPost, Path: "/user/video", Successor: "/videos", Handler: vc.UploadVideo, Protected: true},
+		{Method: http.MethodGet, Path: "/user/video/:user_id", Successor: "/users/:user_id/videos", Handler: vc.GetVideoProfilesByUser, Protected: true},
+		{Method: http.MethodPut, Path: "/user/video/:id", Successor: "/videos/:id", Handler: vc.UpdateVideo, Protected: true},
+		{Method: http.MethodDelete, Path: "/user/video/:id", Successor: "/videos/:id", Handler: vc.DeleteVideo, Protected: true},
+		{Method: http.MethodGet, Path: "/user/stream", Successor: "/videos/stream", Handler: vc.StreamVideo, Protected: true},
+	}
+}
+
+// OpenAPI documents the video profile endpoints.
+func (vc *VideoProfileController) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodPost,
+			Path:      "/videos",
+			Tag:       "videos",
+			Summary:   "Upload a profile video",
+			Files:     []string{"video"},
+			Responses: map[int]any{http.StatusCreated: dto.VideoResponse{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/users/:user_id/videos",
+			Tag:       "videos",
+			Summary:   "List a user's profile videos",
+			Responses: map[int]any{http.StatusOK: []models.VideoProfile{}},
+		},
+		{
+			Method:    http.MethodPut,
+			Path:      "/videos/:id",
+			Tag:       "videos",
+			Summary:   "Replace a profile video",
+			Files:     []string{"video"},
+			Responses: map[int]any{http.StatusOK: dto.VideoResponse{}},
+		},
+		{
+			Method:    http.MethodDelete,
+			Path:      "/videos/:id",
+			Tag:       "videos",
+			Summary:   "Delete a profile video",
+			Responses: map[int]any{http.StatusOK: dto.MessageResponse{}},
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/videos/stream",
+			Tag:     "videos",
+			Summary: "Stream a stored video through the API",
+			Query:   []openapi.Param{{Name: "url", Description: "URL of the video to stream", Required: true, Format: "uri"}}, Produces: "video/*",
+			Responses: map[int]any{http.StatusOK: openapi.Binary{}},
+			Errors:    []int{http.StatusBadGateway},
+		},
+	}
 }
 
 // POST /api/video
 // POST /api/video/upload
 func (vc *VideoProfileController) UploadVideo(ctx *gin.Context) {
-	userIDStr := ctx.PostForm("user_id")
-	userID, err := uuid.Parse(userIDStr)
+	userID, err := currentUserID(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		ctx.Error(err)
 		return
 	}
 
 	fileHeader, err := ctx.FormFile("video")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to get video file"})
+		ctx.Error(apperrors.InvalidField("video", "is required"))
 		return
 	}
 
 	file, err := fileHeader.Open()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open video file"})
+		ctx.Error(fmt.Errorf("failed to open video file: %w", err))
 		return
 	}
 	defer file.Close()
 
-	cfg, err := config.LoadConfig(".")
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load config"})
-		return
-	}
-
-	uploader, err := utils.NewS3Uploader(cfg)
-	if err != nil || uploader == nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create S3 uploader"})
-		return
-	}
-
 	key := fmt.Sprintf("videos/%s_%d_%s", userID, time.Now().Unix(), fileHeader.Filename)
-	videoURL, err := uploader.UploadFile(file, fileHeader, key)
+	videoURL, err := vc.Uploader.UploadFile(ctx.Request.Context(), file, fileHeader, key)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload video"})
+		ctx.Error(fmt.Errorf("failed to upload video: %w", err))
 		return
 	}
 
@@ -67,13 +121,13 @@ func (vc *VideoProfileController) UploadVideo(ctx *gin.Context) {
 	}
 
 	if err := vc.VideoProfileService.Create(ctx.Request.Context(), video); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save video profile"})
+		ctx.Error(fmt.Errorf("failed to save video profile: %w", err))
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{
-		"message":   "Video uploaded successfully",
-		"video_url": videoURL,
+	ctx.JSON(http.StatusCreated, dto.VideoResponse{
+		Message:  "Video uploaded successfully",
+		VideoURL: videoURL,
 	})
 }
 
@@ -82,13 +136,13 @@ func (vc *VideoProfileController) GetVideoProfilesByUser(ctx *gin.Context) {
 	userIDParam := ctx.Param("user_id")
 	userID, err := uuid.Parse(userIDParam)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		ctx.Error(apperrors.InvalidField("user_id", "must be a valid UUID"))
 		return
 	}
 
-	profiles, err := vc.VideoProfileService.GetByUserID(context.Background(), userID)
+	profiles, err := vc.VideoProfileService.GetByUserID(ctx.Request.Context(), userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -98,17 +152,28 @@ func (vc *VideoProfileController) GetVideoProfilesByUser(ctx *gin.Context) {
 func (vc *VideoProfileController) StreamVideo(ctx *gin.Context) {
 	videoURL := ctx.Query("url")
 	if videoURL == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Video URL required"})
+		ctx.Error(apperrors.InvalidField("url", "is required"))
 		return
 	}
 
-	resp, err := http.Get(videoURL)
-	if err != nil || resp.StatusCode != 200 {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch video"})
+	req, err := http.NewRequestWithContext(ctx.Request.Context(), http.MethodGet, videoURL, nil)
+	if err != nil {
+		ctx.Error(apperrors.InvalidField("url", "must be a valid URL"))
+		return
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		ctx.Error(fmt.Errorf("failed to fetch video: %w", err))
 		return
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		ctx.Error(fmt.Errorf("failed to fetch video: upstream returned %s", resp.Status))
+		return
+	}
+
 	ctx.Header("Content-Type", resp.Header.Get("Content-Type"))
 	ctx.Status(resp.StatusCode)
 	io.Copy(ctx.Writer, resp.Body)
@@ -118,39 +183,27 @@ func (vc *VideoProfileController) UpdateVideo(ctx *gin.Context) {
 	videoIDStr := ctx.Param("id")
 	videoID, err := uuid.Parse(videoIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video ID"})
+		ctx.Error(apperrors.InvalidField("id", "must be a valid UUID"))
 		return
 	}
 
 	fileHeader, err := ctx.FormFile("video")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to get new video file"})
+		ctx.Error(apperrors.InvalidField("video", "is required"))
 		return
 	}
 
 	file, err := fileHeader.Open()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open video file"})
+		ctx.Error(fmt.Errorf("failed to open video file: %w", err))
 		return
 	}
 	defer file.Close()
 
-	cfg, err := config.LoadConfig(".")
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load config"})
-		return
-	}
-
-	uploader, err := utils.NewS3Uploader(cfg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create uploader"})
-		return
-	}
-
 	key := fmt.Sprintf("videos/%s_%d_%s", videoID, time.Now().Unix(), fileHeader.Filename)
-	videoURL, err := uploader.UploadFile(file, fileHeader, key)
+	videoURL, err := vc.Uploader.UploadFile(ctx.Request.Context(), file, fileHeader, key)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload new video"})
+		ctx.Error(fmt.Errorf("failed to upload new video: %w", err))
 		return
 	}
 
@@ -160,13 +213,13 @@ func (vc *VideoProfileController) UpdateVideo(ctx *gin.Context) {
 	}
 
 	if err := vc.VideoProfileService.Update(ctx.Request.Context(), video); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update video"})
+		ctx.Error(fmt.Errorf("failed to update video: %w", err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"message":   "Video updated successfully",
-		"video_url": videoURL,
+	ctx.JSON(http.StatusOK, dto.VideoResponse{
+		Message:  "Video updated successfully",
+		VideoURL: videoURL,
 	})
 }
 
@@ -174,12 +227,12 @@ func (vc *VideoProfileController) DeleteVideo(ctx *gin.Context) {
 	videoIDStr := ctx.Param("id")
 	videoID, err := uuid.Parse(videoIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video ID"})
+		ctx.Error(apperrors.InvalidField("id", "must be a valid UUID"))
 		return
 	}
 
 	if err := vc.VideoProfileService.Delete(ctx.Request.Context(), videoID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete video"})
+		ctx.Error(fmt.Errorf("failed to delete video: %w", err))
 		return
 	}
 
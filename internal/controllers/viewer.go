@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/dto"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// viewer returns the authenticated user a response is rendered for, or an
+// anonymous viewer on public routes.
+func viewer(ctx *gin.Context) dto.Viewer {
+	if user, ok := ctx.Get("user"); ok {
+		if u, ok := user.(models.User); ok {
+			return dto.Viewer{UserID: u.ID}
+		}
+	}
+	return dto.Viewer{}
+}
+
+// viewerID returns the ID of the authenticated user posts are filtered for.
+// Anonymous viewers get uuid.Nil, which sees public posts only.
+func viewerID(ctx *gin.Context) uuid.UUID {
+	id, err := uuid.Parse(viewer(ctx).UserID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// currentUserID reads the ID of the authenticated user on protected routes,
+// where a token naming a malformed ID is rejected.
+func currentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	user := ctx.MustGet("user").(models.User)
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		return uuid.Nil, apperrors.Unauthorized("invalid_token", "Invalid user ID")
+	}
+	return userID, nil
+}
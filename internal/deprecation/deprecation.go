@@ -0,0 +1,171 @@
+// Package deprecation keeps retired routes working as aliases of their
+// replacements while telling clients, through the Deprecation (RFC 9745),
+// Sunset (RFC 8594) and Link headers, that they should move. It counts the
+// calls each alias receives so we know when it is safe to remove.
+package deprecation
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+)
+
+// Alias serves Path with the handler of the route at Successor. Both paths
+// use Gin syntax and share their parameter names. SuccessorMethod is set
+// when the successor is reached with another method.
+type Alias struct {
+	Method          string
+	Path            string
+	SuccessorMethod string
+	Successor       string
+	Handler         gin.HandlerFunc
+
+	// Protected aliases require authentication like their successor.
+	Protected bool
+}
+
+// Key identifies an alias in usage reports, e.g. "GET /posts/all-content".
+func (a Alias) Key() string {
+	return a.Method + " " + a.Path
+}
+
+// SuccessorKey identifies the route that replaces the alias.
+func (a Alias) SuccessorKey() string {
+	method := a.SuccessorMethod
+	if method == "" {
+		method = a.Method
+	}
+	return method + " " + a.Successor
+}
+
+// Policy describes when the aliases were deprecated and when they go away.
+type Policy struct {
+	DeprecatedAt time.Time
+	SunsetAt     time.Time
+}
+
+// Usage counts calls to deprecated aliases.
+type Usage struct {
+	mu       sync.Mutex
+	counts   map[string]uint64
+	lastSeen map[string]time.Time
+}
+
+// NewUsage creates an empty usage counter.
+func NewUsage() *Usage {
+	return &Usage{counts: make(map[string]uint64), lastSeen: make(map[string]time.Time)}
+}
+
+// Record counts one call to the alias with the given key and reports
+// whether it was the first one.
+func (u *Usage) Record(key string, at time.Time) (first bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.counts[key]++
+	u.lastSeen[key] = at
+	return u.counts[key] == 1
+}
+
+// Stat is the usage of one alias.
+type Stat struct {
+	Route    string    `json:"route"`
+	Calls    uint64    `json:"calls"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Snapshot returns the usage of every alias called so far, most used first.
+func (u *Usage) Snapshot() []Stat {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	stats := make([]Stat, 0, len(u.counts))
+	for key, n := range u.counts {
+		stats = append(stats, Stat{Route: key, Calls: n, LastSeen: u.lastSeen[key]})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Calls != stats[j].Calls {
+			return stats[i].Calls > stats[j].Calls
+		}
+		return stats[i].Route < stats[j].Route
+	})
+	return stats
+}
+
+// successorKey is the context key under which Resolver stores the route an
+// alias stands for.
+const successorKey = "deprecation.successor"
+
+// Resolver records the successor of requests to aliases. Installed as
+// engine-wide middleware it runs before any route middleware, so Route
+// resolves aliases everywhere.
+func Resolver(aliases []Alias) gin.HandlerFunc {
+	successors := make(map[string]string, len(aliases))
+	for _, alias := range aliases {
+		successors[alias.Key()] = alias.Successor
+	}
+	return func(ctx *gin.Context) {
+		if successor, ok := successors[ctx.Request.Method+" "+ctx.FullPath()]; ok {
+			ctx.Set(successorKey, successor)
+		}
+		ctx.Next()
+	}
+}
+
+// Route returns the route template of the request, resolving aliases to
+// their successor so per-route policies treat both alike.
+func Route(ctx *gin.Context) string {
+	if successor := ctx.GetString(successorKey); successor != "" {
+		return successor
+	}
+	return ctx.FullPath()
+}
+
+// Path returns the path of the request, the path of the successor for
+// aliases, so a request reads the same through both.
+func Path(ctx *gin.Context) string {
+	if successor := ctx.GetString(successorKey); successor != "" {
+		return expand(successor, ctx.Params)
+	}
+	return ctx.Request.URL.Path
+}
+
+// Middleware marks responses of alias as deprecated and records the call.
+// The first call to each alias is logged so operators notice live clients.
+func (p Policy) Middleware(alias Alias, usage *Usage) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", p.DeprecatedAt.Unix())
+	sunset := p.SunsetAt.UTC().Format(http.TimeFormat)
+	key := alias.Key()
+
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", deprecation)
+		ctx.Header("Sunset", sunset)
+		ctx.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", expand(alias.Successor, ctx.Params)))
+
+		if usage.Record(key, time.Now()) {
+			reqCtx := ctx.Request.Context()
+			logging.FromContext(reqCtx).WarnContext(reqCtx, "deprecated route called",
+				"route", key, "successor", alias.SuccessorKey())
+		}
+		ctx.Next()
+	}
+}
+
+// expand fills the parameters of a Gin path with the values of the request.
+func expand(path string, params gin.Params) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") {
+			if v, ok := params.Get(seg[1:]); ok {
+				segments[i] = v
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
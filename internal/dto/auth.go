@@ -0,0 +1,32 @@
+package dto
+
+// RegisterRequest is the body of POST /auth/register.
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Username string `json:"username" binding:"required,min=3,max=30,username"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// LoginRequest is the body of POST /auth/login.
+type LoginRequest struct {
+	EmailOrUsername string `json:"emailOrUsername" binding:"required,max=255"` // accept either
+	Password        string `json:"password" binding:"required,max=72"`
+}
+
+// VerifyOTPRequest is the body of POST /auth/verify-otp.
+type VerifyOTPRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	OTP   string `json:"otp" binding:"required,len=6,numeric"`
+}
+
+// ForgotPasswordRequest is the body of POST /auth/forgot-password.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest is the body of POST /auth/reset-password.
+type ResetPasswordRequest struct {
+	Email       string `json:"email" binding:"required,email"`
+	OTP         string `json:"otp" binding:"required,len=6,numeric"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72"`
+}
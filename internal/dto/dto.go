@@ -0,0 +1,223 @@
+// Package dto holds the request bodies accepted by the HTTP API. They are
+// kept apart from internal/models so the wire format and its validation rules
+// can change without touching the persistence layer.
+//
+// Validation rules live in `binding` struct tags and are enforced by Bind,
+// which reports every failing field at once.
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+)
+
+// DateLayout is the format of every calendar date in request bodies.
+const DateLayout = "2006-01-02"
+
+var (
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+	phonePattern    = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{5,18}[0-9]$`)
+
+	registerOnce sync.Once
+)
+
+// BindJSON decodes the JSON request body into req and validates it.
+func BindJSON(ctx *gin.Context, req any) error {
+	return bind(ctx, binding.JSON, req)
+}
+
+// BindForm decodes a urlencoded or multipart form into req and validates it.
+func BindForm(ctx *gin.Context, req any) error {
+	b := binding.Form
+	if strings.HasPrefix(ctx.ContentType(), binding.MIMEMultipartPOSTForm) {
+		b = binding.FormMultipart
+	}
+	return bind(ctx, b, req)
+}
+
+func bind(ctx *gin.Context, b binding.Binding, req any) error {
+	registerOnce.Do(registerValidators)
+
+	err := ctx.ShouldBindWith(req, b)
+	if err == nil {
+		return nil
+	}
+	return translate(err)
+}
+
+// translate turns binding and validation failures into a validation error
+// that lists each offending field.
+func translate(err error) error {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		fields := make([]apperrors.FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			fields = append(fields, apperrors.FieldError{Field: fe.Field(), Message: message(fe)})
+		}
+		return apperrors.Validation("Invalid input", fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return apperrors.InvalidField(typeErr.Field, "must be a "+typeErr.Type.String())
+	}
+
+	if errors.Is(err, io.EOF) {
+		return apperrors.Validation("Request body is empty")
+	}
+	return apperrors.Validation("Malformed request body")
+}
+
+// message renders one failed rule as a sentence fragment following the field
+// name, e.g. "must be a valid email address".
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "notblank":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "uuid":
+		return "must be a valid UUID"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters", fe.Param())
+	case "len":
+		return fmt.Sprintf("must be exactly %s characters", fe.Param())
+	case "numeric":
+		return "must contain only digits"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "username":
+		return "may contain only letters, digits, '_' and '.'"
+	case "phone":
+		return "must be a valid phone number"
+	case "date":
+		return "must be a date in YYYY-MM-DD format"
+	case "notpast":
+		return "must not be in the past"
+	case "notfuture":
+		return "must not be in the future"
+	case "onorafter":
+		return "must not be before " + jsonName(fe)
+	default:
+		return "is invalid"
+	}
+}
+
+// jsonName returns the wire name of the field a cross-field rule points at.
+func jsonName(fe validator.FieldError) string {
+	param := fe.Param()
+	var out []rune
+	for i, r := range param {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			out = append(out, '_')
+		}
+		out = append(out, r)
+	}
+	return strings.ToLower(string(out))
+}
+
+func registerValidators() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Report fields by the name clients send rather than the Go name.
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+
+	rules := map[string]validator.Func{
+		"notblank":  notBlank,
+		"username":  matches(usernamePattern),
+		"phone":     matches(phonePattern),
+		"date":      isDate,
+		"notpast":   notPast,
+		"notfuture": notFuture,
+		"onorafter": onOrAfter,
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(fmt.Sprintf("dto: registering %q: %v", tag, err))
+		}
+	}
+}
+
+func notBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+func matches(re *regexp.Regexp) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return re.MatchString(fl.Field().String())
+	}
+}
+
+func isDate(fl validator.FieldLevel) bool {
+	_, err := time.Parse(DateLayout, fl.Field().String())
+	return err == nil
+}
+
+// notPast accepts today or any later date. Values that are not dates are
+// left to the "date" rule.
+func notPast(fl validator.FieldLevel) bool {
+	d, err := time.Parse(DateLayout, fl.Field().String())
+	return err != nil || !d.Before(today())
+}
+
+// notFuture accepts today or any earlier date.
+func notFuture(fl validator.FieldLevel) bool {
+	d, err := time.Parse(DateLayout, fl.Field().String())
+	return err != nil || !d.After(today())
+}
+
+// onorafter=Field checks that a date is not before the date in another field
+// of the same struct. An empty or malformed other field is not compared.
+func onOrAfter(fl validator.FieldLevel) bool {
+	other, kind, _, ok := fl.GetStructFieldOK2()
+	if !ok || kind != reflect.String || other.String() == "" {
+		return true
+	}
+	start, err := time.Parse(DateLayout, other.String())
+	if err != nil {
+		return true
+	}
+	end, err := time.Parse(DateLayout, fl.Field().String())
+	return err != nil || !end.Before(start)
+}
+
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// ParseDate parses a date already checked by the "date" rule. An empty value
+// yields the zero time.
+func ParseDate(s string) time.Time {
+	d, _ := time.Parse(DateLayout, s)
+	return d
+}
@@ -0,0 +1,137 @@
+package dto
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+)
+
+func jsonContext(body string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	return ctx
+}
+
+// fieldErrors binds body into req and returns the failing fields by name.
+func fieldErrors(t *testing.T, body string, req any) map[string]string {
+	t.Helper()
+
+	err := BindJSON(jsonContext(body), req)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, apperrors.ErrValidation) {
+		t.Fatalf("error %v is not a validation error", err)
+	}
+	var appErr *apperrors.Error
+	errors.As(err, &appErr)
+
+	fields := make(map[string]string)
+	for _, f := range appErr.Fields {
+		fields[f.Field] = f.Message
+	}
+	return fields
+}
+
+func TestRegisterRequestReportsEveryField(t *testing.T) {
+	fields := fieldErrors(t, `{"email":"","username":"a b","password":"short"}`, &RegisterRequest{})
+
+	want := map[string]string{
+		"email":    "is required",
+		"username": "may contain only letters, digits, '_' and '.'",
+		"password": "must be at least 8 characters",
+	}
+	for field, msg := range want {
+		if fields[field] != msg {
+			t.Errorf("%s: got %q; want %q", field, fields[field], msg)
+		}
+	}
+}
+
+func TestRegisterRequestAcceptsValidInput(t *testing.T) {
+	if fields := fieldErrors(t, `{"email":"alice@example.com","username":"alice_1","password":"s3cret-pass"}`, &RegisterRequest{}); fields != nil {
+		t.Errorf("unexpected errors %v", fields)
+	}
+}
+
+func TestCreateJobPostRequestDates(t *testing.T) {
+	base := `{"job_title":"Chef","company_name":"Hotel","job_description":"Cook","last_date_to_apply":"%s"}`
+	tomorrow := time.Now().AddDate(0, 0, 2).Format(DateLayout)
+
+	tests := map[string]string{
+		"2020-01-01": "must not be in the past",
+		"01/02/2030": "must be a date in YYYY-MM-DD format",
+		tomorrow:     "",
+	}
+	for date, want := range tests {
+		var req CreateJobPostRequest
+		fields := fieldErrors(t, strings.Replace(base, "%s", date, 1), &req)
+		if got := fields["last_date_to_apply"]; got != want {
+			t.Errorf("%s: got %q; want %q", date, got, want)
+		}
+	}
+}
+
+func TestCreateJobPostRequestDefaultsClosingDate(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	var req CreateJobPostRequest
+
+	if got := req.JobPost(uuid.New(), now).LastDateToApply; !got.Equal(now.AddDate(0, 1, 0)) {
+		t.Errorf("LastDateToApply = %v; want one month after %v", got, now)
+	}
+}
+
+func TestExperienceRequestDateRange(t *testing.T) {
+	fields := fieldErrors(t, `{"job_title":"Chef","company_name":"Hotel","start_date":"2022-05-01","end_date":"2021-01-01"}`, &CreateExperienceRequest{})
+	if got := fields["end_date"]; got != "must not be before start_date" {
+		t.Errorf("end_date: got %q", got)
+	}
+}
+
+func TestNotificationRequestEnums(t *testing.T) {
+	fields := fieldErrors(t, `{"recipient_user_id":"7d444840-9dc0-11d1-b245-5ffdce74fad2","sender_user_id":"nope","type":"poke","message":"hi"}`, &CreateNotificationRequest{})
+	if got := fields["type"]; got != "must be one of: follow, like, comment, job, message" {
+		t.Errorf("type: got %q", got)
+	}
+	if got := fields["sender_user_id"]; got != "must be a valid UUID" {
+		t.Errorf("sender_user_id: got %q", got)
+	}
+}
+
+func TestBindJSONMalformedBody(t *testing.T) {
+	fields := fieldErrors(t, `{"email": 42}`, &ForgotPasswordRequest{})
+	if got := fields["email"]; got != "must be a string" {
+		t.Errorf("email: got %q", got)
+	}
+
+	if err := BindJSON(jsonContext(`{`), &ForgotPasswordRequest{}); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("truncated body: got %v; want a validation error", err)
+	}
+}
+
+func TestBindFormUsesFormNames(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	form := url.Values{"user_id": {"7d444840-9dc0-11d1-b245-5ffdce74fad2"}, "post_content": {"   "}}
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var appErr *apperrors.Error
+	if err := BindForm(ctx, &CreatePostRequest{}); !errors.As(err, &appErr) {
+		t.Fatalf("BindForm: got %v; want a validation error", err)
+	}
+	if len(appErr.Fields) != 1 || appErr.Fields[0].Field != "post_content" {
+		t.Errorf("fields = %+v; want post_content only", appErr.Fields)
+	}
+}
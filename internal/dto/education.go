@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// EducationRequest holds the fields shared by creating and updating an
+// education entry.
+type EducationRequest struct {
+	Degree          string `json:"degree" binding:"required,notblank,max=255"`
+	InstitutionName string `json:"institution_name" binding:"required,notblank,max=255"`
+	FieldOfStudy    string `json:"field_of_study" binding:"required,notblank,max=255"`
+	Grade           string `json:"grade" binding:"required,max=10"`
+	Year            string `json:"year" binding:"omitempty,len=4,numeric"` // e.g., "2023"
+}
+
+// CreateEducationRequest is the body of POST /user/education. The entry
+// belongs to the authenticated user.
+type CreateEducationRequest = EducationRequest
+
+// NewUserEducation builds a new education entry of userID to store.
+func (r EducationRequest) NewUserEducation(userID uuid.UUID) *models.UserEducation {
+	edu := r.UserEducation(uuid.New())
+	edu.UserID = userID
+	return edu
+}
+
+// UpdateEducationRequest is the body of PUT /user/education/:id.
+type UpdateEducationRequest = EducationRequest
+
+// UserEducation builds the education entry with the given id.
+func (r EducationRequest) UserEducation(id uuid.UUID) *models.UserEducation {
+	return &models.UserEducation{
+		ID:              id,
+		Degree:          r.Degree,
+		InstitutionName: r.InstitutionName,
+		FieldOfStudy:    r.FieldOfStudy,
+		Grade:           r.Grade,
+		Year:            r.Year,
+	}
+}
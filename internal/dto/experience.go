@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// ExperienceRequest holds the fields shared by creating and updating a work
+// experience entry. An empty end date means the position is current.
+type ExperienceRequest struct {
+	JobTitle       string `json:"job_title" binding:"required,notblank,max=255"`
+	CompanyName    string `json:"company_name" binding:"required,notblank,max=255"`
+	Location       string `json:"location" binding:"max=255"`
+	JobDescription string `json:"job_description" binding:"max=10000"`
+	Achievements   string `json:"achievements" binding:"max=10000"`
+	StartDate      string `json:"start_date" binding:"required,date,notfuture"`
+	EndDate        string `json:"end_date" binding:"omitempty,date,onorafter=StartDate"`
+}
+
+// CreateExperienceRequest is the body of POST /user/experience. The entry
+// belongs to the authenticated user.
+type CreateExperienceRequest = ExperienceRequest
+
+// NewUserExperience builds a new experience entry of userID to store.
+func (r ExperienceRequest) NewUserExperience(userID uuid.UUID) *models.UserExperience {
+	exp := r.UserExperience(uuid.Nil)
+	exp.UserID = userID
+	return exp
+}
+
+// UpdateExperienceRequest is the body of PUT /user/experience/:id.
+type UpdateExperienceRequest = ExperienceRequest
+
+// UserExperience builds the experience entry with the given id.
+func (r ExperienceRequest) UserExperience(id uuid.UUID) *models.UserExperience {
+	return &models.UserExperience{
+		ID:             id,
+		JobTitle:       r.JobTitle,
+		CompanyName:    r.CompanyName,
+		Location:       r.Location,
+		JobDescription: r.JobDescription,
+		Achievements:   r.Achievements,
+		StartDate:      r.StartDate,
+		EndDate:        r.EndDate,
+	}
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// CreateJobPostRequest is the body of POST /posts/job. The job is posted by
+// the authenticated user.
+type CreateJobPostRequest struct {
+	JobTitle        string `json:"job_title" binding:"required,notblank,max=255"`
+	CompanyName     string `json:"company_name" binding:"required,notblank,max=255"`
+	JobDescription  string `json:"job_description" binding:"required,notblank,max=10000"`
+	JobApplyURL     string `json:"job_apply_url" binding:"omitempty,http_url,max=255"`
+	Location        string `json:"location" binding:"max=255"`
+	LastDateToApply string `json:"last_date_to_apply" binding:"omitempty,date,notpast"`
+}
+
+// JobPost builds the job post of userID to store. Without a closing date
+// the post stays open for one month from now.
+func (r CreateJobPostRequest) JobPost(userID uuid.UUID, now time.Time) *models.JobPost {
+	lastDate := ParseDate(r.LastDateToApply)
+	if r.LastDateToApply == "" {
+		lastDate = now.AddDate(0, 1, 0)
+	}
+
+	return &models.JobPost{
+		UserID:          userID,
+		JobTitle:        r.JobTitle,
+		CompanyName:     r.CompanyName,
+		JobDescription:  r.JobDescription,
+		JobApplyURL:     r.JobApplyURL,
+		Location:        r.Location,
+		LastDateToApply: lastDate,
+		PostDate:        now,
+		CreatedAt:       now,
+	}
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+)
+
+// JobView is the public shape of a job post. The closing date is a calendar
+// date in DateLayout, the same format it is submitted in.
+type JobView struct {
+	ID              uuid.UUID `json:"id"`
+	UserID          uuid.UUID `json:"user_id"`
+	JobTitle        string    `json:"job_title"`
+	CompanyName     string    `json:"company_name"`
+	JobDescription  string    `json:"job_description"`
+	JobApplyURL     string    `json:"job_apply_url"`
+	Location        string    `json:"location"`
+	PostDate        time.Time `json:"post_date"`
+	LastDateToApply string    `json:"last_date_to_apply"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// NewJobViews renders every job post.
+func NewJobViews(jobs []models.JobPost) []*JobView {
+	views := make([]*JobView, 0, len(jobs))
+	for _, j := range jobs {
+		views = append(views, &JobView{
+			ID:              j.ID,
+			UserID:          j.UserID,
+			JobTitle:        j.JobTitle,
+			CompanyName:     j.CompanyName,
+			JobDescription:  j.JobDescription,
+			JobApplyURL:     j.JobApplyURL,
+			Location:        j.Location,
+			PostDate:        j.PostDate,
+			LastDateToApply: j.LastDateToApply.Format(DateLayout),
+			CreatedAt:       j.CreatedAt,
+		})
+	}
+	return views
+}
+
+// JobPage is a page of the job board, newest first.
+type JobPage struct {
+	Items      []*JobView `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// NewJobPage renders a page of job posts.
+func NewJobPage(page pagination.Page[models.JobPost]) JobPage {
+	return JobPage{Items: NewJobViews(page.Items), NextCursor: page.NextCursor}
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// CreateNotificationRequest is the body of POST /notifications/create.
+type CreateNotificationRequest struct {
+	RecipientUserID string `json:"recipient_user_id" binding:"required,uuid"`
+	SenderUserID    string `json:"sender_user_id" binding:"required,uuid"`
+	Type            string `json:"type" binding:"required,oneof=follow like comment job message"`
+	EntityID        string `json:"entity_id" binding:"omitempty,uuid"`
+	EntityType      string `json:"entity_type" binding:"omitempty,oneof=post comment user job"`
+	Message         string `json:"message" binding:"required,notblank,max=500"`
+}
+
+// Notification builds the notification to store.
+func (r CreateNotificationRequest) Notification() *models.Notification {
+	n := &models.Notification{
+		RecipientUserID: uuid.MustParse(r.RecipientUserID),
+		SenderUserID:    uuid.MustParse(r.SenderUserID),
+		Type:            r.Type,
+		EntityType:      r.EntityType,
+		Message:         r.Message,
+	}
+	if r.EntityID != "" {
+		n.EntityID = uuid.MustParse(r.EntityID)
+	}
+	return n
+}
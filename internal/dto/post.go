@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// CreatePostRequest holds the form fields of POST /posts/content. The
+// author is the current user. The attachments are read from the multipart
+// form separately; AltText describes them in the same order. Without a Visibility the post gets the default visibility of its
+// author.
+type CreatePostRequest struct {
+	PostContent string   `form:"post_content" binding:"required,notblank,max=5000"`
+	AltText     []string `form:"alt_text" binding:"max=10,dive,max=1000"`
+	Visibility  string   `form:"visibility" binding:"omitempty,oneof=public followers only_me"`
+}
+
+// ContentPost builds the post of userID to store with its already uploaded
+// attachments. MediaURL is the URL of the first one, for older clients.
+func (r CreatePostRequest) ContentPost(userID uuid.UUID, attachments []models.PostAttachment) *models.ContentPost {
+	post := &models.ContentPost{
+		UserID:      userID,
+		PostContent: strings.TrimSpace(r.PostContent),
+		Attachments: attachments,
+		Visibility:  r.Visibility,
+	}
+	if len(attachments) > 0 {
+		post.MediaURL = attachments[0].URL
+	}
+	return post
+}
+
+// UpdatePostRequest is the body of PATCH /posts/:post_id.
+type UpdatePostRequest struct {
+	PostContent string `json:"post_content" binding:"required,notblank,max=5000"`
+}
+
+// SetVisibilityRequest is the body of PUT /posts/:post_id/visibility.
+type SetVisibilityRequest struct {
+	Visibility string `json:"visibility" binding:"required,oneof=public followers only_me"`
+}
+
+// SaveDraftRequest is the body of POST /drafts and PUT /drafts/:draft_id.
+// A PublishAt schedules the post; without a Visibility it is published with
+// the default visibility of its author.
+type SaveDraftRequest struct {
+	PostContent string     `json:"post_content" binding:"required,notblank,max=5000"`
+	Visibility  string     `json:"visibility" binding:"omitempty,oneof=public followers only_me"`
+	PublishAt   *time.Time `json:"publish_at"`
+}
+
+// Draft builds the draft of userID to store. PublishAt is kept in UTC,
+// whatever offset the client sent it with.
+func (r SaveDraftRequest) Draft(userID uuid.UUID) *models.PostDraft {
+	draft := &models.PostDraft{
+		UserID:      userID,
+		PostContent: strings.TrimSpace(r.PostContent),
+		Visibility:  r.Visibility,
+	}
+	if r.PublishAt != nil {
+		publishAt := r.PublishAt.UTC()
+		draft.PublishAt = &publishAt
+	}
+	return draft
+}
+
+// CreatePollRequest is the body of POST /polls. The question is the text of
+// the poll post.
+type CreatePollRequest struct {
+	Question       string    `json:"question" binding:"required,notblank,max=500"`
+	Options        []string  `json:"options" binding:"required,min=2,max=4,dive,notblank,max=100"`
+	EndsAt         time.Time `json:"ends_at" binding:"required"`
+	MultipleChoice bool      `json:"multiple_choice"`
+	Visibility     string    `json:"visibility" binding:"omitempty,oneof=public followers only_me"`
+}
+
+// ContentPost builds the poll post of userID to store. EndsAt is kept in
+// UTC, whatever offset the client sent it with.
+func (r CreatePollRequest) ContentPost(userID uuid.UUID) *models.ContentPost {
+	poll := &models.Poll{MultipleChoice: r.MultipleChoice, EndsAt: r.EndsAt.UTC()}
+	for _, label := range r.Options {
+		poll.Options = append(poll.Options, models.PollOption{Label: strings.TrimSpace(label)})
+	}
+	return &models.ContentPost{
+		UserID:      userID,
+		PostContent: strings.TrimSpace(r.Question),
+		Visibility:  r.Visibility,
+		Poll:        poll,
+	}
+}
+
+// VoteRequest is the body of POST /posts/:post_id/poll/votes. A single
+// choice poll takes one option.
+type VoteRequest struct {
+	OptionIDs []string `json:"option_ids" binding:"required,min=1,max=4,unique,dive,uuid"`
+}
+
+// Options returns the chosen options.
+func (r VoteRequest) Options() []uuid.UUID {
+	ids := make([]uuid.UUID, len(r.OptionIDs))
+	for i, id := range r.OptionIDs {
+		ids[i] = uuid.MustParse(id)
+	}
+	return ids
+}
+
+// QuotePostRequest is the body of POST /posts/:post_id/quotes.
+type QuotePostRequest struct {
+	PostContent string `json:"post_content" binding:"required,notblank,max=5000"`
+}
+
+// CreateCommentRequest is the body of POST /posts/:post_id/comment.
+type CreateCommentRequest struct {
+	Comment string `json:"comment" binding:"required,notblank,max=500"`
+}
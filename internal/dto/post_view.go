@@ -0,0 +1,269 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ranking"
+)
+
+// PostView is the public shape of a content post.
+type PostView struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	PostContent string     `json:"post_content"`
+	MediaURL    string     `json:"media_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Edited      bool       `json:"edited"`
+	EditedAt    *time.Time `json:"edited_at,omitempty"`
+	Visibility  string     `json:"visibility"`
+	PostType    string     `json:"post_type"`
+	Author      *UserView  `json:"author,omitempty"`
+
+	// ShareType is "repost" or "quote" when the post shares SharedPostID.
+	ShareType    string     `json:"share_type,omitempty"`
+	SharedPostID *uuid.UUID `json:"shared_post_id,omitempty"`
+
+	Attachments []*AttachmentView `json:"attachments"`
+	Entities    []models.Entity   `json:"entities"`
+	Poll        *PollView         `json:"poll,omitempty"`
+}
+
+// NewPostView renders p for viewer.
+func NewPostView(p *models.ContentPost, viewer Viewer) *PostView {
+	return &PostView{
+		ID:           p.ID,
+		UserID:       p.UserID,
+		PostContent:  p.PostContent,
+		MediaURL:     p.MediaURL,
+		CreatedAt:    p.CreatedAt,
+		Edited:       p.EditedAt != nil,
+		EditedAt:     p.EditedAt,
+		Visibility:   p.Visibility,
+		PostType:     p.PostType,
+		Author:       NewUserView(p.User, viewer),
+		ShareType:    p.ShareType,
+		SharedPostID: p.SharedPostID,
+		Attachments:  NewAttachmentViews(p.Attachments),
+		Entities:     NewEntities(p.Entities),
+		Poll:         NewPollView(p.Poll, time.Now()),
+	}
+}
+
+// NewPostViews renders every post for viewer.
+func NewPostViews(posts []models.ContentPost, viewer Viewer) []*PostView {
+	views := make([]*PostView, 0, len(posts))
+	for i := range posts {
+		views = append(views, NewPostView(&posts[i], viewer))
+	}
+	return views
+}
+
+// PostPage is a page of the posts of a user, newest first.
+type PostPage struct {
+	Items      []*PostView `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// NewPostPage renders a page of posts for viewer.
+func NewPostPage(page pagination.Page[models.ContentPost], viewer Viewer) PostPage {
+	return PostPage{Items: NewPostViews(page.Items, viewer), NextCursor: page.NextCursor}
+}
+
+// AttachmentView is a media file attached to a post. Its metadata is read
+// from the file: Width and Height are set for images and MP4 or QuickTime
+// videos, DurationSeconds for those videos.
+type AttachmentView struct {
+	URL             string   `json:"url"`
+	MimeType        string   `json:"mime_type"`
+	SizeBytes       int64    `json:"size_bytes"`
+	Width           *int     `json:"width,omitempty"`
+	Height          *int     `json:"height,omitempty"`
+	DurationSeconds *float64 `json:"duration_seconds,omitempty"`
+	AltText         string   `json:"alt_text"`
+}
+
+// NewAttachmentViews renders the attachments of a post in their order.
+func NewAttachmentViews(attachments []models.PostAttachment) []*AttachmentView {
+	views := make([]*AttachmentView, 0, len(attachments))
+	for _, a := range attachments {
+		views = append(views, &AttachmentView{
+			URL:             a.URL,
+			MimeType:        a.MimeType,
+			SizeBytes:       a.SizeBytes,
+			Width:           a.Width,
+			Height:          a.Height,
+			DurationSeconds: a.DurationSeconds,
+			AltText:         a.AltText,
+		})
+	}
+	return views
+}
+
+// PollView is the poll of a poll post. The number of voters and the votes
+// of the options are only shown once the viewer voted or the poll closed;
+// Choices are the options the viewer voted for.
+type PollView struct {
+	MultipleChoice bool              `json:"multiple_choice"`
+	EndsAt         time.Time         `json:"ends_at"`
+	Closed         bool              `json:"closed"`
+	TotalVoters    *int              `json:"total_voters,omitempty"`
+	Options        []*PollOptionView `json:"options"`
+	Choices        []uuid.UUID       `json:"choices"`
+}
+
+// PollOptionView is an answer of a poll. Votes is missing while the results
+// are hidden.
+type PollOptionView struct {
+	ID    uuid.UUID `json:"id"`
+	Label string    `json:"label"`
+	Votes *int      `json:"votes,omitempty"`
+}
+
+// NewPollView renders a poll as read by its viewer at now; it is nil for
+// posts without a poll.
+func NewPollView(p *models.Poll, now time.Time) *PollView {
+	if p == nil {
+		return nil
+	}
+	v := &PollView{
+		MultipleChoice: p.MultipleChoice,
+		EndsAt:         p.EndsAt,
+		Closed:         !now.Before(p.EndsAt),
+		Options:        make([]*PollOptionView, 0, len(p.Options)),
+		Choices:        p.Choices,
+	}
+	if v.Choices == nil {
+		v.Choices = []uuid.UUID{}
+	}
+	results := v.Closed || len(p.Choices) > 0
+	if results {
+		voters := p.TotalVoters
+		v.TotalVoters = &voters
+	}
+	for _, o := range p.Options {
+		ov := &PollOptionView{ID: o.ID, Label: o.Label}
+		if results {
+			votes := o.Votes
+			ov.Votes = &votes
+		}
+		v.Options = append(v.Options, ov)
+	}
+	return v
+}
+
+// NewEntities renders the hashtags and mentions of a text, an empty list
+// when it has none. Offsets are in Unicode code points.
+func NewEntities(entities []models.Entity) []models.Entity {
+	if entities == nil {
+		return []models.Entity{}
+	}
+	return entities
+}
+
+// FeedPostView is a post in the feed together with its author's profile
+// summary and engagement counts.
+type FeedPostView struct {
+	PostID        uuid.UUID  `json:"post_id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	ProfileImage  string     `json:"profile_image"`
+	FullName      string     `json:"full_name"`
+	Designation   string     `json:"designation"`
+	PostContent   string     `json:"post_content"`
+	MediaURL      *string    `json:"media_url"`
+	TotalLikes    int        `json:"total_likes"`
+	TotalComments int        `json:"total_comments"`
+	TotalShares   int        `json:"total_shares"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Edited        bool       `json:"edited"`
+	EditedAt      *time.Time `json:"edited_at,omitempty"`
+	Visibility    string     `json:"visibility"`
+	PostType      string     `json:"post_type"`
+
+	// ShareType is "repost" or "quote" when the post shares another.
+	// SharedPost is that post; it is missing once the post is deleted.
+	ShareType  string        `json:"share_type,omitempty"`
+	SharedPost *FeedPostView `json:"shared_post,omitempty"`
+
+	Attachments []*AttachmentView `json:"attachments"`
+	Entities    []models.Entity   `json:"entities"`
+	Poll        *PollView         `json:"poll,omitempty"`
+
+	// Debug is the ranking score of the post, shown to admins on request.
+	Debug *ScoreView `json:"debug,omitempty"`
+}
+
+// ScoreView is the score a ranker gave a post and the contribution of each
+// of its scorers.
+type ScoreView struct {
+	Total      float64            `json:"total"`
+	Components map[string]float64 `json:"components"`
+}
+
+// NewFeedPostViews renders the feed.
+func NewFeedPostViews(posts []models.PostWithDetails) []*FeedPostView {
+	views := make([]*FeedPostView, 0, len(posts))
+	for i := range posts {
+		views = append(views, newFeedPostView(&posts[i]))
+	}
+	return views
+}
+
+func newFeedPostView(p *models.PostWithDetails) *FeedPostView {
+	v := &FeedPostView{
+		PostID:        p.PostID,
+		UserID:        p.UserID,
+		ProfileImage:  p.ProfileImage,
+		FullName:      p.FullName,
+		Designation:   p.Designation,
+		PostContent:   p.PostContent,
+		MediaURL:      p.MediaURL,
+		TotalLikes:    p.TotalLikes,
+		TotalComments: p.TotalComments,
+		TotalShares:   p.TotalShares,
+		CreatedAt:     p.CreatedAt,
+		Edited:        p.EditedAt != nil,
+		EditedAt:      p.EditedAt,
+		Visibility:    p.Visibility,
+		PostType:      p.PostType,
+		ShareType:     p.ShareType,
+		Attachments:   NewAttachmentViews(p.Attachments),
+		Entities:      NewEntities(p.Entities),
+		Poll:          NewPollView(p.Poll, time.Now()),
+	}
+	if p.SharedPost != nil {
+		v.SharedPost = newFeedPostView(p.SharedPost)
+	}
+	return v
+}
+
+// FeedPage is a page of the feed, newest first.
+type FeedPage struct {
+	Items      []*FeedPostView `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// NewFeedPage renders a page of the feed.
+func NewFeedPage(page pagination.Page[models.PostWithDetails]) FeedPage {
+	return FeedPage{Items: NewFeedPostViews(page.Items), NextCursor: page.NextCursor}
+}
+
+// HomeFeedPage is a page of the home feed. Source is "following" when it
+// lists posts of the viewer and the people they follow, or "popular" when
+// those have nothing to show yet. Ranker names the ranker of a top feed.
+type HomeFeedPage struct {
+	FeedPage
+	Source string `json:"source"`
+	Ranker string `json:"ranker,omitempty"`
+}
+
+// WithScores adds the score of every post to the page; scores[i] is the
+// score of the i-th item.
+func (p HomeFeedPage) WithScores(scores []ranking.Score) HomeFeedPage {
+	for i, s := range scores {
+		p.Items[i].Debug = &ScoreView{Total: s.Total, Components: s.Components}
+	}
+	return p
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// CreateUserProfileRequest holds the form fields of POST /user/profile. The
+// profile belongs to the authenticated user, and the optional profile image
+// is read from the multipart form separately.
+type CreateUserProfileRequest struct {
+	FullName            string `form:"full_name" binding:"required,notblank,max=255"`
+	Designation         string `form:"designation" binding:"max=255"`
+	Organization        string `form:"organization" binding:"max=255"`
+	ProfessionalSummary string `form:"professional_summary" binding:"max=2000"`
+	Location            string `form:"location" binding:"max=255"`
+	Email               string `form:"email" binding:"required,email,max=255"`
+	ContactNumber       string `form:"contact_number" binding:"omitempty,phone"`
+	DefaultVisibility   string `form:"default_visibility" binding:"omitempty,oneof=public followers only_me"`
+}
+
+// UserProfile builds the profile of userID to store.
+func (r CreateUserProfileRequest) UserProfile(userID uuid.UUID) *models.UserProfile {
+	return &models.UserProfile{
+		UserID:              userID,
+		FullName:            CleanString(r.FullName),
+		Designation:         OptionalString(r.Designation),
+		Organization:        OptionalString(r.Organization),
+		ProfessionalSummary: OptionalString(r.ProfessionalSummary),
+		Location:            OptionalString(r.Location),
+		Email:               CleanString(r.Email),
+		ContactNumber:       OptionalString(r.ContactNumber),
+		DefaultVisibility:   r.DefaultVisibility,
+	}
+}
+
+// UpdateUserProfileRequest holds the form fields of PUT
+// /user/profile/update/:user_id. Every field is replaced, as before, so the
+// required ones must be sent again; only DefaultVisibility is kept when it
+// is left out.
+type UpdateUserProfileRequest struct {
+	FullName            string `form:"full_name" binding:"required,notblank,max=255"`
+	Designation         string `form:"designation" binding:"max=255"`
+	Organization        string `form:"organization" binding:"max=255"`
+	ProfessionalSummary string `form:"professional_summary" binding:"max=2000"`
+	Location            string `form:"location" binding:"max=255"`
+	Email               string `form:"email" binding:"required,email,max=255"`
+	ContactNumber       string `form:"contact_number" binding:"omitempty,phone"`
+	DefaultVisibility   string `form:"default_visibility" binding:"omitempty,oneof=public followers only_me"`
+}
+
+// UserProfile builds the updated profile of userID.
+func (r UpdateUserProfileRequest) UserProfile(userID uuid.UUID) *models.UserProfile {
+	return &models.UserProfile{
+		UserID:              userID,
+		FullName:            CleanString(r.FullName),
+		Designation:         OptionalString(r.Designation),
+		Organization:        OptionalString(r.Organization),
+		ProfessionalSummary: OptionalString(r.ProfessionalSummary),
+		Location:            OptionalString(r.Location),
+		Email:               CleanString(r.Email),
+		ContactNumber:       OptionalString(r.ContactNumber),
+		DefaultVisibility:   r.DefaultVisibility,
+	}
+}
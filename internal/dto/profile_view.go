@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+)
+
+// ProfileView is the public shape of a user profile. Email, contact number
+// and default post visibility are only included for the profile owner.
+type ProfileView struct {
+	ID                  uuid.UUID `json:"id"`
+	UserID              uuid.UUID `json:"user_id"`
+	ProfileImage        *string   `json:"profile_image"`
+	FullName            string    `json:"full_name"`
+	Designation         *string   `json:"designation"`
+	Organization        *string   `json:"organization"`
+	ProfessionalSummary *string   `json:"professional_summary"`
+	Location            *string   `json:"location"`
+	Email               string    `json:"email,omitempty"`
+	ContactNumber       *string   `json:"contact_number,omitempty"`
+	DefaultVisibility   string    `json:"default_visibility,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// NewProfileView renders p for viewer.
+func NewProfileView(p *models.UserProfile, viewer Viewer) *ProfileView {
+	view := &ProfileView{
+		ID:                  p.ID,
+		UserID:              p.UserID,
+		ProfileImage:        p.ProfileImage,
+		FullName:            p.FullName,
+		Designation:         p.Designation,
+		Organization:        p.Organization,
+		ProfessionalSummary: p.ProfessionalSummary,
+		Location:            p.Location,
+		CreatedAt:           p.CreatedAt,
+		UpdatedAt:           p.UpdatedAt,
+	}
+	if viewer.CanSeeContact(p.UserID.String()) {
+		view.Email = p.Email
+		view.ContactNumber = p.ContactNumber
+		view.DefaultVisibility = p.DefaultVisibility
+	}
+	return view
+}
+
+// NewProfileViews renders every profile for viewer.
+func NewProfileViews(profiles []*models.UserProfile, viewer Viewer) []*ProfileView {
+	views := make([]*ProfileView, 0, len(profiles))
+	for _, p := range profiles {
+		views = append(views, NewProfileView(p, viewer))
+	}
+	return views
+}
+
+// ProfilePage is a page of the profile directory, newest first.
+type ProfilePage struct {
+	Items      []*ProfileView `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// NewProfilePage renders a page of profiles for viewer.
+func NewProfilePage(page pagination.Page[*models.UserProfile], viewer Viewer) ProfilePage {
+	return ProfilePage{Items: NewProfileViews(page.Items, viewer), NextCursor: page.NextCursor}
+}
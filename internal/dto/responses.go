@@ -0,0 +1,130 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+)
+
+// MessageResponse is the body of endpoints that only confirm an action.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// LoginResponse is the body of a successful login. The token is also set as
+// the "token" cookie and the CSRF token as the "csrf_token" cookie; browser
+// clients send the latter back in the X-CSRF-Token header.
+type LoginResponse struct {
+	Token     string `json:"token"`
+	UserID    string `json:"userID"`
+	CSRFToken string `json:"csrf_token"`
+}
+
+// PostCreatedResponse is the body of POST /posts/content.
+type PostCreatedResponse struct {
+	Message     string            `json:"message"`
+	PostContent string            `json:"post_content"`
+	MediaURL    string            `json:"media_url,omitempty"`
+	Visibility  string            `json:"visibility"`
+	Attachments []*AttachmentView `json:"attachments"`
+	Entities    []models.Entity   `json:"entities"`
+}
+
+// ProfileCreatedResponse is the body of POST /user/profile.
+type ProfileCreatedResponse struct {
+	Message string       `json:"message"`
+	Profile *ProfileView `json:"profile"`
+}
+
+// VideoResponse is the body of video uploads and replacements.
+type VideoResponse struct {
+	Message  string `json:"message"`
+	VideoURL string `json:"video_url"`
+}
+
+// UploadResponse is the body of POST /user/upload.
+type UploadResponse struct {
+	URL string `json:"url"`
+}
+
+// FollowersResponse lists a page of the ids of a user's followers, latest
+// follow first.
+type FollowersResponse struct {
+	Followers  []uuid.UUID `json:"followers"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// FollowingsResponse lists a page of the ids of the users a user follows,
+// latest follow first.
+type FollowingsResponse struct {
+	Followings []uuid.UUID `json:"followings"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// LikesResponse lists a page of the ids of the users who liked a post,
+// latest like first.
+type LikesResponse struct {
+	Likes      []uuid.UUID `json:"likes"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// CommentsResponse lists a page of the comments on a post, newest first.
+type CommentsResponse struct {
+	Comments   []models.PostComment `json:"comments"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// TrendingHashtagsResponse lists the hashtags used most over the last
+// WindowHours hours, most used first.
+type TrendingHashtagsResponse struct {
+	WindowHours int                   `json:"window_hours"`
+	Hashtags    []models.HashtagCount `json:"hashtags"`
+}
+
+// NotificationPage is a page of a user's notifications, newest first.
+type NotificationPage struct {
+	Items      []models.Notification `json:"items"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+// NewNotificationPage renders a page of notifications; an empty page has an
+// empty list of items.
+func NewNotificationPage(page pagination.Page[models.Notification]) NotificationPage {
+	items := page.Items
+	if items == nil {
+		items = []models.Notification{}
+	}
+	return NotificationPage{Items: items, NextCursor: page.NextCursor}
+}
+
+// RevisionPage is a page of the earlier versions of a post, latest first.
+type RevisionPage struct {
+	Items      []models.PostRevision `json:"items"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+// NewRevisionPage renders a page of revisions; an empty page has an empty
+// list of items.
+func NewRevisionPage(page pagination.Page[models.PostRevision]) RevisionPage {
+	items := page.Items
+	if items == nil {
+		items = []models.PostRevision{}
+	}
+	return RevisionPage{Items: items, NextCursor: page.NextCursor}
+}
+
+// DraftPage is a page of a user's drafts or scheduled posts, newest first.
+type DraftPage struct {
+	Items      []models.PostDraft `json:"items"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// NewDraftPage renders a page of drafts; an empty page has an empty list of
+// items.
+func NewDraftPage(page pagination.Page[models.PostDraft]) DraftPage {
+	items := page.Items
+	if items == nil {
+		items = []models.PostDraft{}
+	}
+	return DraftPage{Items: items, NextCursor: page.NextCursor}
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CleanString trims s and strips one level of quoting that some form clients
+// add around values.
+func CleanString(s string) string {
+	s = strings.TrimSpace(s)
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return s
+	}
+	return unquoted
+}
+
+// OptionalString returns nil for an empty value and a pointer to the cleaned
+// value otherwise, matching the nullable profile columns.
+func OptionalString(s string) *string {
+	cleaned := CleanString(s)
+	if cleaned == "" {
+		return nil
+	}
+	return &cleaned
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+
+// UserView is the public shape of a user account. The email is only included
+// for the user themselves.
+type UserView struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
+}
+
+// NewUserView renders u for viewer.
+func NewUserView(u *models.User, viewer Viewer) *UserView {
+	if u == nil {
+		return nil
+	}
+
+	view := &UserView{ID: u.ID, Username: u.Username}
+	if viewer.CanSeeContact(u.ID) {
+		view.Email = u.Email
+	}
+	return view
+}
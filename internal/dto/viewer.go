@@ -0,0 +1,18 @@
+package dto
+
+// Viewer is the authenticated user a response is rendered for. Views use it
+// to decide which private fields to include.
+type Viewer struct {
+	UserID string
+}
+
+// IsSelf reports whether the viewer is the user with the given id.
+func (v Viewer) IsSelf(userID string) bool {
+	return v.UserID != "" && v.UserID == userID
+}
+
+// CanSeeContact reports whether the viewer may see the email address and
+// phone number of the user with the given id. Only users themselves can.
+func (v Viewer) CanSeeContact(userID string) bool {
+	return v.IsSelf(userID)
+}
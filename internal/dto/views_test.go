@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+func TestUserNeverSerializesPasswordHash(t *testing.T) {
+	data, err := json.Marshal(models.User{ID: "u1", PasswordHash: "$2a$10$secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "password") || strings.Contains(string(data), "secret") {
+		t.Errorf("user JSON leaks the password hash: %s", data)
+	}
+}
+
+func TestProfileViewHidesContactFromOthers(t *testing.T) {
+	owner := uuid.New()
+	phone := "+91 98765 43210"
+	profile := &models.UserProfile{UserID: owner, FullName: "Alice", Email: "alice@example.com", ContactNumber: &phone}
+
+	data, _ := json.Marshal(NewProfileView(profile, Viewer{UserID: uuid.NewString()}))
+	for _, key := range []string{`"email"`, `"contact_number"`, "alice@example.com"} {
+		if strings.Contains(string(data), key) {
+			t.Errorf("profile seen by another user contains %s: %s", key, data)
+		}
+	}
+	if !strings.Contains(string(data), `"full_name":"Alice"`) {
+		t.Errorf("profile is not snake_case: %s", data)
+	}
+
+	self := NewProfileView(profile, Viewer{UserID: owner.String()})
+	if self.Email != profile.Email || self.ContactNumber == nil {
+		t.Errorf("owner view = %+v; want email and contact number", self)
+	}
+}
+
+func TestPostViewAuthorEmail(t *testing.T) {
+	post := models.ContentPost{ID: uuid.New(), User: &models.User{ID: "author", Username: "alice", Email: "alice@example.com"}}
+
+	views := NewPostViews([]models.ContentPost{post}, Viewer{UserID: "someone-else"})
+	if views[0].Author == nil || views[0].Author.Username != "alice" || views[0].Author.Email != "" {
+		t.Errorf("author = %+v; want username without email", views[0].Author)
+	}
+
+	views = NewPostViews([]models.ContentPost{post}, Viewer{UserID: "author"})
+	if views[0].Author.Email != "alice@example.com" {
+		t.Errorf("author email hidden from the author")
+	}
+}
+
+func TestPollViewHidesResultsUntilVotedOrClosed(t *testing.T) {
+	now := time.Now()
+	option := models.PollOption{ID: uuid.New(), Label: "Night", Votes: 3}
+	poll := &models.Poll{EndsAt: now.Add(time.Hour), Options: []models.PollOption{option}, TotalVoters: 3}
+
+	data, _ := json.Marshal(NewPollView(poll, now))
+	if strings.Contains(string(data), `"votes"`) || strings.Contains(string(data), `"total_voters"`) {
+		t.Errorf("open poll before voting shows votes: %s", data)
+	}
+	for name, v := range map[string]*PollView{
+		"voted":  NewPollView(&models.Poll{EndsAt: poll.EndsAt, Options: poll.Options, TotalVoters: 3, Choices: []uuid.UUID{option.ID}}, now),
+		"closed": NewPollView(poll, poll.EndsAt),
+	} {
+		if v.Options[0].Votes == nil || *v.Options[0].Votes != 3 {
+			t.Errorf("%s poll votes = %v; want 3", name, v.Options[0].Votes)
+		}
+		if v.TotalVoters == nil || *v.TotalVoters != 3 {
+			t.Errorf("%s poll voters = %v; want 3", name, v.TotalVoters)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Package entities finds #hashtags and @mentions in the text of posts and
+// comments.
+package entities
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// Maximum lengths of a hashtag and a username, in code points.
+const (
+	MaxHashtagLength  = 100
+	MaxUsernameLength = 100
+)
+
+// Parse returns the hashtags and mentions of text in order. A hashtag is #
+// followed by letters, digits and underscores, at least one of them a
+// letter; a mention is @ followed by the characters of a username, minus a
+// trailing period. Either must start the text or follow a character that
+// cannot be part of a word, so e-mail addresses are not mentions. Mentions
+// are not resolved to users.
+func Parse(text string) []models.Entity {
+	runes := []rune(text)
+	var found []models.Entity
+	for i := 0; i < len(runes); i++ {
+		prefix := runes[i]
+		if (prefix != '#' && prefix != '@') || (i > 0 && isWordRune(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		if prefix == '#' {
+			for end < len(runes) && isHashtagRune(runes[end]) {
+				end++
+			}
+		} else {
+			for end < len(runes) && isUsernameRune(runes[end]) {
+				end++
+			}
+			for end > i+1 && runes[end-1] == '.' {
+				end--
+			}
+		}
+		body := string(runes[i+1 : end])
+		if end == i+1 {
+			continue
+		}
+
+		if prefix == '#' {
+			if end-i-1 > MaxHashtagLength || !strings.ContainsFunc(body, unicode.IsLetter) {
+				continue
+			}
+			found = append(found, models.Entity{Type: models.EntityHashtag, Start: i, End: end, Text: strings.ToLower(body)})
+		} else {
+			if end-i-1 > MaxUsernameLength {
+				continue
+			}
+			found = append(found, models.Entity{Type: models.EntityMention, Start: i, End: end, Text: body})
+		}
+		i = end - 1
+	}
+	return found
+}
+
+// Hashtags returns the distinct tags among entities in order.
+func Hashtags(entities []models.Entity) []string {
+	return distinct(entities, models.EntityHashtag)
+}
+
+// Mentions returns the distinct mentioned usernames among entities in order.
+func Mentions(entities []models.Entity) []string {
+	return distinct(entities, models.EntityMention)
+}
+
+// NormalizeTag returns tag lower-cased without a leading #, or "" when it
+// is not a valid hashtag.
+func NormalizeTag(tag string) string {
+	tag = strings.TrimPrefix(tag, "#")
+	parsed := Parse("#" + tag)
+	if len(parsed) != 1 || parsed[0].End != utf8.RuneCountInString(tag)+1 {
+		return ""
+	}
+	return parsed[0].Text
+}
+
+func distinct(entities []models.Entity, kind string) []string {
+	var values []string
+	seen := map[string]bool{}
+	for _, e := range entities {
+		if e.Type == kind && !seen[e.Text] {
+			seen[e.Text] = true
+			values = append(values, e.Text)
+		}
+	}
+	return values
+}
+
+func isHashtagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+func isUsernameRune(r rune) bool {
+	return r == '_' || r == '.' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
+
+func isWordRune(r rune) bool {
+	return isHashtagRune(r) || isUsernameRune(r) || r == '#' || r == '@'
+}
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	got := Parse("Thanks @chef.anna. Café #GoLang, #2024 #año_nuevo and mail me at bob@example.com #go")
+	want := []models.Entity{
+		{Type: models.EntityMention, Start: 7, End: 17, Text: "chef.anna"},
+		{Type: models.EntityHashtag, Start: 24, End: 31, Text: "golang"},
+		{Type: models.EntityHashtag, Start: 39, End: 49, Text: "año_nuevo"},
+		{Type: models.EntityHashtag, Start: 81, End: 84, Text: "go"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := Hashtags(Parse("#Go #go #rust")); !reflect.DeepEqual(got, []string{"go", "rust"}) {
+		t.Errorf("Hashtags = %v; want [go rust]", got)
+	}
+	if got := Mentions(Parse("@a @b @a")); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Mentions = %v; want [a b]", got)
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	for tag, want := range map[string]string{"GoLang": "golang", "#go": "go", "año": "año", "2024": "", "go lang": "", "": ""} {
+		if got := NormalizeTag(tag); got != want {
+			t.Errorf("NormalizeTag(%q) = %q; want %q", tag, got, want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package idempotency lets clients retry unsafe requests without repeating
+// their effects. A client names each logical request with an Idempotency-Key
+// header; the first successful response under a key is kept in a Store and
+// replayed for retries of the same request until the key expires.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"mime"
+	"time"
+)
+
+const (
+	// Header carries the key chosen by the client.
+	Header = "Idempotency-Key"
+	// ReplayedHeader marks responses replayed from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+	// MaxKeyLength bounds the keys clients may send.
+	MaxKeyLength = 255
+	// MaxBodySize bounds the bodies of requests carrying a key, which are
+	// read into memory to be fingerprinted.
+	MaxBodySize = 32 << 20
+)
+
+// Policy controls how long keys are held.
+type Policy struct {
+	// TTL is how long a key is remembered after its first request.
+	TTL time.Duration
+	// LockTimeout is how long a request may hold a key before it is
+	// presumed lost, for example with a crashed replica, and a retry may
+	// claim the key again.
+	LockTimeout time.Duration
+}
+
+// Response is a stored response.
+type Response struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// ErrLockLost is returned by Complete and Release when a retry took the key
+// over after the lock timeout; the outcome of the retry is kept.
+var ErrLockLost = errors.New("idempotency: key was taken over by another request")
+
+// Lock is a claim on a key, identified by the time it was taken. Complete
+// and Release only act on the key while the same claim holds it.
+type Lock struct {
+	Key      string
+	LockedAt time.Time
+}
+
+// Record is the state of a key claimed by an earlier request.
+type Record struct {
+	// Fingerprint identifies the request that claimed the key.
+	Fingerprint string
+	// Response is nil while that request is still running.
+	Response *Response
+}
+
+// Store keeps the keys.
+type Store interface {
+	// Reserve claims key for the request identified by fingerprint. It
+	// returns the lock taken and a nil record when the key was free,
+	// expired or abandoned, and the record of the earlier request
+	// otherwise.
+	Reserve(ctx context.Context, key, fingerprint string, policy Policy) (Lock, *Record, error)
+
+	// Complete stores the response of the request holding lock.
+	Complete(ctx context.Context, lock Lock, resp Response) error
+
+	// Release frees a key whose request failed, so it can be retried.
+	// Keys with a stored response are kept.
+	Release(ctx context.Context, lock Lock) error
+
+	// Prune forgets expired keys.
+	Prune(ctx context.Context) error
+}
+
+// Fingerprint identifies a request by its method, path and body, so a key
+// reused for a different request can be told apart from a retry. The
+// boundary of a multipart body is left out, since clients pick a new one
+// for every attempt.
+func Fingerprint(method, path, contentType string, body []byte) string {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if boundary := params["boundary"]; boundary != "" {
+		body = bytes.ReplaceAll(body, []byte(boundary), nil)
+	}
+
+	h := sha256.New()
+	for _, part := range []string{method, path, mediaType} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,68 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFingerprintIgnoresMultipartBoundary(t *testing.T) {
+	body := func(boundary string) []byte {
+		return []byte("--" + boundary + "\r\nContent-Disposition: form-data; name=\"title\"\r\n\r\nhello\r\n--" + boundary + "--\r\n")
+	}
+	a := Fingerprint("POST", "/posts", "multipart/form-data; boundary=aaa111", body("aaa111"))
+	b := Fingerprint("POST", "/posts", "multipart/form-data; boundary=bbb222", body("bbb222"))
+	if a != b {
+		t.Error("retries with a new boundary have different fingerprints")
+	}
+
+	json := []byte(`{"title":"hello"}`)
+	if Fingerprint("POST", "/posts", "application/json", json) == Fingerprint("POST", "/posts/2", "application/json", json) {
+		t.Error("requests to different paths share a fingerprint")
+	}
+	if Fingerprint("POST", "/posts", "application/json", json) == Fingerprint("POST", "/posts", "application/json", []byte(`{}`)) {
+		t.Error("requests with different bodies share a fingerprint")
+	}
+}
+
+func TestMemoryStoreLocksAndExpiresKeys(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.Now = func() time.Time { return now }
+	policy := Policy{TTL: 24 * time.Hour, LockTimeout: time.Minute}
+	ctx := context.Background()
+
+	first, rec, _ := store.Reserve(ctx, "k", "fp", policy)
+	if rec != nil {
+		t.Fatalf("first reserve = %+v; want nil", rec)
+	}
+	if _, rec, _ := store.Reserve(ctx, "k", "fp", policy); rec == nil || rec.Response != nil {
+		t.Fatalf("reserve while running = %+v; want in progress", rec)
+	}
+
+	// A request that never finished gives the key up after the lock timeout.
+	now = now.Add(2 * time.Minute)
+	lock, rec, _ := store.Reserve(ctx, "k", "fp", policy)
+	if rec != nil {
+		t.Fatalf("reserve after lock timeout = %+v; want nil", rec)
+	}
+
+	// The request that timed out no longer owns the key.
+	if err := store.Release(ctx, first); err != ErrLockLost {
+		t.Fatalf("release by the timed out request = %v; want ErrLockLost", err)
+	}
+
+	store.Complete(ctx, lock, Response{Status: 201, ContentType: "application/json", Body: []byte(`{}`)})
+	store.Release(ctx, lock)
+	now = now.Add(time.Hour)
+	_, rec, _ = store.Reserve(ctx, "k", "other", policy)
+	if rec == nil || rec.Fingerprint != "fp" || rec.Response == nil || rec.Response.Status != 201 {
+		t.Fatalf("reserve after completion = %+v; want the stored response", rec)
+	}
+
+	now = now.Add(24 * time.Hour)
+	store.Prune(ctx)
+	if len(store.entries) != 0 {
+		t.Fatalf("expired keys kept: %v", store.entries)
+	}
+}
@@ -0,0 +1,89 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	record    Record
+	lockedAt  time.Time
+	expiresAt time.Time
+}
+
+// MemoryStore keeps keys in process memory. Retries are only recognized by
+// the replica that served the first request, so it suits a single replica
+// and tests.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+
+	// Now returns the current time; tests replace it.
+	Now func() time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*entry), Now: time.Now}
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, key, fingerprint string, policy Policy) (Lock, *Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Now()
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		if e.record.Response != nil || now.Before(e.lockedAt.Add(policy.LockTimeout)) {
+			record := e.record
+			return Lock{}, &record, nil
+		}
+	}
+
+	s.entries[key] = &entry{
+		record:    Record{Fingerprint: fingerprint},
+		lockedAt:  now,
+		expiresAt: now.Add(policy.TTL),
+	}
+	return Lock{Key: key, LockedAt: now}, nil, nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, lock Lock, resp Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[lock.Key]
+	if !ok || !e.lockedAt.Equal(lock.LockedAt) {
+		return ErrLockLost
+	}
+	resp.Body = append([]byte(nil), resp.Body...)
+	e.record.Response = &resp
+	return nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, lock Lock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[lock.Key]
+	if !ok || !e.lockedAt.Equal(lock.LockedAt) {
+		return ErrLockLost
+	}
+	if e.record.Response == nil {
+		delete(s.entries, lock.Key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Prune(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Now()
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
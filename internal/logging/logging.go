@@ -0,0 +1,107 @@
+// Package logging builds the structured logger of the application. Records
+// are written through a handler that tags them with the ID of the request
+// being served and redacts secrets and personal data, so call sites can log
+// freely without thinking about either.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/requestid"
+)
+
+// Options configures New.
+type Options struct {
+	// Format is "json" or "text".
+	Format string
+	// Level is "debug", "info", "warn" or "error"; info by default.
+	Level string
+}
+
+// New creates a logger writing to w.
+func New(w io.Writer, opts Options) *slog.Logger {
+	handlerOpts := &slog.HandlerOptions{Level: parseLevel(opts.Level)}
+
+	var h slog.Handler
+	if strings.EqualFold(opts.Format, "json") {
+		h = slog.NewJSONHandler(w, handlerOpts)
+	} else {
+		h = slog.NewTextHandler(w, handlerOpts)
+	}
+	return slog.New(NewHandler(h))
+}
+
+// Discard returns a logger that drops every record, for tests.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func parseLevel(level string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// handler adds the request and trace IDs and redacts every record before passing it on.
+type handler struct {
+	next slog.Handler
+}
+
+// NewHandler wraps next so records carry the request and trace IDs of their
+// context and have secrets and email addresses redacted.
+func NewHandler(next slog.Handler) slog.Handler {
+	return &handler{next: next}
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, Redact(r.Message), r.PC)
+	if id := requestid.FromContext(ctx); id != "" {
+		out.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		out.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(a)
+	}
+	return &handler{next: h.next.WithAttrs(redacted)}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces secret values.
+const Redacted = "[REDACTED]"
+
+// secretKeys are substrings of attribute keys whose values are never logged.
+var secretKeys = []string{"password", "token", "secret", "otp", "authorization", "cookie", "api_key"}
+
+var (
+	emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+	// jwtPattern matches JSON Web Tokens, which always start with an
+	// encoded '{"' header.
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)\S+`)
+)
+
+// Redact masks email addresses and tokens embedded in free text, such as a
+// message or the text of an error: "alice@example.com" becomes
+// "a***@example.com".
+func Redact(s string) string {
+	s = jwtPattern.ReplaceAllString(s, Redacted)
+	s = bearerPattern.ReplaceAllString(s, "${1}"+Redacted)
+	return emailPattern.ReplaceAllString(s, "${1}***@${2}")
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		redacted := make([]any, len(attrs))
+		for i, attr := range attrs {
+			redacted[i] = redactAttr(attr)
+		}
+		return slog.Group(a.Key, redacted...)
+	}
+
+	key := strings.ToLower(a.Key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+
+	switch v := a.Value.Resolve(); v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(v.String()))
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+		return a
+	default:
+		return a
+	}
+}
@@ -0,0 +1,215 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"mime/multipart"
+	"strconv"
+	"time"
+)
+
+var (
+	// LatencyBuckets suit request and query latencies, in seconds.
+	LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	// SizeBuckets suit uploaded file sizes, in bytes, from 1 KiB to 1 GiB.
+	SizeBuckets = []float64{1 << 10, 16 << 10, 128 << 10, 1 << 20, 4 << 20, 16 << 20, 64 << 20, 256 << 20, 1 << 30}
+)
+
+// Metrics are the metrics of the application. Its methods do nothing on a
+// nil *Metrics, so components work without metrics in tests.
+type Metrics struct {
+	Registry *Registry
+
+	httpDuration   *HistogramVec
+	uploadSize     *HistogramVec
+	uploadDuration *HistogramVec
+	background     *HistogramVec
+
+	registrations *CounterVec
+	logins        *CounterVec
+	posts         *CounterVec
+	jobs          *CounterVec
+	likes         *CounterVec
+	follows       *CounterVec
+	emails        *CounterVec
+}
+
+// New registers the metrics of the application in a fresh registry.
+func New() *Metrics {
+	r := NewRegistry()
+	return &Metrics{
+		Registry: r,
+
+		httpDuration: r.Histogram("http_request_duration_seconds",
+			"Latency of HTTP requests by route template and status.", LatencyBuckets, "method", "route", "status"),
+		uploadSize: r.Histogram("upload_size_bytes",
+			"Size of files uploaded to object storage.", SizeBuckets, "outcome"),
+		uploadDuration: r.Histogram("upload_duration_seconds",
+			"Time spent uploading files to object storage.", LatencyBuckets, "outcome"),
+		background: r.Histogram("background_job_duration_seconds",
+			"Duration of background job runs.", LatencyBuckets, "job", "outcome"),
+
+		registrations: r.Counter("users_registered_total", "Users who signed up."),
+		logins:        r.Counter("logins_total", "Login attempts by outcome.", "outcome"),
+		posts:         r.Counter("posts_created_total", "Posts created."),
+		jobs:          r.Counter("jobs_posted_total", "Job posts created."),
+		likes:         r.Counter("post_likes_total", "Posts liked."),
+		follows:       r.Counter("follows_total", "Users followed."),
+		emails:        r.Counter("emails_total", "Emails sent by outcome.", "outcome"),
+	}
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// ObserveHTTP records a served request. route is the route template, so
+// paths with IDs do not create a series each.
+func (m *Metrics) ObserveHTTP(method, route string, status int, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpDuration.Observe(d.Seconds(), method, route, strconv.Itoa(status))
+}
+
+// ObserveBackground records one run of a background job.
+func (m *Metrics) ObserveBackground(job string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.background.Observe(d.Seconds(), job, outcome(err))
+}
+
+// Registered counts a sign-up.
+func (m *Metrics) Registered() {
+	if m == nil {
+		return
+	}
+	m.registrations.Inc()
+}
+
+// LoginAttempted counts a login attempt, e.g. with outcome "success" or
+// "invalid_credentials".
+func (m *Metrics) LoginAttempted(outcome string) {
+	if m == nil {
+		return
+	}
+	m.logins.Inc(outcome)
+}
+
+// PostCreated counts a new post.
+func (m *Metrics) PostCreated() {
+	if m == nil {
+		return
+	}
+	m.posts.Inc()
+}
+
+// JobPosted counts a new job post.
+func (m *Metrics) JobPosted() {
+	if m == nil {
+		return
+	}
+	m.jobs.Inc()
+}
+
+// PostLiked counts a like.
+func (m *Metrics) PostLiked() {
+	if m == nil {
+		return
+	}
+	m.likes.Inc()
+}
+
+// Followed counts a follow.
+func (m *Metrics) Followed() {
+	if m == nil {
+		return
+	}
+	m.follows.Inc()
+}
+
+// RegisterDBStats exposes the connection pool statistics of db.
+func (m *Metrics) RegisterDBStats(db *sql.DB) {
+	if m == nil {
+		return
+	}
+	stat := func(fn func(sql.DBStats) float64) func() float64 {
+		return func() float64 { return fn(db.Stats()) }
+	}
+	r := m.Registry
+	r.GaugeFunc("db_max_open_connections", "Maximum number of open connections to the database.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	r.GaugeFunc("db_open_connections", "Established connections, in use or idle.",
+		stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	r.GaugeFunc("db_in_use_connections", "Connections currently in use.",
+		stat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	r.GaugeFunc("db_idle_connections", "Idle connections.",
+		stat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	r.CounterFunc("db_wait_count_total", "Connections waited for.",
+		stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	r.CounterFunc("db_wait_duration_seconds_total", "Time blocked waiting for a connection.",
+		stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	r.CounterFunc("db_max_idle_closed_total", "Connections closed due to the idle limit.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }))
+	r.CounterFunc("db_max_lifetime_closed_total", "Connections closed due to their maximum lifetime.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }))
+}
+
+// Mailer matches services.Mailer.
+type Mailer interface {
+	SendEmail(to, subject, body string) error
+}
+
+// InstrumentMailer counts the emails next sends and fails to send.
+func (m *Metrics) InstrumentMailer(next Mailer) Mailer {
+	if m == nil {
+		return next
+	}
+	return &mailer{next: next, m: m}
+}
+
+type mailer struct {
+	next Mailer
+	m    *Metrics
+}
+
+func (ml *mailer) SendEmail(to, subject, body string) error {
+	err := ml.next.SendEmail(to, subject, body)
+	ml.m.emails.Inc(outcome(err))
+	return err
+}
+
+// Uploader matches controllers.Uploader.
+type Uploader interface {
+	UploadFile(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, key string) (string, error)
+	DeleteFile(ctx context.Context, url string) error
+}
+
+// InstrumentUploader records the size and latency of the uploads of next.
+func (m *Metrics) InstrumentUploader(next Uploader) Uploader {
+	if m == nil {
+		return next
+	}
+	return &uploader{next: next, m: m}
+}
+
+type uploader struct {
+	next Uploader
+	m    *Metrics
+}
+
+func (u *uploader) UploadFile(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, key string) (string, error) {
+	start := time.Now()
+	url, err := u.next.UploadFile(ctx, file, fileHeader, key)
+	u.m.uploadDuration.Observe(time.Since(start).Seconds(), outcome(err))
+	u.m.uploadSize.Observe(float64(fileHeader.Size), outcome(err))
+	return url, err
+}
+
+func (u *uploader) DeleteFile(ctx context.Context, url string) error {
+	return u.next.DeleteFile(ctx, url)
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextFormat(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("events_total", "Events.", "kind")
+	h := r.Histogram("latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+	r.GaugeFunc("answer", "The answer.", func() float64 { return 42 })
+
+	c.Inc(`say "hi"`)
+	c.Add(2, "other")
+	h.Observe(0.05, "/posts/:post_id")
+	h.Observe(0.5, "/posts/:post_id")
+
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP answer The answer.
+# TYPE answer gauge
+answer 42
+# HELP events_total Events.
+# TYPE events_total counter
+events_total{kind="other"} 2
+events_total{kind="say \"hi\""} 1
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/posts/:post_id",le="0.1"} 1
+latency_seconds_bucket{route="/posts/:post_id",le="1"} 2
+latency_seconds_bucket{route="/posts/:post_id",le="+Inf"} 2
+latency_seconds_sum{route="/posts/:post_id"} 0.55
+latency_seconds_count{route="/posts/:post_id"} 2
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+type failingMailer struct{ err error }
+
+func (f failingMailer) SendEmail(to, subject, body string) error { return f.err }
+
+func TestInstrumentMailer(t *testing.T) {
+	m := New()
+	m.InstrumentMailer(failingMailer{}).SendEmail("a@example.com", "s", "b")
+	m.InstrumentMailer(failingMailer{errors.New("smtp down")}).SendEmail("a@example.com", "s", "b")
+
+	var buf bytes.Buffer
+	m.Registry.WriteTo(&buf)
+	for _, line := range []string{`emails_total{outcome="error"} 1`, `emails_total{outcome="success"} 1`} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("missing %q", line)
+		}
+	}
+}
+
+func TestNilMetricsAreNoOps(t *testing.T) {
+	var m *Metrics
+	m.Registered()
+	m.ObserveHTTP("GET", "/", 200, time.Second)
+	if m.InstrumentMailer(failingMailer{}) == nil {
+		t.Error("nil metrics dropped the mailer")
+	}
+}
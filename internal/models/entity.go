@@ -0,0 +1,28 @@
+package models
+
+import "github.com/google/uuid"
+
+// Kinds of text entities.
+const (
+	EntityHashtag = "hashtag"
+	EntityMention = "mention"
+)
+
+// Entity is a hashtag or @mention found in the text of a post or comment.
+// Start and End are offsets in Unicode code points, End exclusive, and
+// cover the leading # or @. Text is the tag, lower-cased, or the username
+// without the prefix; UserID is the mentioned user, nil when no user has
+// that username.
+type Entity struct {
+	Type   string     `json:"type"`
+	Start  int        `json:"start"`
+	End    int        `json:"end"`
+	Text   string     `json:"text"`
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+}
+
+// HashtagCount is the number of posts and comments that used a hashtag.
+type HashtagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
@@ -6,13 +6,108 @@ import (
 	"github.com/google/uuid"
 )
 
+// Kinds of shared posts: a repost shares a post as is, a quote adds the
+// sharer's text.
+const (
+	ShareRepost = "repost"
+	ShareQuote  = "quote"
+)
+
+// Visibilities of a post: everyone, the author's followers or the author
+// only.
+const (
+	VisibilityPublic    = "public"
+	VisibilityFollowers = "followers"
+	VisibilityOnlyMe    = "only_me"
+)
+
+// Types of posts: text, possibly with attachments, or a poll asking the
+// question in its text.
+const (
+	PostTypeText = "text"
+	PostTypePoll = "poll"
+)
+
 type ContentPost struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	PostContent string     `json:"post_content"`
+	MediaURL    string     `json:"media_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	EditedAt    *time.Time `json:"edited_at,omitempty"` // nil until the post is edited
+	Visibility  string     `json:"visibility"`
+	PostType    string     `json:"post_type"`
+	User        *User      `json:"user,omitempty"`
+
+	// ShareType is ShareRepost or ShareQuote when the post shares the post
+	// SharedPostID, which is nil once a quoted post is deleted.
+	SharedPostID *uuid.UUID `json:"shared_post_id,omitempty"`
+	ShareType    string     `json:"share_type,omitempty"`
+
+	Attachments []PostAttachment `json:"attachments"`
+	Entities    []Entity         `json:"entities"`
+	Poll        *Poll            `json:"poll,omitempty"`
+}
+
+// PostAttachment is a media file attached to a post, in Position order.
+// Width and Height are known for images and MP4 or QuickTime videos,
+// DurationSeconds for those videos.
+type PostAttachment struct {
+	ID              uuid.UUID `json:"id"`
+	PostID          uuid.UUID `json:"post_id"`
+	Position        int       `json:"position"`
+	URL             string    `json:"url"`
+	MimeType        string    `json:"mime_type"`
+	SizeBytes       int64     `json:"size_bytes"`
+	Width           *int      `json:"width"`
+	Height          *int      `json:"height"`
+	DurationSeconds *float64  `json:"duration_seconds"`
+	AltText         string    `json:"alt_text"`
+}
+
+// Poll is the poll of a poll post. It takes votes until EndsAt. TotalVoters counts the users who voted;
+// Choices are the options the user the poll is read for voted for, empty
+// until they vote.
+type Poll struct {
+	PostID         uuid.UUID    `json:"post_id"`
+	MultipleChoice bool         `json:"multiple_choice"`
+	EndsAt         time.Time    `json:"ends_at"`
+	Options        []PollOption `json:"options"`
+	TotalVoters    int          `json:"total_voters"`
+	Choices        []uuid.UUID  `json:"choices"`
+}
+
+// PollOption is an answer of a poll, in Position order. Votes counts the
+// users who chose it.
+type PollOption struct {
+	ID       uuid.UUID `json:"id"`
+	PostID   uuid.UUID `json:"post_id"`
+	Position int       `json:"position"`
+	Label    string    `json:"label"`
+	Votes    int       `json:"votes"`
+}
+
+// PostDraft is a post written ahead of time. It is a draft while PublishAt
+// is nil and a scheduled post otherwise, published at that time. An empty
+// Visibility publishes it with the author's default visibility.
+type PostDraft struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	PostContent string     `json:"post_content"`
+	Visibility  string     `json:"visibility,omitempty"`
+	PublishAt   *time.Time `json:"publish_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// PostRevision is a version of an edited post as it was before an edit.
+// CreatedAt is when the version was written, ReplacedAt when it was edited.
+type PostRevision struct {
 	ID          uuid.UUID `json:"id"`
-	UserID      uuid.UUID `json:"user_id"`
+	PostID      uuid.UUID `json:"post_id"`
 	PostContent string    `json:"post_content"`
-	MediaURL    string    `json:"media_url"`
 	CreatedAt   time.Time `json:"created_at"`
-	User        *User     `json:"user,omitempty"`
+	ReplacedAt  time.Time `json:"replaced_at"`
 }
 
 type JobPost struct {
@@ -29,13 +124,38 @@ type JobPost struct {
 }
 
 type PostWithDetails struct {
-	PostID        uuid.UUID `json:"post_id"`
-	UserID        uuid.UUID `json:"user_id"`
-	ProfileImage  string    `json:"profile_image"`
-	FullName      string    `json:"full_name"`
-	Designation   string    `json:"designation"`
-	PostContent   string    `json:"post_content"`
-	MediaURL      *string   `json:"media_url"`
-	TotalLikes    int       `json:"total_likes"`
-	TotalComments int       `json:"total_comments"`
+	PostID        uuid.UUID  `json:"post_id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	ProfileImage  string     `json:"profile_image"`
+	FullName      string     `json:"full_name"`
+	Designation   string     `json:"designation"`
+	PostContent   string     `json:"post_content"`
+	MediaURL      *string    `json:"media_url"`
+	TotalLikes    int        `json:"total_likes"`
+	TotalComments int        `json:"total_comments"`
+	CreatedAt     time.Time  `json:"created_at"`
+	EditedAt      *time.Time `json:"edited_at"`
+	Visibility    string     `json:"visibility"`
+	PostType      string     `json:"post_type"`
+
+	// TotalShares counts the reposts and quotes of the post. SharedPost is
+	// the post a repost or quote shares, without its own shared post.
+	TotalShares  int              `json:"total_shares"`
+	ShareType    string           `json:"share_type,omitempty"`
+	SharedPostID *uuid.UUID       `json:"shared_post_id,omitempty"`
+	SharedPost   *PostWithDetails `json:"shared_post,omitempty"`
+
+	Attachments []PostAttachment `json:"attachments"`
+	Entities    []Entity         `json:"entities"`
+	Poll        *Poll            `json:"poll,omitempty"`
+}
+
+// PostSignals are the engagement counts of a post read by the feed ranker.
+// RecentLikes and RecentComments are counted since a cut-off; Affinity is
+// the number of likes and comments the viewer gave to the author's posts.
+type PostSignals struct {
+	PostID         uuid.UUID
+	RecentLikes    int
+	RecentComments int
+	Affinity       int
 }
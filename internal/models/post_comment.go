@@ -12,4 +12,5 @@ type PostComment struct {
 	PostID    uuid.UUID `json:"post_id"`
 	Comment   string    `json:"comment"`
 	CreatedAt time.Time `json:"created_at"`
+	Entities  []Entity  `json:"entities"`
 }
@@ -7,16 +7,17 @@ import (
 )
 
 type UserProfile struct {
-	ID                  uuid.UUID
-	UserID              uuid.UUID
-	ProfileImage        *string // nullable
-	FullName            string  // required
-	Designation         *string // nullable
-	Organization        *string // nullable
-	ProfessionalSummary *string // nullable
-	Location            *string // nullable
-	Email               string  // required
-	ContactNumber       *string // nullable
-	CreatedAt           time.Time
-	UpdatedAt           time.Time
+	ID                  uuid.UUID `json:"id"`
+	UserID              uuid.UUID `json:"user_id"`
+	ProfileImage        *string   `json:"profile_image"`        // nullable
+	FullName            string    `json:"full_name"`            // required
+	Designation         *string   `json:"designation"`          // nullable
+	Organization        *string   `json:"organization"`         // nullable
+	ProfessionalSummary *string   `json:"professional_summary"` // nullable
+	Location            *string   `json:"location"`             // nullable
+	Email               string    `json:"email"`                // required
+	ContactNumber       *string   `json:"contact_number"`       // nullable
+	DefaultVisibility   string    `json:"default_visibility"`   // of new posts
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
 }
@@ -0,0 +1,28 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed docs.html
+var docsPage string
+
+// SpecHandler serves doc as JSON.
+func SpecHandler(doc *Document) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, doc)
+	}
+}
+
+// DocsHandler serves the bundled docs UI, which renders the document found
+// at specURL. It needs no assets from outside the binary.
+func DocsHandler(specURL string) gin.HandlerFunc {
+	page := strings.ReplaceAll(docsPage, "{{SPEC_URL}}", specURL)
+	return func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	}
+}
@@ -0,0 +1,349 @@
+// Package openapi builds the OpenAPI 3 description of the HTTP API from
+// operations declared next to the handlers that serve them. Request and
+// response schemas are derived from the DTO types by reflection, so the
+// document follows the code instead of being maintained by hand.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Version is the OpenAPI version the document conforms to.
+const Version = "3.0.3"
+
+// Operation documents one route. Path uses Gin syntax (/posts/:post_id);
+// path parameters are derived from it.
+type Operation struct {
+	Method  string
+	Path    string
+	Tag     string
+	Summary string
+
+	// Public operations do not require authentication.
+	Public bool
+
+	// Body is a JSON request body value, Form a urlencoded or multipart form
+	// value. Files lists the multipart file fields read besides Form.
+	Body  any
+	Form  any
+	Files []string
+
+	Query []Param
+
+	// Responses maps success status codes to an example value of the body,
+	// or nil for an empty body. Errors lists the error statuses the handler
+	// returns besides those every operation can produce (400 with input,
+	// 401 when protected, 429 and 500).
+	Responses map[int]any
+	Errors    []int
+
+	// Produces overrides the response media type, e.g. for streamed files.
+	Produces string
+
+	Deprecated bool
+}
+
+// Param documents a query parameter.
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+	Format      string
+
+	// Type is the JSON schema type of the value; it defaults to "string".
+	Type string
+}
+
+// Documenter is implemented by controllers that describe their routes.
+type Documenter interface {
+	OpenAPI() []Operation
+}
+
+// Document is the root of an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations in the docs UI.
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds the operations of one path keyed by lower-case method.
+type PathItem map[string]*OperationObject
+
+// OperationObject is the serialized form of an Operation.
+type OperationObject struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []ParameterObject     `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// ParameterObject describes a path or query parameter.
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the accepted request bodies.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one response status.
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header describes a response header.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType holds the schema of a body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the shared schemas and security schemes.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how clients authenticate.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Builder collects operations into a Document.
+type Builder struct {
+	doc     *Document
+	schemas *schemaRegistry
+	errType any
+}
+
+// NewBuilder starts a document. errorBody is an example of the body every
+// error response carries.
+func NewBuilder(info Info, errorBody any) *Builder {
+	b := &Builder{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   make(map[string]*PathItem),
+			Components: Components{
+				Schemas: make(map[string]*Schema),
+				SecuritySchemes: map[string]SecurityScheme{
+					"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+					"cookieAuth": {
+						Type: "apiKey", In: "cookie", Name: "token",
+						Description: "Unsafe requests must repeat the csrf_token cookie in the X-CSRF-Token header.",
+					},
+				},
+			},
+		},
+		errType: errorBody,
+	}
+	b.schemas = newSchemaRegistry(b.doc.Components.Schemas)
+	return b
+}
+
+// Add documents ops.
+func (b *Builder) Add(ops ...Operation) {
+	for _, op := range ops {
+		path, params := convertPath(op.Path)
+		item, ok := b.doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			b.doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(op.Method)] = b.operation(op, params)
+		b.addTag(op.Tag)
+	}
+}
+
+// Document returns the assembled document.
+func (b *Builder) Document() *Document {
+	sort.Slice(b.doc.Tags, func(i, j int) bool { return b.doc.Tags[i].Name < b.doc.Tags[j].Name })
+	return b.doc
+}
+
+func (b *Builder) addTag(name string) {
+	if name == "" {
+		return
+	}
+	for _, t := range b.doc.Tags {
+		if t.Name == name {
+			return
+		}
+	}
+	b.doc.Tags = append(b.doc.Tags, Tag{Name: name})
+}
+
+func (b *Builder) operation(op Operation, pathParams []string) *OperationObject {
+	obj := &OperationObject{
+		Summary:     op.Summary,
+		OperationID: operationID(op.Method, op.Path),
+		Responses:   make(map[string]Response),
+		Deprecated:  op.Deprecated,
+	}
+	if op.Tag != "" {
+		obj.Tags = []string{op.Tag}
+	}
+
+	for _, name := range pathParams {
+		schema := &Schema{Type: "string"}
+		if name == "id" || strings.HasSuffix(name, "_id") {
+			schema.Format = "uuid"
+		}
+		obj.Parameters = append(obj.Parameters, ParameterObject{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	for _, q := range op.Query {
+		typ := q.Type
+		if typ == "" {
+			typ = "string"
+		}
+		obj.Parameters = append(obj.Parameters, ParameterObject{
+			Name:        q.Name,
+			In:          "query",
+			Description: q.Description,
+			Required:    q.Required,
+			Schema:      &Schema{Type: typ, Format: q.Format},
+		})
+	}
+
+	switch {
+	case op.Body != nil:
+		obj.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"application/json": {Schema: b.schemas.ref(op.Body)},
+		}}
+	case op.Form != nil || len(op.Files) > 0:
+		obj.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"multipart/form-data": {Schema: b.formSchema(op)},
+		}}
+	}
+
+	for status, body := range op.Responses {
+		resp := Response{Description: http.StatusText(status)}
+		if body != nil {
+			mediaType := op.Produces
+			if mediaType == "" {
+				mediaType = "application/json"
+			}
+			resp.Content = map[string]MediaType{mediaType: {Schema: b.schemas.ref(body)}}
+		}
+		obj.Responses[fmt.Sprint(status)] = resp
+	}
+
+	errors := append([]int(nil), op.Errors...)
+	if obj.RequestBody != nil || len(obj.Parameters) > 0 {
+		errors = append(errors, http.StatusBadRequest)
+	}
+	if !op.Public {
+		errors = append(errors, http.StatusUnauthorized)
+		obj.Security = []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}}
+		if op.Method != http.MethodGet {
+			obj.Parameters = append(obj.Parameters, idempotencyKeyParam)
+			errors = append(errors, http.StatusConflict, http.StatusUnprocessableEntity)
+		}
+	}
+	errors = append(errors, http.StatusTooManyRequests, http.StatusInternalServerError)
+	for _, status := range errors {
+		obj.Responses[fmt.Sprint(status)] = Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{"application/json": {Schema: b.schemas.ref(b.errType)}},
+		}
+	}
+
+	return obj
+}
+
+// idempotencyKeyParam documents the Idempotency-Key header accepted by every
+// protected unsafe operation.
+var idempotencyKeyParam = func() ParameterObject {
+	maxLength := 255
+	return ParameterObject{
+		Name:        "Idempotency-Key",
+		In:          "header",
+		Description: "Makes the request safe to retry for 24 hours: retries with the same body get the first response again, with Idempotent-Replayed: true.",
+		Schema:      &Schema{Type: "string", MaxLength: &maxLength},
+	}
+}()
+
+// formSchema merges the form value's fields and the file fields into one
+// multipart schema.
+func (b *Builder) formSchema(op Operation) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	if op.Form != nil {
+		inline := b.schemas.inline(op.Form)
+		for name, prop := range inline.Properties {
+			schema.Properties[name] = prop
+		}
+		schema.Required = append(schema.Required, inline.Required...)
+	}
+	for _, name := range op.Files {
+		schema.Properties[name] = &Schema{Type: "string", Format: "binary"}
+	}
+	return schema
+}
+
+// convertPath turns /posts/:post_id into /posts/{post_id} and returns the
+// parameter names in order.
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			name := seg[1:]
+			params = append(params, name)
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// PathFor converts a Gin route path to its OpenAPI form.
+func PathFor(ginPath string) string {
+	path, _ := convertPath(ginPath)
+	return path
+}
+
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, seg := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == '_' || r == ':' }) {
+		b.WriteString(strings.ToUpper(seg[:1]) + seg[1:])
+	}
+	return b.String()
+}
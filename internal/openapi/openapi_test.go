@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+type embedded struct {
+	Name string `json:"name" binding:"required,max=20"`
+}
+
+type request struct {
+	embedded
+	Kind   string  `json:"kind" binding:"omitempty,oneof=a b"`
+	Email  string  `json:"email" binding:"required,email"`
+	Secret string  `json:"-"`
+	Note   *string `json:"note"`
+}
+
+type errorBody struct {
+	Code string `json:"code"`
+}
+
+func TestStructSchemaFollowsTags(t *testing.T) {
+	b := NewBuilder(Info{Title: "test", Version: "1"}, errorBody{})
+	b.Add(Operation{Method: http.MethodPost, Path: "/things/:thing_id", Body: request{}, Responses: map[int]any{http.StatusCreated: nil}})
+	doc := b.Document()
+
+	s := doc.Components.Schemas["request"]
+	if s == nil {
+		t.Fatal("request schema was not registered")
+	}
+	if _, ok := s.Properties["-"]; ok || s.Properties["Secret"] != nil {
+		t.Error("fields tagged json:\"-\" must be omitted")
+	}
+	if name := s.Properties["name"]; name == nil || *name.MaxLength != 20 {
+		t.Errorf("embedded field name = %+v; want maxLength 20", name)
+	}
+	if !slices.Equal(s.Required, []string{"name", "email"}) {
+		t.Errorf("required = %v", s.Required)
+	}
+	if got := s.Properties["kind"].Enum; !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("kind enum = %v", got)
+	}
+	if s.Properties["email"].Format != "email" || !s.Properties["note"].Nullable {
+		t.Errorf("email/note = %+v / %+v", s.Properties["email"], s.Properties["note"])
+	}
+
+	op := (*doc.Paths["/things/{thing_id}"])["post"]
+	if op == nil {
+		t.Fatal("operation missing")
+	}
+	if len(op.Parameters) != 2 || op.Parameters[0].Schema.Format != "uuid" || op.Parameters[1].In != "header" {
+		t.Errorf("parameters = %+v; want thing_id as uuid and the Idempotency-Key header", op.Parameters)
+	}
+	for _, status := range []string{"201", "400", "401", "409", "422", "500"} {
+		if _, ok := op.Responses[status]; !ok {
+			t.Errorf("response %s missing", status)
+		}
+	}
+	if op.Security == nil {
+		t.Error("protected operation has no security requirement")
+	}
+}
@@ -0,0 +1,212 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is an OpenAPI schema object, restricted to what the DTOs use.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Description string             `json:"description,omitempty"`
+}
+
+// Binary documents a raw, non-JSON response body such as a streamed file.
+type Binary []byte
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	uuidType   = reflect.TypeOf(uuid.UUID{})
+	binaryType = reflect.TypeOf(Binary{})
+)
+
+// schemaRegistry turns Go types into schemas, registering named structs as
+// reusable components.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry(schemas map[string]*Schema) *schemaRegistry {
+	return &schemaRegistry{schemas: schemas, names: make(map[reflect.Type]string)}
+}
+
+// ref returns a schema for v, referencing a component for named structs.
+func (r *schemaRegistry) ref(v any) *Schema {
+	return r.schemaFor(reflect.TypeOf(v))
+}
+
+// inline returns the object schema of a struct value without registering it.
+func (r *schemaRegistry) inline(v any) *Schema {
+	return r.structSchema(deref(reflect.TypeOf(v)))
+}
+
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case binaryType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := r.schemaFor(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + r.register(t)}
+	default:
+		return &Schema{}
+	}
+}
+
+// register adds the component for a named struct and returns its name.
+func (r *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := r.schemas[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	r.names[t] = name
+	r.schemas[name] = &Schema{} // placeholder for recursive types
+	*r.schemas[name] = *r.structSchema(t)
+	return name
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(s, t)
+	return s
+}
+
+// addFields adds the fields of t to s following encoding/json rules:
+// embedded structs are flattened and "-" fields are skipped.
+func (r *schemaRegistry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		if f.Anonymous && name == "" && deref(f.Type).Kind() == reflect.Struct {
+			r.addFields(s, deref(f.Type))
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := r.schemaFor(f.Type)
+		if applyRules(prop, f.Tag.Get("binding")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// fieldName returns the wire name of f from its json or form tag. ok is
+// false for fields excluded from the wire format.
+func fieldName(f reflect.StructField) (name string, ok bool) {
+	for _, key := range []string{"json", "form"} {
+		tag, found := f.Tag.Lookup(key)
+		if !found {
+			continue
+		}
+		name, _, _ = strings.Cut(tag, ",")
+		if name == "-" {
+			return "", false
+		}
+		return name, true
+	}
+	return "", true
+}
+
+// applyRules copies the validation rules of a binding tag onto s and reports
+// whether the field is required.
+func applyRules(s *Schema, tag string) (required bool) {
+	if tag == "" || s.Ref != "" {
+		return false
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			s.Format = "email"
+		case "uuid":
+			s.Format = "uuid"
+		case "url", "http_url":
+			s.Format = "uri"
+		case "date":
+			s.Format = "date"
+		case "numeric":
+			s.Pattern = "^[0-9]+$"
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case "min", "max", "len":
+			n, err := strconv.Atoi(param)
+			if err != nil || s.Type != "string" {
+				continue
+			}
+			if name != "max" {
+				s.MinLength = &n
+			}
+			if name != "min" {
+				s.MaxLength = &n
+			}
+		}
+	}
+	return required
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
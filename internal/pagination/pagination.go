@@ -0,0 +1,177 @@
+// Package pagination pages lists with opaque keyset cursors. Lists are
+// ordered newest first by (created_at, id); a cursor holds the key of the
+// last item a client has seen, and the next page starts right after it.
+// Unlike offsets, cursors stay correct while rows are inserted and cost the
+// same however deep a client pages.
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+)
+
+// DefaultLimit is the page size when a client asks for none, MaxLimit the
+// largest page a client may ask for. The server may change both at start-up.
+var (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Cursor is the key of an item: its creation time and ID.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Encode returns the opaque form of c handed to clients.
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Decode parses a cursor produced by Encode.
+func Decode(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, errInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return Cursor{}, errInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return Cursor{}, errInvalidCursor
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return Cursor{}, errInvalidCursor
+	}
+	return Cursor{CreatedAt: time.Unix(0, n).UTC(), ID: parsed}, nil
+}
+
+var errInvalidCursor = apperrors.InvalidField("cursor", "is not a cursor returned by this API")
+
+// Compare orders keys newest first: it is negative when a comes before b.
+func Compare(a, b Cursor) int {
+	if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+		return c
+	}
+	return bytes.Compare(b.ID[:], a.ID[:])
+}
+
+// Params selects a page: at most Limit items following After, or the first
+// page when After is nil.
+type Params struct {
+	Limit int
+	After *Cursor
+}
+
+// First returns the parameters of the first page of the default size.
+func First() Params {
+	return Params{Limit: DefaultLimit}
+}
+
+// Parse reads the page parameters of a request from the raw values of its
+// limit and cursor query parameters, either of which may be empty.
+func Parse(limit, cursor string) (Params, error) {
+	p := First()
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxLimit {
+			return Params{}, apperrors.InvalidField("limit", fmt.Sprintf("must be between 1 and %d", MaxLimit))
+		}
+		p.Limit = n
+	}
+	if cursor != "" {
+		c, err := Decode(cursor)
+		if err != nil {
+			return Params{}, err
+		}
+		p.After = &c
+	}
+	return p, nil
+}
+
+// Page is one page of a list. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// Where returns the SQL condition selecting the rows after the cursor, with
+// createdAt and id the key columns and n the number of its first
+// placeholder, together with the arguments of its placeholders.
+func (p Params) Where(createdAt, id string, n int) (string, []any) {
+	if p.After == nil {
+		return "TRUE", nil
+	}
+	return fmt.Sprintf("(%s, %s) < ($%d, $%d)", createdAt, id, n, n+1), []any{p.After.CreatedAt, p.After.ID}
+}
+
+// OrderBy returns the ORDER BY and LIMIT clauses of a page query. One row
+// more than the page holds is fetched to learn whether another page follows.
+func (p Params) OrderBy(createdAt, id string) string {
+	return fmt.Sprintf("ORDER BY %s DESC, %s DESC LIMIT %d", createdAt, id, p.Limit+1)
+}
+
+// NewPage builds the page from the rows of a query built with Where and
+// OrderBy.
+func NewPage[T any](rows []T, p Params, key func(T) Cursor) Page[T] {
+	if len(rows) <= p.Limit {
+		return Page[T]{Items: rows}
+	}
+	rows = rows[:p.Limit]
+	return Page[T]{Items: rows, NextCursor: key(rows[len(rows)-1]).Encode()}
+}
+
+// Apply pages a whole list held in memory, as the database would.
+func Apply[T any](items []T, p Params, key func(T) Cursor) Page[T] {
+	items = slices.Clone(items)
+	slices.SortStableFunc(items, func(a, b T) int { return Compare(key(a), key(b)) })
+	if p.After != nil {
+		start, _ := slices.BinarySearchFunc(items, *p.After, func(item T, after Cursor) int {
+			if Compare(key(item), after) <= 0 {
+				return -1
+			}
+			return 1
+		})
+		items = items[start:]
+	}
+	if len(items) > p.Limit+1 {
+		items = items[:p.Limit+1]
+	}
+	return NewPage(items, p, key)
+}
+
+// NewIDPage builds a page of IDs, such as the followers of a user, from the
+// keys of the rows of a query built with Where and OrderBy.
+func NewIDPage(keys []Cursor, p Params) Page[uuid.UUID] {
+	return ids(NewPage(keys, p, self))
+}
+
+// ApplyIDs pages the IDs of a whole list of keys held in memory, as the
+// database would.
+func ApplyIDs(keys []Cursor, p Params) Page[uuid.UUID] {
+	return ids(Apply(keys, p, self))
+}
+
+func self(c Cursor) Cursor {
+	return c
+}
+
+func ids(p Page[Cursor]) Page[uuid.UUID] {
+	items := make([]uuid.UUID, len(p.Items))
+	for i, c := range p.Items {
+		items[i] = c.ID
+	}
+	return Page[uuid.UUID]{Items: items, NextCursor: p.NextCursor}
+}
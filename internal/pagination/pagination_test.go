@@ -0,0 +1,86 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{CreatedAt: time.Date(2026, time.October, 19, 12, 0, 0, 123456000, time.UTC), ID: uuid.New()}
+	got, err := Decode(c.Encode())
+	if err != nil || !got.CreatedAt.Equal(c.CreatedAt) || got.ID != c.ID {
+		t.Fatalf("Decode(Encode(%v)) = %v, %v", c, got, err)
+	}
+	for _, bad := range []string{"!!", "bm90LWEtY3Vyc29y", c.Encode()[:10]} {
+		if _, err := Decode(bad); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Decode(%q) err = %v; want a validation error", bad, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	if p, err := Parse("", ""); err != nil || p.Limit != DefaultLimit || p.After != nil {
+		t.Errorf("Parse defaults = %+v, %v", p, err)
+	}
+	if p, err := Parse("5", ""); err != nil || p.Limit != 5 {
+		t.Errorf("Parse limit = %+v, %v", p, err)
+	}
+	for _, limit := range []string{"0", "-1", "abc", "101"} {
+		if _, err := Parse(limit, ""); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Parse(%q) err = %v; want a validation error", limit, err)
+		}
+	}
+}
+
+func TestApplyWalksEveryItemOnce(t *testing.T) {
+	base := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	var items []Cursor
+	for i := 0; i < 7; i++ {
+		// Pairs of items share a timestamp, so the ID breaks ties.
+		items = append(items, Cursor{CreatedAt: base.Add(time.Duration(i/2) * time.Minute), ID: uuid.New()})
+	}
+	key := func(c Cursor) Cursor { return c }
+
+	seen := make(map[uuid.UUID]bool)
+	var last *Cursor
+	p := Params{Limit: 3}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("paging does not end")
+		}
+		page := Apply(items, p, key)
+		for _, item := range page.Items {
+			if seen[item.ID] {
+				t.Fatalf("item %v returned twice", item)
+			}
+			if last != nil && Compare(*last, item) >= 0 {
+				t.Fatalf("item %v out of order after %v", item, *last)
+			}
+			seen[item.ID] = true
+			item := item
+			last = &item
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		p, _ = Parse("3", page.NextCursor)
+	}
+	if len(seen) != len(items) {
+		t.Fatalf("saw %d items; want %d", len(seen), len(items))
+	}
+}
+
+func TestWhere(t *testing.T) {
+	if where, args := First().Where("created_at", "id", 2); where != "TRUE" || args != nil {
+		t.Errorf("first page: %q %v", where, args)
+	}
+	c := Cursor{CreatedAt: time.Now(), ID: uuid.New()}
+	where, args := Params{Limit: 1, After: &c}.Where("p.created_at", "p.id", 2)
+	if where != "(p.created_at, p.id) < ($2, $3)" || len(args) != 2 {
+		t.Errorf("after cursor: %q %v", where, args)
+	}
+}
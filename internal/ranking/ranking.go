@@ -0,0 +1,246 @@
+// Package ranking scores posts for the "top" mode of the home feed. A Ranker
+// turns the signals of a post into a score; Weighted rankers combine
+// independent scorers so variants can be compared in A/B experiments.
+package ranking
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+)
+
+// Candidate is a post considered for a ranked feed with the signals the
+// scorers read.
+type Candidate struct {
+	Post    models.PostWithDetails
+	Signals models.PostSignals
+}
+
+// Score is the score of a candidate. Components holds the weighted
+// contribution of every scorer by name; they add up to Total.
+type Score struct {
+	Total      float64
+	Components map[string]float64
+}
+
+// Ranker scores candidates for a viewer at a point in time.
+type Ranker interface {
+	Name() string
+	Score(c Candidate, now time.Time) Score
+}
+
+// Scorer is one term of a Weighted ranker.
+type Scorer interface {
+	Name() string
+	Score(c Candidate, now time.Time) float64
+}
+
+// Term is a scorer and the weight of its score.
+type Term struct {
+	Scorer Scorer
+	Weight float64
+}
+
+// Weighted is a Ranker whose score is the weighted sum of its terms.
+type Weighted struct {
+	name  string
+	terms []Term
+}
+
+// NewWeighted creates a Weighted ranker.
+func NewWeighted(name string, terms ...Term) *Weighted {
+	return &Weighted{name: name, terms: terms}
+}
+
+func (w *Weighted) Name() string { return w.name }
+
+func (w *Weighted) Score(c Candidate, now time.Time) Score {
+	s := Score{Components: make(map[string]float64, len(w.terms))}
+	for _, t := range w.terms {
+		v := t.Weight * t.Scorer.Score(c, now)
+		s.Components[t.Scorer.Name()] += v
+		s.Total += v
+	}
+	return s
+}
+
+// Recency decays from 1 for a new post by half every HalfLife.
+type Recency struct {
+	HalfLife time.Duration
+}
+
+func (Recency) Name() string { return "recency" }
+
+func (r Recency) Score(c Candidate, now time.Time) float64 {
+	age := max(now.Sub(c.Post.CreatedAt), 0)
+	return math.Exp2(-float64(age) / float64(r.HalfLife))
+}
+
+// Velocity rewards posts gathering likes and comments quickly: it is the
+// logarithm of the recent engagement per hour of the post's age, counting a
+// comment as two likes. Posts younger than an hour count as an hour old.
+type Velocity struct{}
+
+func (Velocity) Name() string { return "velocity" }
+
+func (Velocity) Score(c Candidate, now time.Time) float64 {
+	hours := max(now.Sub(c.Post.CreatedAt).Hours(), 1)
+	engagement := float64(c.Signals.RecentLikes + 2*c.Signals.RecentComments)
+	return math.Log1p(engagement / hours)
+}
+
+// Affinity rewards authors the viewer often likes and comments on.
+type Affinity struct{}
+
+func (Affinity) Name() string { return "affinity" }
+
+func (Affinity) Score(c Candidate, now time.Time) float64 {
+	return math.Log1p(float64(c.Signals.Affinity))
+}
+
+// Content types of posts, told apart by their media.
+const (
+	ContentText     = "text"
+	ContentImage    = "image"
+	ContentVideo    = "video"
+	ContentDocument = "document"
+)
+
+var videoExtensions = map[string]bool{".mp4": true, ".mov": true, ".webm": true, ".m4v": true, ".avi": true, ".mkv": true}
+
+// ContentTypeOf classifies a post by its richest attachment: video, then
+// image, then document. Posts without attachments are classified by the
+// extension of their media URL.
+func ContentTypeOf(p models.PostWithDetails) string {
+	if len(p.Attachments) > 0 {
+		kind := ContentDocument
+		for _, a := range p.Attachments {
+			switch {
+			case strings.HasPrefix(a.MimeType, "video/"):
+				return ContentVideo
+			case strings.HasPrefix(a.MimeType, "image/"):
+				kind = ContentImage
+			}
+		}
+		return kind
+	}
+	if p.MediaURL == nil || *p.MediaURL == "" {
+		return ContentText
+	}
+	u := *p.MediaURL
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	if videoExtensions[strings.ToLower(path.Ext(u))] {
+		return ContentVideo
+	}
+	return ContentImage
+}
+
+// ContentType scores a post by its content type; unlisted types score 0.
+type ContentType struct {
+	Weights map[string]float64
+}
+
+func (ContentType) Name() string { return "content_type" }
+
+func (t ContentType) Score(c Candidate, now time.Time) float64 {
+	return t.Weights[ContentTypeOf(c.Post)]
+}
+
+// Names of the built-in rankers.
+const (
+	Baseline   = "baseline"
+	Engagement = "engagement"
+)
+
+var contentWeights = map[string]float64{ContentText: 0.5, ContentDocument: 0.6, ContentImage: 0.8, ContentVideo: 1}
+
+// New returns the built-in ranker with the given name.
+func New(name string) (Ranker, error) {
+	switch name {
+	case Baseline:
+		return NewWeighted(Baseline,
+			Term{Recency{HalfLife: 12 * time.Hour}, 2},
+			Term{Velocity{}, 1},
+			Term{Affinity{}, 0.5},
+			Term{ContentType{Weights: contentWeights}, 0.25},
+		), nil
+	case Engagement:
+		return NewWeighted(Engagement,
+			Term{Recency{HalfLife: 24 * time.Hour}, 1},
+			Term{Velocity{}, 2},
+			Term{Affinity{}, 0.5},
+			Term{ContentType{Weights: contentWeights}, 0.25},
+		), nil
+	}
+	return nil, fmt.Errorf("ranking: unknown ranker %q", name)
+}
+
+// Experiment splits viewers between rankers. A viewer always gets the same
+// variant as long as the list of variants is unchanged.
+type Experiment struct {
+	Variants []Ranker
+}
+
+// NewExperiment creates an experiment between the built-in rankers listed
+// in names, separated by commas. An empty list selects Baseline alone.
+func NewExperiment(names string) (*Experiment, error) {
+	e := &Experiment{}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		r, err := New(name)
+		if err != nil {
+			return nil, err
+		}
+		e.Variants = append(e.Variants, r)
+	}
+	if len(e.Variants) == 0 {
+		r, _ := New(Baseline)
+		e.Variants = []Ranker{r}
+	}
+	return e, nil
+}
+
+// For returns the ranker assigned to a viewer.
+func (e *Experiment) For(viewerID uuid.UUID) Ranker {
+	h := fnv.New32a()
+	h.Write(viewerID[:])
+	return e.Variants[h.Sum32()%uint32(len(e.Variants))]
+}
+
+// Ranked is a candidate and its score.
+type Ranked struct {
+	Candidate
+	Score Score
+}
+
+// Rank scores candidates and orders them best first, newest first among
+// equal scores.
+func Rank(r Ranker, candidates []Candidate, now time.Time) []Ranked {
+	ranked := make([]Ranked, 0, len(candidates))
+	for _, c := range candidates {
+		ranked = append(ranked, Ranked{Candidate: c, Score: r.Score(c, now)})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if a, b := ranked[i].Score.Total, ranked[j].Score.Total; a != b {
+			return a > b
+		}
+		return pagination.Compare(
+			pagination.Cursor{CreatedAt: ranked[i].Post.CreatedAt, ID: ranked[i].Post.PostID},
+			pagination.Cursor{CreatedAt: ranked[j].Post.CreatedAt, ID: ranked[j].Post.PostID},
+		) < 0
+	})
+	return ranked
+}
@@ -0,0 +1,107 @@
+package ranking
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+var now = time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+func candidate(age time.Duration, media string, signals models.PostSignals) Candidate {
+	p := models.PostWithDetails{PostID: uuid.New(), CreatedAt: now.Add(-age)}
+	if media != "" {
+		p.MediaURL = &media
+	}
+	signals.PostID = p.PostID
+	return Candidate{Post: p, Signals: signals}
+}
+
+func TestRecencyHalvesEveryHalfLife(t *testing.T) {
+	r := Recency{HalfLife: 12 * time.Hour}
+	for age, want := range map[time.Duration]float64{0: 1, 12 * time.Hour: 0.5, 24 * time.Hour: 0.25, -time.Hour: 1} {
+		if got := r.Score(candidate(age, "", models.PostSignals{}), now); math.Abs(got-want) > 1e-9 {
+			t.Errorf("Recency at age %v = %v; want %v", age, got, want)
+		}
+	}
+}
+
+func TestContentTypeOf(t *testing.T) {
+	for media, want := range map[string]string{
+		"":                                    ContentText,
+		"https://cdn.example.com/a.png":       ContentImage,
+		"https://cdn.example.com/a.MP4?x=1":   ContentVideo,
+		"https://cdn.example.com/clip.webm#t": ContentVideo,
+	} {
+		if got := ContentTypeOf(candidate(0, media, models.PostSignals{}).Post); got != want {
+			t.Errorf("ContentTypeOf(%q) = %q; want %q", media, got, want)
+		}
+	}
+
+	p := candidate(0, "https://cdn.example.com/a.pdf", models.PostSignals{}).Post
+	p.Attachments = []models.PostAttachment{{MimeType: "application/pdf"}}
+	if got := ContentTypeOf(p); got != ContentDocument {
+		t.Errorf("ContentTypeOf(a PDF) = %q; want document", got)
+	}
+	p.Attachments = append(p.Attachments, models.PostAttachment{MimeType: "image/png"}, models.PostAttachment{MimeType: "video/mp4"})
+	if got := ContentTypeOf(p); got != ContentVideo {
+		t.Errorf("ContentTypeOf(a PDF, an image and a video) = %q; want video", got)
+	}
+}
+
+func TestWeightedComponentsAddUp(t *testing.T) {
+	r, err := New(Baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := r.Score(candidate(3*time.Hour, "https://cdn.example.com/a.jpg", models.PostSignals{RecentLikes: 4, RecentComments: 1, Affinity: 2}), now)
+
+	sum := 0.0
+	for _, v := range s.Components {
+		sum += v
+	}
+	if len(s.Components) != 4 || math.Abs(sum-s.Total) > 1e-9 {
+		t.Errorf("Score = %+v; want four components adding up to the total", s)
+	}
+}
+
+func TestRankPrefersEngagementAndAffinity(t *testing.T) {
+	r, _ := New(Baseline)
+	quiet := candidate(time.Hour, "", models.PostSignals{})
+	busy := candidate(2*time.Hour, "", models.PostSignals{RecentLikes: 30, RecentComments: 10})
+	friend := candidate(time.Hour, "", models.PostSignals{Affinity: 20})
+
+	ranked := Rank(r, []Candidate{quiet, busy, friend}, now)
+	if ranked[0].Post.PostID != busy.Post.PostID || ranked[1].Post.PostID != friend.Post.PostID || ranked[2].Post.PostID != quiet.Post.PostID {
+		t.Errorf("Rank = [%v %v %v]; want busy, friend, quiet", ranked[0].Score, ranked[1].Score, ranked[2].Score)
+	}
+}
+
+func TestExperimentAssignsViewersStably(t *testing.T) {
+	e, err := NewExperiment("baseline, engagement")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		viewer := uuid.New()
+		name := e.For(viewer).Name()
+		if e.For(viewer).Name() != name {
+			t.Fatalf("viewer %v switched rankers", viewer)
+		}
+		seen[name] = true
+	}
+	if !seen[Baseline] || !seen[Engagement] {
+		t.Errorf("rankers assigned = %v; want both variants", seen)
+	}
+
+	if e, _ := NewExperiment(""); len(e.Variants) != 1 || e.Variants[0].Name() != Baseline {
+		t.Errorf("NewExperiment(\"\") = %+v; want baseline alone", e)
+	}
+	if _, err := NewExperiment("baseline,unknown"); err == nil {
+		t.Error("NewExperiment with an unknown ranker succeeded")
+	}
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryStore keeps buckets in process memory. Limits only hold per process,
+// so it suits a single replica and tests.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+
+	// Now returns the current time; tests replace it.
+	Now func() time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), Now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Now()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	tokens, allowed := limit.Take(limit.Refill(b.tokens, now.Sub(b.updatedAt)))
+	b.tokens, b.updatedAt = tokens, now
+	return limit.Result(tokens, allowed), nil
+}
+
+func (s *MemoryStore) Prune(ctx context.Context, idle time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.Now().Add(-idle)
+	for key, b := range s.buckets {
+		if b.updatedAt.Before(cutoff) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package ratelimit implements token-bucket rate limiting. Each key, such as
+// a user or a client IP within a rule, owns a bucket holding up to Burst
+// tokens that refills at Rate tokens per second; every request takes one.
+// Buckets live in a Store, in memory for a single process or in the database
+// when several replicas must share the limits.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Limit allows Burst requests at once and Rate requests per second on
+// average.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute allows n requests per minute, all of which may come at once.
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// Refill returns the tokens of a bucket that held tokens elapsed ago.
+func (l Limit) Refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed > 0 {
+		tokens += elapsed.Seconds() * l.Rate
+	}
+	return math.Min(tokens, float64(l.Burst))
+}
+
+// Take takes one token from a bucket holding tokens, if there is one, and
+// returns what is left.
+func (l Limit) Take(tokens float64) (left float64, allowed bool) {
+	if tokens >= 1 {
+		return tokens - 1, true
+	}
+	return tokens, false
+}
+
+// Result describes the bucket after a request.
+func (l Limit) Result(tokens float64, allowed bool) Result {
+	r := Result{
+		Allowed:   allowed,
+		Limit:     l.Burst,
+		Remaining: int(math.Floor(tokens)),
+	}
+	if l.Rate > 0 {
+		r.ResetAfter = seconds((float64(l.Burst) - tokens) / l.Rate)
+		if !allowed {
+			r.RetryAfter = seconds((1 - tokens) / l.Rate)
+		}
+	}
+	return r
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed bool
+
+	// Limit is the bucket size and Remaining the requests left in it.
+	Limit     int
+	Remaining int
+
+	// ResetAfter is when the bucket will be full again, RetryAfter when
+	// a rejected request may be retried.
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets.
+type Store interface {
+	// Take takes one token from the bucket of key, creating a full bucket if
+	// there is none.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+
+	// Prune forgets buckets untouched for idle. A bucket idle longer than it
+	// takes to refill is full, so forgetting it changes nothing.
+	Prune(ctx context.Context, idle time.Duration) error
+}
+
+// Rule limits requests with one of Methods to one of Routes. Routes are
+// Gin route templates; one ending in "*" matches every route it prefixes.
+// Empty Methods or Routes match anything. Name scopes the buckets so rules
+// do not share tokens.
+type Rule struct {
+	Name    string
+	Methods []string
+	Routes  []string
+	Limit   Limit
+}
+
+// Match returns the first rule covering a request.
+func Match(rules []Rule, method, route string) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.matches(method, route) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (r Rule) matches(method, route string) bool {
+	if len(r.Methods) > 0 && !slices.Contains(r.Methods, method) {
+		return false
+	}
+	if len(r.Routes) == 0 {
+		return true
+	}
+	for _, pattern := range r.Routes {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(route, prefix) || pattern == route {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreRefills(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.Now = func() time.Time { return now }
+	limit := PerMinute(2)
+	ctx := context.Background()
+
+	for i, want := range []int{1, 0} {
+		r, _ := store.Take(ctx, "k", limit)
+		if !r.Allowed || r.Remaining != want {
+			t.Fatalf("take %d: %+v; want allowed with %d remaining", i, r, want)
+		}
+	}
+
+	r, _ := store.Take(ctx, "k", limit)
+	if r.Allowed || r.RetryAfter != 30*time.Second {
+		t.Fatalf("third take: %+v; want rejected, retry after 30s", r)
+	}
+	if r, _ := store.Take(ctx, "other", limit); !r.Allowed {
+		t.Fatal("keys share a bucket")
+	}
+
+	now = now.Add(30 * time.Second)
+	if r, _ := store.Take(ctx, "k", limit); !r.Allowed {
+		t.Fatalf("after refill: %+v; want allowed", r)
+	}
+}
+
+func TestMemoryStorePrune(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore()
+	store.Now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store.Take(ctx, "old", PerMinute(1))
+	now = now.Add(time.Hour)
+	store.Take(ctx, "new", PerMinute(1))
+
+	if err := store.Prune(ctx, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.buckets["old"]; ok {
+		t.Error("idle bucket kept")
+	}
+	if _, ok := store.buckets["new"]; !ok {
+		t.Error("recent bucket pruned")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	rules := []Rule{
+		{Name: "auth", Routes: []string{"/api/v1/auth/*"}},
+		{Name: "uploads", Methods: []string{"POST"}, Routes: []string{"/api/v1/uploads", "/api/v1/posts"}},
+		{Name: "default"},
+	}
+	tests := []struct {
+		method, route, want string
+	}{
+		{"POST", "/api/v1/auth/login", "auth"},
+		{"POST", "/api/v1/uploads", "uploads"},
+		{"POST", "/api/v1/posts", "uploads"},
+		{"GET", "/api/v1/posts", "default"},
+		{"POST", "/api/v1/posts/:post_id/likes", "default"},
+		{"GET", "", "default"},
+	}
+	for _, tt := range tests {
+		if rule, _ := Match(rules, tt.method, tt.route); rule.Name != tt.want {
+			t.Errorf("Match(%s %q) = %q; want %q", tt.method, tt.route, rule.Name, tt.want)
+		}
+	}
+}
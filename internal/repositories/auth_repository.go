@@ -3,57 +3,125 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
 )
 
-type UserRepository struct {
+// UserRepository persists user accounts.
+type UserRepository interface {
+	CreateUser(ctx context.Context, user models.User) error
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	GetUserByEmailOrUsername(ctx context.Context, identifier string) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	// GetUsersByUsernames returns the users with the given usernames;
+	// unknown usernames are skipped.
+	GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.User, error)
+	UpdatePassword(ctx context.Context, email, passwordHash string) error
+	DeleteUser(ctx context.Context, id string) error
+}
+
+type userRepo struct {
 	DB *sql.DB
 }
 
+// NewUserRepository creates a Postgres-backed UserRepository.
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepo{DB: db}
+}
+
 // CreateUser saves a new user into the database
-func (r *UserRepository) CreateUser(ctx context.Context, user models.User) error {
+func (r *userRepo) CreateUser(ctx context.Context, user models.User) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `INSERT INTO users (id, email, username, password_hash, created_at, updated_at) 
 	          VALUES (uuid_generate_v4(), $1, $2, $3, $4, $5)`
-	_, err := r.DB.ExecContext(ctx, query, user.Email, user.Username, user.PasswordHash, user.CreatedAt, user.UpdatedAt)
-	return err
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, user.Email, user.Username, user.PasswordHash, user.CreatedAt, user.UpdatedAt)
+	return mapError(err)
 }
 
-// GetUserByEmail retrieves a user by email
-func (r *UserRepository) GetUserByEmailOrUsername(identifier string) (*models.User, error) {
+// GetUserByID retrieves a user by primary key
+func (r *userRepo) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, email, username, password_hash, created_at, updated_at FROM users WHERE id = $1`
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, id)
+	var user models.User
+	if err := row.Scan(&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		return nil, mapError(err)
+	}
+	return &user, nil
+}
+
+// GetUserByEmailOrUsername retrieves a user by email or username
+func (r *userRepo) GetUserByEmailOrUsername(ctx context.Context, identifier string) (*models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `SELECT id, email, username, password_hash, created_at, updated_at FROM users WHERE email = $1 OR username = $1`
-	row := r.DB.QueryRow(query, identifier)
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, identifier)
 	var user models.User
-	err := row.Scan(&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
-		}
-		return nil, err
+	if err := row.Scan(&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		return nil, mapError(err)
 	}
 	return &user, nil
 }
 
 // GetUserByEmail retrieves a user by email
-func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
+func (r *userRepo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `SELECT id, email, username, password_hash, created_at, updated_at FROM users WHERE email = $1 or username = $1`
-	row := r.DB.QueryRow(query, email)
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, email)
 	var user models.User
-	err := row.Scan(&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	if err := row.Scan(&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		return nil, mapError(err)
+	}
+	return &user, nil
+}
+
+// GetUsersByUsernames retrieves the users with the given usernames
+func (r *userRepo) GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, email, username, password_hash, created_at, updated_at FROM users WHERE username = ANY($1)`
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, pq.Array(usernames))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, mapError(err)
 		}
-		return nil, err
+		users = append(users, user)
 	}
-	return &user, nil
+	return users, mapError(rows.Err())
 }
 
 // UpdatePassword updates a user's password
-func (r *UserRepository) UpdatePassword(ctx context.Context, email, passwordHash string) error {
+func (r *userRepo) UpdatePassword(ctx context.Context, email, passwordHash string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `UPDATE users SET password_hash = $1, updated_at = $2 WHERE email = $3`
-	_, err := r.DB.ExecContext(ctx, query, passwordHash, time.Now(), email)
-	return err
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, passwordHash, time.Now(), email)
+	return mapError(err)
+}
+
+// DeleteUser removes a user; posts, likes, comments and follows cascade
+func (r *userRepo) DeleteUser(ctx context.Context, id string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	return mapError(err)
 }
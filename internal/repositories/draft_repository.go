@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+)
+
+// DraftRepository stores drafts and scheduled posts.
+type DraftRepository interface {
+	// Create stores a draft and fills in its ID and timestamps.
+	Create(ctx context.Context, draft *models.PostDraft) error
+	GetByID(ctx context.Context, draftID uuid.UUID) (*models.PostDraft, error)
+	// GetByUserID returns a page of the drafts of a user, or of their
+	// scheduled posts when scheduled is set, newest first.
+	GetByUserID(ctx context.Context, userID uuid.UUID, scheduled bool, page pagination.Params) (pagination.Page[models.PostDraft], error)
+	// Update replaces the text, visibility and publish time of a draft.
+	Update(ctx context.Context, draft *models.PostDraft) (*models.PostDraft, error)
+	Delete(ctx context.Context, draftID uuid.UUID) error
+	// ClaimDue locks the earliest scheduled post due at now, leaving out
+	// the ones in skip and the ones locked by other transactions, so
+	// schedulers running side by side never claim the same post. It
+	// returns ErrNotFound when none is due. Run it in a transaction that
+	// publishes and deletes the post.
+	ClaimDue(ctx context.Context, now time.Time, skip []uuid.UUID) (*models.PostDraft, error)
+}
+
+type draftRepo struct {
+	DB *sql.DB
+}
+
+// NewDraftRepository creates a Postgres-backed DraftRepository.
+func NewDraftRepository(db *sql.DB) DraftRepository {
+	return &draftRepo{DB: db}
+}
+
+// draftColumns are the post_drafts columns read by draftFields.
+const draftColumns = `id, user_id, post_content, visibility, publish_at, created_at, updated_at`
+
+// draftFields returns the scan destinations of draftColumns.
+func draftFields(d *models.PostDraft) []any {
+	return []any{&d.ID, &d.UserID, &d.PostContent, &d.Visibility, &d.PublishAt, &d.CreatedAt, &d.UpdatedAt}
+}
+
+func (r *draftRepo) Create(ctx context.Context, draft *models.PostDraft) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `
+		INSERT INTO post_drafts (user_id, post_content, visibility, publish_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+draftColumns,
+		draft.UserID, draft.PostContent, draft.Visibility, draft.PublishAt,
+	).Scan(draftFields(draft)...)
+	return mapError(err)
+}
+
+func (r *draftRepo) GetByID(ctx context.Context, draftID uuid.UUID) (*models.PostDraft, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var draft models.PostDraft
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `
+		SELECT `+draftColumns+`
+		FROM post_drafts
+		WHERE id = $1`, draftID,
+	).Scan(draftFields(&draft)...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &draft, nil
+}
+
+func (r *draftRepo) GetByUserID(ctx context.Context, userID uuid.UUID, scheduled bool, page pagination.Params) (pagination.Page[models.PostDraft], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("created_at", "id", 3)
+	query := `
+		SELECT ` + draftColumns + `
+		FROM post_drafts
+		WHERE user_id = $1 AND (publish_at IS NOT NULL) = $2 AND ` + after + `
+		` + page.OrderBy("created_at", "id")
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, append([]any{userID, scheduled}, args...)...)
+	if err != nil {
+		return pagination.Page[models.PostDraft]{}, mapError(err)
+	}
+	defer rows.Close()
+
+	var drafts []models.PostDraft
+	for rows.Next() {
+		var d models.PostDraft
+		if err := rows.Scan(draftFields(&d)...); err != nil {
+			return pagination.Page[models.PostDraft]{}, mapError(err)
+		}
+		drafts = append(drafts, d)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.PostDraft]{}, mapError(err)
+	}
+	return pagination.NewPage(drafts, page, draftCursor), nil
+}
+
+func draftCursor(d models.PostDraft) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: d.CreatedAt, ID: d.ID}
+}
+
+func (r *draftRepo) Update(ctx context.Context, draft *models.PostDraft) (*models.PostDraft, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var updated models.PostDraft
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `
+		UPDATE post_drafts
+		SET post_content = $2, visibility = $3, publish_at = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+draftColumns,
+		draft.ID, draft.PostContent, draft.Visibility, draft.PublishAt,
+	).Scan(draftFields(&updated)...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &updated, nil
+}
+
+func (r *draftRepo) Delete(ctx context.Context, draftID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var id uuid.UUID
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `DELETE FROM post_drafts WHERE id = $1 RETURNING id`, draftID).Scan(&id)
+	return mapError(err)
+}
+
+func (r *draftRepo) ClaimDue(ctx context.Context, now time.Time, skip []uuid.UUID) (*models.PostDraft, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	skipped := make([]string, len(skip))
+	for i, id := range skip {
+		skipped[i] = id.String()
+	}
+
+	var draft models.PostDraft
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `
+		SELECT `+draftColumns+`
+		FROM post_drafts
+		WHERE publish_at <= $1 AND NOT (id = ANY($2::uuid[]))
+		ORDER BY publish_at, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`, now, pq.Array(skipped),
+	).Scan(draftFields(&draft)...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &draft, nil
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// entitiesValue encodes entities for a JSONB entities column.
+func entitiesValue(entities []models.Entity) (string, error) {
+	if entities == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(entities)
+	return string(b), err
+}
+
+// entitiesColumn scans a JSONB entities column into dst.
+type entitiesColumn struct {
+	dst *[]models.Entity
+}
+
+func (c entitiesColumn) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	case nil:
+		*c.dst = []models.Entity{}
+		return nil
+	default:
+		return fmt.Errorf("entities: cannot scan %T", src)
+	}
+	*c.dst = []models.Entity{}
+	return json.Unmarshal(b, c.dst)
+}
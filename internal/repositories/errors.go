@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+)
+
+// Postgres error codes the repositories translate.
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqInvalidTextRepr     = "22P02"
+)
+
+// mapError classifies database errors as apperrors kinds so services and
+// handlers never have to inspect driver errors. The original error stays in
+// the chain, so errors.Is(err, sql.ErrNoRows) keeps working.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.Wrap(apperrors.ErrNotFound, err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return apperrors.Wrap(apperrors.ErrConflict, err)
+		case pqForeignKeyViolation:
+			// A missing referenced row, e.g. liking a post that does not exist.
+			return apperrors.Wrap(apperrors.ErrNotFound, err)
+		case pqInvalidTextRepr:
+			return apperrors.Wrap(apperrors.ErrValidation, err)
+		}
+	}
+	return err
+}
@@ -1,93 +1,120 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 )
 
-type FollowRepository struct {
+// FollowRepository stores the follow graph.
+type FollowRepository interface {
+	FollowUser(ctx context.Context, followerID, followedID uuid.UUID) error
+	UnfollowUser(ctx context.Context, followerID, followedID uuid.UUID) error
+	// GetFollowers and GetFollowings return a page of the follow graph,
+	// latest follows first.
+	GetFollowers(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error)
+	GetFollowings(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error)
+}
+
+type followRepo struct {
 	DB *sql.DB
 }
 
-// FollowUser allows a user to follow another user
-func (repo *FollowRepository) FollowUser(followerID, followedID uuid.UUID) error {
-	_, err := repo.DB.Exec(`
+// NewFollowRepository creates a Postgres-backed FollowRepository.
+func NewFollowRepository(db *sql.DB) FollowRepository {
+	return &followRepo{DB: db}
+}
+
+// FollowUser allows a user to follow another user. Both tables are written
+// through conn, so callers wrap it in TxManager.WithTx to keep them in step.
+func (repo *followRepo) FollowUser(ctx context.Context, followerID, followedID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.DB).ExecContext(ctx, `
 		INSERT INTO followers (follower_id, followed_id) 
 		VALUES ($1, $2) 
 		ON CONFLICT (follower_id, followed_id) DO NOTHING`, followerID, followedID)
 
 	if err != nil {
-		return err
+		return mapError(err)
 	}
 
-	_, err = repo.DB.Exec(`
+	_, err = conn(ctx, repo.DB).ExecContext(ctx, `
 		INSERT INTO followings (follower_id, followed_id) 
 		VALUES ($1, $2) 
 		ON CONFLICT (follower_id, followed_id) DO NOTHING`, followerID, followedID)
 
-	return err
+	return mapError(err)
 }
 
 // UnfollowUser allows a user to unfollow another user
-func (repo *FollowRepository) UnfollowUser(followerID, followedID uuid.UUID) error {
-	_, err := repo.DB.Exec(`
+func (repo *followRepo) UnfollowUser(ctx context.Context, followerID, followedID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.DB).ExecContext(ctx, `
 		DELETE FROM followers 
 		WHERE follower_id = $1 AND followed_id = $2`, followerID, followedID)
 
 	if err != nil {
-		return err
+		return mapError(err)
 	}
 
-	_, err = repo.DB.Exec(`
+	_, err = conn(ctx, repo.DB).ExecContext(ctx, `
 		DELETE FROM followings 
 		WHERE follower_id = $1 AND followed_id = $2`, followerID, followedID)
 
-	return err
+	return mapError(err)
 }
 
-// GetFollowers retrieves a list of followers for a user
-func (repo *FollowRepository) GetFollowers(userID uuid.UUID) ([]uuid.UUID, error) {
-	rows, err := repo.DB.Query(`
-		SELECT follower_id 
-		FROM followers 
-		WHERE followed_id = $1`, userID)
-
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var followers []uuid.UUID
-	for rows.Next() {
-		var followerID uuid.UUID
-		if err := rows.Scan(&followerID); err != nil {
-			return nil, err
-		}
-		followers = append(followers, followerID)
-	}
-	return followers, nil
+// GetFollowers retrieves a page of the followers of a user
+func (repo *followRepo) GetFollowers(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("created_at", "follower_id", 2)
+	return queryUserPage(ctx, repo.DB, page, `
+		SELECT created_at, follower_id
+		FROM followers
+		WHERE followed_id = $1 AND `+after+`
+		`+page.OrderBy("created_at", "follower_id"), append([]any{userID}, args...)...)
 }
 
-// GetFollowings retrieves a list of users that a user is following
-func (repo *FollowRepository) GetFollowings(userID uuid.UUID) ([]uuid.UUID, error) {
-	rows, err := repo.DB.Query(`
-		SELECT followed_id 
-		FROM followings 
-		WHERE follower_id = $1`, userID)
+// GetFollowings retrieves a page of the users that a user is following
+func (repo *followRepo) GetFollowings(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("created_at", "followed_id", 2)
+	return queryUserPage(ctx, repo.DB, page, `
+		SELECT created_at, followed_id
+		FROM followings
+		WHERE follower_id = $1 AND `+after+`
+		`+page.OrderBy("created_at", "followed_id"), append([]any{userID}, args...)...)
+}
 
+// queryUserPage runs a page query selecting the time a user was related to
+// something and their ID, and returns the page of IDs.
+func queryUserPage(ctx context.Context, db *sql.DB, page pagination.Params, query string, args ...any) (pagination.Page[uuid.UUID], error) {
+	rows, err := conn(ctx, db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return pagination.Page[uuid.UUID]{}, mapError(err)
 	}
 	defer rows.Close()
 
-	var followings []uuid.UUID
+	var keys []pagination.Cursor
 	for rows.Next() {
-		var followedID uuid.UUID
-		if err := rows.Scan(&followedID); err != nil {
-			return nil, err
+		var key pagination.Cursor
+		if err := rows.Scan(&key.CreatedAt, &key.ID); err != nil {
+			return pagination.Page[uuid.UUID]{}, mapError(err)
 		}
-		followings = append(followings, followedID)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[uuid.UUID]{}, mapError(err)
 	}
-	return followings, nil
+	return pagination.NewIDPage(keys, page), nil
 }
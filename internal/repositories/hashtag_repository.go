@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+)
+
+// HashtagRepository indexes the hashtags of posts and comments. Tags are
+// stored lower-cased and without the leading #.
+type HashtagRepository interface {
+	// IndexPost sets the hashtags of a post, dropping the ones it no
+	// longer uses.
+	IndexPost(ctx context.Context, postID uuid.UUID, tags []string) error
+	// IndexComment sets the hashtags of a comment.
+	IndexComment(ctx context.Context, commentID uuid.UUID, tags []string) error
+	// GetPosts returns a page of the posts using tag that viewerID can
+	// see, newest first.
+	GetPosts(ctx context.Context, viewerID uuid.UUID, tag string, page pagination.Params) (pagination.Page[models.PostWithDetails], error)
+	// Trending returns the limit hashtags used by the most posts and
+	// comments since the given time, most used first. Only public posts
+	// and the comments on them are counted.
+	Trending(ctx context.Context, since time.Time, limit int) ([]models.HashtagCount, error)
+}
+
+type hashtagRepo struct {
+	DB *sql.DB
+}
+
+// NewHashtagRepository creates a Postgres-backed HashtagRepository.
+func NewHashtagRepository(db *sql.DB) HashtagRepository {
+	return &hashtagRepo{DB: db}
+}
+
+func (r *hashtagRepo) IndexPost(ctx context.Context, postID uuid.UUID, tags []string) error {
+	return r.index(ctx, "post_hashtags", "post_id", postID, tags)
+}
+
+func (r *hashtagRepo) IndexComment(ctx context.Context, commentID uuid.UUID, tags []string) error {
+	return r.index(ctx, "comment_hashtags", "comment_id", commentID, tags)
+}
+
+// index links id to tags in table, keyed by column. table and column are
+// never user input.
+func (r *hashtagRepo) index(ctx context.Context, table, column string, id uuid.UUID, tags []string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	db := conn(ctx, r.DB)
+	if len(tags) > 0 {
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO hashtags (tag)
+			SELECT unnest($1::text[])
+			ON CONFLICT (tag) DO NOTHING`, pq.Array(tags)); err != nil {
+			return mapError(err)
+		}
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO `+table+` (`+column+`, hashtag_id)
+			SELECT $1, id FROM hashtags WHERE tag = ANY($2::text[])
+			ON CONFLICT DO NOTHING`, id, pq.Array(tags)); err != nil {
+			return mapError(err)
+		}
+	}
+	_, err := db.ExecContext(ctx, `
+		DELETE FROM `+table+`
+		WHERE `+column+` = $1
+		  AND hashtag_id NOT IN (SELECT id FROM hashtags WHERE tag = ANY($2::text[]))`, id, pq.Array(tags))
+	return mapError(err)
+}
+
+func (r *hashtagRepo) GetPosts(ctx context.Context, viewerID uuid.UUID, tag string, page pagination.Params) (pagination.Page[models.PostWithDetails], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("cp.created_at", "cp.id", 3)
+	query := postDetailsQuery + `
+		WHERE cp.id IN (
+			SELECT ph.post_id
+			FROM post_hashtags ph
+			JOIN hashtags h ON h.id = ph.hashtag_id
+			WHERE h.tag = $1
+		) AND ` + visibleTo(2) + ` AND ` + after + `
+		` + page.OrderBy("cp.created_at", "cp.id")
+
+	posts, err := queryPostDetails(ctx, r.DB, viewerID, query, append([]any{tag, viewerID}, args...)...)
+	if err != nil {
+		return pagination.Page[models.PostWithDetails]{}, err
+	}
+	return pagination.NewPage(posts, page, postCursor), nil
+}
+
+func (r *hashtagRepo) Trending(ctx context.Context, since time.Time, limit int) ([]models.HashtagCount, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, `
+		SELECT h.tag, COUNT(*) AS uses
+		FROM (
+			SELECT ph.hashtag_id
+			FROM post_hashtags ph
+			JOIN content_post cp ON cp.id = ph.post_id
+			WHERE ph.created_at >= $1 AND cp.visibility = 'public'
+			UNION ALL
+			SELECT ch.hashtag_id
+			FROM comment_hashtags ch
+			JOIN post_comments pc ON pc.id = ch.comment_id
+			JOIN content_post cp ON cp.id = pc.post_id
+			WHERE ch.created_at >= $1 AND cp.visibility = 'public'
+		) used
+		JOIN hashtags h ON h.id = used.hashtag_id
+		GROUP BY h.tag
+		ORDER BY uses DESC, h.tag
+		LIMIT $2`, since, limit)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	trending := []models.HashtagCount{}
+	for rows.Next() {
+		var c models.HashtagCount
+		if err := rows.Scan(&c.Tag, &c.Count); err != nil {
+			return nil, mapError(err)
+		}
+		trending = append(trending, c)
+	}
+	return trending, mapError(rows.Err())
+}
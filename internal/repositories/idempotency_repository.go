@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+)
+
+type idempotencyRepo struct {
+	DB *sql.DB
+}
+
+// NewIdempotencyRepository creates a Postgres-backed idempotency store, so a
+// retry is recognized whichever replica serves it.
+func NewIdempotencyRepository(db *sql.DB) idempotency.Store {
+	return &idempotencyRepo{DB: db}
+}
+
+// claimKeyQuery inserts the key, or takes over a row that expired or whose
+// request has held it past the lock timeout ($4 seconds). locked_at uses
+// the wall clock so that each claim is distinct even within a transaction.
+const claimKeyQuery = `
+	INSERT INTO idempotency_keys AS k (key, fingerprint, locked_at, expires_at)
+	VALUES ($1, $2, clock_timestamp(), NOW() + make_interval(secs => $3))
+	ON CONFLICT (key) DO UPDATE SET
+		fingerprint = EXCLUDED.fingerprint,
+		status = NULL,
+		content_type = NULL,
+		body = NULL,
+		locked_at = EXCLUDED.locked_at,
+		expires_at = EXCLUDED.expires_at
+	WHERE k.expires_at <= NOW()
+		OR (k.status IS NULL AND k.locked_at <= NOW() - make_interval(secs => $4))
+	RETURNING locked_at`
+
+// Reserve claims key. When the claim loses to a live row it reads that row
+// in a second statement, which unlike the first sees rows committed by
+// concurrent claims. The row can be pruned in between, so it tries twice.
+// The locked_at of a claim identifies it to Complete and Release.
+func (r *idempotencyRepo) Reserve(ctx context.Context, key, fingerprint string, policy idempotency.Policy) (idempotency.Lock, *idempotency.Record, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	db := conn(ctx, r.DB)
+	for attempt := 0; ; attempt++ {
+		lock := idempotency.Lock{Key: key}
+		err := db.QueryRowContext(ctx, claimKeyQuery, key, fingerprint, policy.TTL.Seconds(), policy.LockTimeout.Seconds()).Scan(&lock.LockedAt)
+		if err == nil {
+			return lock, nil, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return idempotency.Lock{}, nil, mapError(err)
+		}
+
+		var record idempotency.Record
+		var status sql.NullInt64
+		var contentType sql.NullString
+		var body []byte
+		err = db.QueryRowContext(ctx,
+			`SELECT fingerprint, status, content_type, body FROM idempotency_keys WHERE key = $1`, key,
+		).Scan(&record.Fingerprint, &status, &contentType, &body)
+		if errors.Is(err, sql.ErrNoRows) && attempt == 0 {
+			continue
+		}
+		if err != nil {
+			return idempotency.Lock{}, nil, mapError(err)
+		}
+		if status.Valid {
+			record.Response = &idempotency.Response{Status: int(status.Int64), ContentType: contentType.String, Body: body}
+		}
+		return idempotency.Lock{}, &record, nil
+	}
+}
+
+func (r *idempotencyRepo) Complete(ctx context.Context, lock idempotency.Lock, resp idempotency.Response) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	res, err := conn(ctx, r.DB).ExecContext(ctx, `
+		UPDATE idempotency_keys SET status = $3, content_type = $4, body = $5
+		WHERE key = $1 AND locked_at = $2`,
+		lock.Key, lock.LockedAt, resp.Status, resp.ContentType, resp.Body)
+	return lockHeld(res, err)
+}
+
+// Release deletes the key while it has no response. A claim that still
+// holds a completed key has nothing to release, so only a claim that no
+// longer holds the key at all has lost its lock.
+func (r *idempotencyRepo) Release(ctx context.Context, lock idempotency.Lock) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var held bool
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `
+		WITH held AS (
+			SELECT key FROM idempotency_keys WHERE key = $1 AND locked_at = $2 FOR UPDATE
+		), released AS (
+			DELETE FROM idempotency_keys WHERE key = $1 AND locked_at = $2 AND status IS NULL
+		)
+		SELECT EXISTS (SELECT 1 FROM held)`,
+		lock.Key, lock.LockedAt).Scan(&held)
+	if err != nil {
+		return mapError(err)
+	}
+	if !held {
+		return idempotency.ErrLockLost
+	}
+	return nil
+}
+
+// lockHeld turns an update that matched no row into ErrLockLost.
+func lockHeld(res sql.Result, err error) error {
+	if err != nil {
+		return mapError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return mapError(err)
+	}
+	if n == 0 {
+		return idempotency.ErrLockLost
+	}
+	return nil
+}
+
+// Prune deletes expired keys.
+func (r *idempotencyRepo) Prune(ctx context.Context) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	return mapError(err)
+}
@@ -6,13 +6,28 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 )
 
-type JobRepository struct {
+// JobRepository stores job listings.
+type JobRepository interface {
+	CreateJobPost(ctx context.Context, post *models.JobPost) error
+	GetAll(ctx context.Context, page pagination.Params) (pagination.Page[models.JobPost], error)
+}
+
+type jobRepo struct {
 	DB *sql.DB
 }
 
-func (r *JobRepository) CreateJobPost(post *models.JobPost) error {
+// NewJobRepository creates a Postgres-backed JobRepository.
+func NewJobRepository(db *sql.DB) JobRepository {
+	return &jobRepo{DB: db}
+}
+
+func (r *jobRepo) CreateJobPost(ctx context.Context, post *models.JobPost) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		INSERT INTO job_post (
 			id, user_id, job_title, company_name, job_description,
@@ -25,26 +40,30 @@ func (r *JobRepository) CreateJobPost(post *models.JobPost) error {
 
 	post.ID = uuid.New()
 
-	_, err := r.DB.Exec(
+	_, err := conn(ctx, r.DB).ExecContext(ctx,
 		query,
 		post.ID, post.UserID, post.JobTitle, post.CompanyName, post.JobDescription,
 		post.JobApplyURL, post.Location, post.PostDate, post.LastDateToApply, post.CreatedAt,
 	)
 
-	return err
+	return mapError(err)
 }
 
-func (r *JobRepository) GetAll(ctx context.Context) ([]models.JobPost, error) {
+func (r *jobRepo) GetAll(ctx context.Context, page pagination.Params) (pagination.Page[models.JobPost], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("created_at", "id", 1)
 	query := `
 		SELECT id, user_id, job_title, company_name, job_description,
 		       job_apply_url, location, post_date, last_date_to_apply, created_at
 		FROM job_post
-		ORDER BY created_at DESC
-	`
+		WHERE ` + after + `
+		` + page.OrderBy("created_at", "id")
 
-	rows, err := r.DB.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.JobPost]{}, mapError(err)
 	}
 	defer rows.Close()
 
@@ -56,10 +75,17 @@ func (r *JobRepository) GetAll(ctx context.Context) ([]models.JobPost, error) {
 			&jp.JobApplyURL, &jp.Location, &jp.PostDate, &jp.LastDateToApply, &jp.CreatedAt,
 		)
 		if err != nil {
-			return nil, err
+			return pagination.Page[models.JobPost]{}, mapError(err)
 		}
 		jobPosts = append(jobPosts, jp)
 	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.JobPost]{}, mapError(err)
+	}
+
+	return pagination.NewPage(jobPosts, page, jobCursor), nil
+}
 
-	return jobPosts, nil
+func jobCursor(jp models.JobPost) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: jp.CreatedAt, ID: jp.ID}
 }
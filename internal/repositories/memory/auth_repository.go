@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type userRepo struct {
+	store *Store
+}
+
+// NewUserRepository creates an in-memory UserRepository.
+func NewUserRepository(store *Store) repositories.UserRepository {
+	return &userRepo{store: store}
+}
+
+func (r *userRepo) CreateUser(ctx context.Context, user models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, u := range r.store.users {
+		if u.Email == user.Email {
+			return uniqueViolation("users_email_key")
+		}
+		if u.Username == user.Username {
+			return uniqueViolation("users_username_key")
+		}
+	}
+
+	user.ID = uuid.NewString()
+	r.store.users = append(r.store.users, user)
+	return nil
+}
+
+func (r *userRepo) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	if _, err := parseUUID(id); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, u := range r.store.users {
+		if u.ID == id {
+			return &u, nil
+		}
+	}
+	return nil, errNoRows()
+}
+
+func (r *userRepo) GetUserByEmailOrUsername(ctx context.Context, identifier string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, u := range r.store.users {
+		if u.Email == identifier || u.Username == identifier {
+			return &u, nil
+		}
+	}
+	return nil, errNoRows()
+}
+
+func (r *userRepo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.GetUserByEmailOrUsername(ctx, email)
+}
+
+func (r *userRepo) GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var users []models.User
+	for _, u := range r.store.users {
+		if slices.Contains(usernames, u.Username) {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func (r *userRepo) UpdatePassword(ctx context.Context, email, passwordHash string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.users {
+		if r.store.users[i].Email == email {
+			r.store.users[i].PasswordHash = passwordHash
+			r.store.users[i].UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *userRepo) DeleteUser(ctx context.Context, id string) error {
+	userID, err := parseUUID(id)
+	if err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.deleteUser(userID)
+	return nil
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type draftRepo struct {
+	store *Store
+}
+
+// NewDraftRepository creates an in-memory DraftRepository.
+func NewDraftRepository(store *Store) repositories.DraftRepository {
+	return &draftRepo{store: store}
+}
+
+func (r *draftRepo) Create(ctx context.Context, draft *models.PostDraft) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(draft.UserID) {
+		return foreignKeyViolation("post_drafts_user_id_fkey")
+	}
+	now := time.Now()
+	draft.ID, draft.CreatedAt, draft.UpdatedAt = uuid.New(), now, now
+	r.store.drafts = append(r.store.drafts, *draft)
+	return nil
+}
+
+func (r *draftRepo) GetByID(ctx context.Context, draftID uuid.UUID) (*models.PostDraft, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, d := range r.store.drafts {
+		if d.ID == draftID {
+			return &d, nil
+		}
+	}
+	return nil, errNoRows()
+}
+
+func (r *draftRepo) GetByUserID(ctx context.Context, userID uuid.UUID, scheduled bool, page pagination.Params) (pagination.Page[models.PostDraft], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var drafts []models.PostDraft
+	for _, d := range r.store.drafts {
+		if d.UserID == userID && (d.PublishAt != nil) == scheduled {
+			drafts = append(drafts, d)
+		}
+	}
+	return pagination.Apply(drafts, page, func(d models.PostDraft) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: d.CreatedAt, ID: d.ID}
+	}), nil
+}
+
+func (r *draftRepo) Update(ctx context.Context, draft *models.PostDraft) (*models.PostDraft, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, d := range r.store.drafts {
+		if d.ID != draft.ID {
+			continue
+		}
+		d.PostContent, d.Visibility, d.PublishAt = draft.PostContent, draft.Visibility, draft.PublishAt
+		d.UpdatedAt = time.Now()
+		r.store.drafts[i] = d
+		return &d, nil
+	}
+	return nil, errNoRows()
+}
+
+func (r *draftRepo) Delete(ctx context.Context, draftID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n := len(r.store.drafts)
+	r.store.drafts = filter(r.store.drafts, func(d models.PostDraft) bool { return d.ID != draftID })
+	if len(r.store.drafts) == n {
+		return errNoRows()
+	}
+	return nil
+}
+
+// ClaimDue has no locks to take: the memory TxManager already runs one
+// transaction at a time.
+func (r *draftRepo) ClaimDue(ctx context.Context, now time.Time, skip []uuid.UUID) (*models.PostDraft, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var due []models.PostDraft
+	for _, d := range r.store.drafts {
+		if d.PublishAt != nil && !d.PublishAt.After(now) && !slices.Contains(skip, d.ID) {
+			due = append(due, d)
+		}
+	}
+	if len(due) == 0 {
+		return nil, errNoRows()
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].PublishAt.Before(*due[j].PublishAt) })
+	return &due[0], nil
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type followRepo struct {
+	store *Store
+}
+
+// NewFollowRepository creates an in-memory FollowRepository.
+func NewFollowRepository(store *Store) repositories.FollowRepository {
+	return &followRepo{store: store}
+}
+
+func (repo *followRepo) FollowUser(ctx context.Context, followerID, followedID uuid.UUID) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if !repo.store.userExists(followerID) {
+		return foreignKeyViolation("followers_follower_id_fkey")
+	}
+	if !repo.store.userExists(followedID) {
+		return foreignKeyViolation("followers_followed_id_fkey")
+	}
+
+	edge := follow{FollowerID: followerID, FollowedID: followedID, CreatedAt: time.Now()}
+	repo.store.followers = insertEdge(repo.store.followers, edge)
+	repo.store.followings = insertEdge(repo.store.followings, edge)
+	return nil
+}
+
+func (repo *followRepo) UnfollowUser(ctx context.Context, followerID, followedID uuid.UUID) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	other := func(f follow) bool { return f.FollowerID != followerID || f.FollowedID != followedID }
+	repo.store.followers = filter(repo.store.followers, other)
+	repo.store.followings = filter(repo.store.followings, other)
+	return nil
+}
+
+func (repo *followRepo) GetFollowers(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	var followers []pagination.Cursor
+	for _, f := range repo.store.followers {
+		if f.FollowedID == userID {
+			followers = append(followers, pagination.Cursor{CreatedAt: f.CreatedAt, ID: f.FollowerID})
+		}
+	}
+	return pagination.ApplyIDs(followers, page), nil
+}
+
+func (repo *followRepo) GetFollowings(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	var followings []pagination.Cursor
+	for _, f := range repo.store.followings {
+		if f.FollowerID == userID {
+			followings = append(followings, pagination.Cursor{CreatedAt: f.CreatedAt, ID: f.FollowedID})
+		}
+	}
+	return pagination.ApplyIDs(followings, page), nil
+}
+
+// insertEdge appends edge unless the (follower_id, followed_id) key already
+// exists, mirroring ON CONFLICT DO NOTHING.
+func insertEdge(edges []follow, edge follow) []follow {
+	for _, f := range edges {
+		if f.FollowerID == edge.FollowerID && f.FollowedID == edge.FollowedID {
+			return edges
+		}
+	}
+	return append(edges, edge)
+}
@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type hashtagRepo struct {
+	store *Store
+}
+
+// NewHashtagRepository creates an in-memory HashtagRepository.
+func NewHashtagRepository(store *Store) repositories.HashtagRepository {
+	return &hashtagRepo{store: store}
+}
+
+func (r *hashtagRepo) IndexPost(ctx context.Context, postID uuid.UUID, tags []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if len(tags) > 0 && !r.store.postExists(postID) {
+		return foreignKeyViolation("post_hashtags_post_id_fkey")
+	}
+	r.store.postTags = r.store.index(r.store.postTags, postID, tags)
+	return nil
+}
+
+func (r *hashtagRepo) IndexComment(ctx context.Context, commentID uuid.UUID, tags []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if len(tags) > 0 && !slices.ContainsFunc(r.store.comments, func(c models.PostComment) bool { return c.ID == commentID }) {
+		return foreignKeyViolation("comment_hashtags_comment_id_fkey")
+	}
+	r.store.commentTags = r.store.index(r.store.commentTags, commentID, tags)
+	return nil
+}
+
+// index links ownerID to tags in uses, creating missing hashtags and
+// dropping the links to other tags. It must be called with s.mu held for
+// writing.
+func (s *Store) index(uses []hashtagUse, ownerID uuid.UUID, tags []string) []hashtagUse {
+	wanted := map[uuid.UUID]bool{}
+	for _, tag := range tags {
+		wanted[s.hashtagID(tag)] = true
+	}
+	linked := map[uuid.UUID]bool{}
+	uses = filter(uses, func(u hashtagUse) bool {
+		if u.OwnerID != ownerID {
+			return true
+		}
+		linked[u.HashtagID] = true
+		return wanted[u.HashtagID]
+	})
+
+	now := time.Now()
+	for _, tag := range tags {
+		if id := s.hashtagID(tag); !linked[id] {
+			uses = append(uses, hashtagUse{OwnerID: ownerID, HashtagID: id, CreatedAt: now})
+			linked[id] = true
+		}
+	}
+	return uses
+}
+
+// hashtagID returns the ID of tag, creating the hashtag when it is new. It
+// must be called with s.mu held for writing.
+func (s *Store) hashtagID(tag string) uuid.UUID {
+	for _, h := range s.hashtags {
+		if h.Tag == tag {
+			return h.ID
+		}
+	}
+	h := hashtag{ID: uuid.New(), Tag: tag, CreatedAt: time.Now()}
+	s.hashtags = append(s.hashtags, h)
+	return h.ID
+}
+
+func (r *hashtagRepo) GetPosts(ctx context.Context, viewerID uuid.UUID, tag string, page pagination.Params) (pagination.Page[models.PostWithDetails], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tagged := map[uuid.UUID]bool{}
+	for _, h := range r.store.hashtags {
+		if h.Tag != tag {
+			continue
+		}
+		for _, u := range r.store.postTags {
+			if u.HashtagID == h.ID {
+				tagged[u.OwnerID] = true
+			}
+		}
+	}
+	posts := r.store.postDetails(viewerID, func(p models.ContentPost) bool { return tagged[p.ID] })
+	return pagination.Apply(posts, page, postCursor), nil
+}
+
+func (r *hashtagRepo) Trending(ctx context.Context, since time.Time, limit int) ([]models.HashtagCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	public := map[uuid.UUID]bool{}
+	for _, p := range r.store.posts {
+		if p.Visibility == models.VisibilityPublic {
+			public[p.ID] = true
+		}
+	}
+	for _, c := range r.store.comments {
+		if public[c.PostID] {
+			public[c.ID] = true
+		}
+	}
+	counts := map[uuid.UUID]int{}
+	for _, u := range slices.Concat(r.store.postTags, r.store.commentTags) {
+		if !u.CreatedAt.Before(since) && public[u.OwnerID] {
+			counts[u.HashtagID]++
+		}
+	}
+	trending := []models.HashtagCount{}
+	for _, h := range r.store.hashtags {
+		if n := counts[h.ID]; n > 0 {
+			trending = append(trending, models.HashtagCount{Tag: h.Tag, Count: n})
+		}
+	}
+	sort.Slice(trending, func(i, j int) bool {
+		if trending[i].Count != trending[j].Count {
+			return trending[i].Count > trending[j].Count
+		}
+		return trending[i].Tag < trending[j].Tag
+	})
+	if len(trending) > limit {
+		trending = trending[:limit]
+	}
+	return trending, nil
+}
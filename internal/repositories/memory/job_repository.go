@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type jobRepo struct {
+	store *Store
+}
+
+// NewJobRepository creates an in-memory JobRepository.
+func NewJobRepository(store *Store) repositories.JobRepository {
+	return &jobRepo{store: store}
+}
+
+func (r *jobRepo) CreateJobPost(ctx context.Context, post *models.JobPost) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(post.UserID) {
+		return foreignKeyViolation("job_post_user_id_fkey")
+	}
+
+	post.ID = uuid.New()
+	r.store.jobs = append(r.store.jobs, *post)
+	return nil
+}
+
+func (r *jobRepo) GetAll(ctx context.Context, page pagination.Params) (pagination.Page[models.JobPost], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return pagination.Apply(r.store.jobs, page, func(jp models.JobPost) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: jp.CreatedAt, ID: jp.ID}
+	}), nil
+}
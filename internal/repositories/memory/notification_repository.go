@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type notificationRepo struct {
+	store *Store
+}
+
+// NewNotificationRepository creates an in-memory NotificationRepository.
+func NewNotificationRepository(store *Store) repositories.NotificationRepository {
+	return &notificationRepo{store: store}
+}
+
+func (r *notificationRepo) Create(ctx context.Context, n *models.Notification) error {
+	n.CreatedAt = time.Now()
+
+	if n.SenderUserID == uuid.Nil || n.RecipientUserID == uuid.Nil {
+		return apperrors.Validation("sender_user_id and recipient_user_id cannot be empty")
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(n.RecipientUserID) {
+		return foreignKeyViolation("notifications_recipient_user_id_fkey")
+	}
+	if !r.store.userExists(n.SenderUserID) {
+		return foreignKeyViolation("notifications_sender_user_id_fkey")
+	}
+	for _, existing := range r.store.notifications {
+		if existing.ID == n.ID {
+			return uniqueViolation("notifications_pkey")
+		}
+	}
+
+	r.store.notifications = append(r.store.notifications, *n)
+	return nil
+}
+
+func (r *notificationRepo) GetByUserID(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[models.Notification], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var notifications []models.Notification
+	for _, n := range r.store.notifications {
+		if n.RecipientUserID == userID {
+			notifications = append(notifications, n)
+		}
+	}
+	return pagination.Apply(notifications, page, func(n models.Notification) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: n.CreatedAt, ID: n.ID}
+	}), nil
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type otpRepo struct {
+	store *Store
+}
+
+// NewOTPRepository creates an in-memory OTPRepository.
+func NewOTPRepository(store *Store) repositories.OTPRepository {
+	return &otpRepo{store: store}
+}
+
+func (r *otpRepo) InsertOTP(ctx context.Context, email, otp string) error {
+	return r.SaveOTP(ctx, models.OTP{Email: email, OTP: otp, CreatedAt: time.Now()})
+}
+
+func (r *otpRepo) SaveOTP(ctx context.Context, otp models.OTP) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	otp.ID = uuid.NewString()
+	r.store.otps = append(r.store.otps, otp)
+	return nil
+}
+
+func (r *otpRepo) GetOTPByEmail(ctx context.Context, email string) (*models.OTP, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var latest *models.OTP
+	for i, o := range r.store.otps {
+		if o.Email != email {
+			continue
+		}
+		if latest == nil || !o.CreatedAt.Before(latest.CreatedAt) {
+			latest = &r.store.otps[i]
+		}
+	}
+	if latest == nil {
+		return nil, errNoRows()
+	}
+
+	otp := *latest
+	return &otp, nil
+}
+
+func (r *otpRepo) MarkUserVerified(ctx context.Context, email string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.otps {
+		if r.store.otps[i].Email == email {
+			r.store.otps[i].IsVerified = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+// poll is a row of post_polls with its options in their order. Reported is
+// set once ClaimEnded returned it.
+type poll struct {
+	PostID         uuid.UUID
+	MultipleChoice bool
+	EndsAt         time.Time
+	Reported       bool
+	Options        []models.PollOption
+}
+
+type pollVote struct {
+	PostID   uuid.UUID
+	OptionID uuid.UUID
+	UserID   uuid.UUID
+}
+
+// errAlreadyVoted mirrors the error of the Postgres repository.
+var errAlreadyVoted = errors.New("memory: user already voted in the poll")
+
+type pollRepo struct {
+	store *Store
+}
+
+// NewPollRepository creates an in-memory PollRepository.
+func NewPollRepository(store *Store) repositories.PollRepository {
+	return &pollRepo{store: store}
+}
+
+func (r *pollRepo) GetPoll(ctx context.Context, viewerID, postID uuid.UUID) (*models.Poll, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if p := r.store.pollOf(viewerID, postID); p != nil {
+		return p, nil
+	}
+	return nil, errNoRows()
+}
+
+func (r *pollRepo) Vote(ctx context.Context, postID, userID uuid.UUID, optionIDs []uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(userID) {
+		return foreignKeyViolation("post_poll_votes_user_id_fkey")
+	}
+	i := slices.IndexFunc(r.store.polls, func(p poll) bool { return p.PostID == postID })
+	if i < 0 {
+		return foreignKeyViolation("post_poll_votes_post_id_option_id_fkey")
+	}
+	// Mirrors the primary key of post_poll_voters.
+	if slices.ContainsFunc(r.store.pollVotes, func(v pollVote) bool { return v.PostID == postID && v.UserID == userID }) {
+		return apperrors.Wrap(apperrors.ErrConflict, errAlreadyVoted)
+	}
+	for _, id := range optionIDs {
+		if !slices.ContainsFunc(r.store.polls[i].Options, func(o models.PollOption) bool { return o.ID == id }) {
+			return foreignKeyViolation("post_poll_votes_post_id_option_id_fkey")
+		}
+	}
+	for _, id := range optionIDs {
+		r.store.pollVotes = append(r.store.pollVotes, pollVote{PostID: postID, OptionID: id, UserID: userID})
+	}
+	return nil
+}
+
+// ClaimEnded has no locks to skip: the memory TxManager already runs one
+// transaction at a time.
+func (r *pollRepo) ClaimEnded(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var ended []int
+	for i, p := range r.store.polls {
+		if !p.Reported && !p.EndsAt.After(now) {
+			ended = append(ended, i)
+		}
+	}
+	sort.SliceStable(ended, func(a, b int) bool {
+		return r.store.polls[ended[a]].EndsAt.Before(r.store.polls[ended[b]].EndsAt)
+	})
+	if len(ended) > limit {
+		ended = ended[:limit]
+	}
+
+	ids := make([]uuid.UUID, 0, len(ended))
+	for _, i := range ended {
+		r.store.polls[i].Reported = true
+		ids = append(ids, r.store.polls[i].PostID)
+	}
+	return ids, nil
+}
+
+// insertPoll stores the poll of a new post with its options in their order.
+// It must be called with s.mu held for writing.
+func (s *Store) insertPoll(postID uuid.UUID, p *models.Poll) {
+	stored := poll{PostID: postID, MultipleChoice: p.MultipleChoice, EndsAt: p.EndsAt}
+	for i, o := range p.Options {
+		o.ID, o.PostID, o.Position, o.Votes = uuid.New(), postID, i, 0
+		stored.Options = append(stored.Options, o)
+	}
+	s.polls = append(s.polls, stored)
+}
+
+// pollOf returns the poll of a post with the vote counts and the choices of
+// viewerID, or nil when the post has none. It must be called with s.mu held.
+func (s *Store) pollOf(viewerID, postID uuid.UUID) *models.Poll {
+	i := slices.IndexFunc(s.polls, func(p poll) bool { return p.PostID == postID })
+	if i < 0 {
+		return nil
+	}
+	stored := s.polls[i]
+	p := &models.Poll{
+		PostID:         postID,
+		MultipleChoice: stored.MultipleChoice,
+		EndsAt:         stored.EndsAt,
+		Options:        make([]models.PollOption, 0, len(stored.Options)),
+		Choices:        []uuid.UUID{},
+	}
+	voters := map[uuid.UUID]bool{}
+	for _, o := range stored.Options {
+		o.Votes = 0
+		for _, v := range s.pollVotes {
+			if v.OptionID != o.ID {
+				continue
+			}
+			o.Votes++
+			voters[v.UserID] = true
+			if v.UserID == viewerID {
+				p.Choices = append(p.Choices, o.ID)
+			}
+		}
+		p.Options = append(p.Options, o)
+	}
+	p.TotalVoters = len(voters)
+	return p
+}
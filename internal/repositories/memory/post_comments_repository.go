@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type postCommentRepo struct {
+	store *Store
+}
+
+// NewPostCommentRepository creates an in-memory PostCommentRepository.
+func NewPostCommentRepository(store *Store) repositories.PostCommentRepository {
+	return &postCommentRepo{store: store}
+}
+
+func (repo *postCommentRepo) CreateComment(ctx context.Context, comment *models.PostComment) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if !repo.store.userExists(comment.UserID) {
+		return foreignKeyViolation("post_comments_user_id_fkey")
+	}
+	if !repo.store.postExists(comment.PostID) {
+		return foreignKeyViolation("post_comments_post_id_fkey")
+	}
+
+	comment.ID = uuid.New()
+	comment.CreatedAt = time.Now()
+	comment.Entities = entitiesOrEmpty(comment.Entities)
+	repo.store.comments = append(repo.store.comments, *comment)
+	return nil
+}
+
+func (repo *postCommentRepo) GetComments(ctx context.Context, postID uuid.UUID, page pagination.Params) (pagination.Page[models.PostComment], error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	var comments []models.PostComment
+	for _, c := range repo.store.comments {
+		if c.PostID == postID {
+			comments = append(comments, c)
+		}
+	}
+	return pagination.Apply(comments, page, func(c models.PostComment) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	}), nil
+}
+
+func (repo *postCommentRepo) PostExists(ctx context.Context, postID uuid.UUID) (bool, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	return repo.store.postExists(postID), nil
+}
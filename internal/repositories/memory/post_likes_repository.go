@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type postLikeRepo struct {
+	store *Store
+}
+
+// NewPostLikeRepository creates an in-memory PostLikeRepository.
+func NewPostLikeRepository(store *Store) repositories.PostLikeRepository {
+	return &postLikeRepo{store: store}
+}
+
+func (repo *postLikeRepo) CreateLike(ctx context.Context, userID, postID uuid.UUID) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if !repo.store.userExists(userID) {
+		return foreignKeyViolation("post_likes_user_id_fkey")
+	}
+	if !repo.store.postExists(postID) {
+		return foreignKeyViolation("post_likes_post_id_fkey")
+	}
+
+	// ON CONFLICT (user_id, post_id) DO NOTHING
+	for _, l := range repo.store.likes {
+		if l.UserID == userID && l.PostID == postID {
+			return nil
+		}
+	}
+
+	repo.store.likes = append(repo.store.likes, models.PostLike{
+		ID:        uuid.New(),
+		UserID:    userID,
+		PostID:    postID,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (repo *postLikeRepo) RemoveLike(ctx context.Context, userID, postID uuid.UUID) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	repo.store.likes = filter(repo.store.likes, func(l models.PostLike) bool {
+		return l.UserID != userID || l.PostID != postID
+	})
+	return nil
+}
+
+func (repo *postLikeRepo) GetLikes(ctx context.Context, postID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	var likes []pagination.Cursor
+	for _, l := range repo.store.likes {
+		if l.PostID == postID {
+			likes = append(likes, pagination.Cursor{CreatedAt: l.CreatedAt, ID: l.UserID})
+		}
+	}
+	return pagination.ApplyIDs(likes, page), nil
+}
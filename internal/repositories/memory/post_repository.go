@@ -0,0 +1,412 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type postRepo struct {
+	store *Store
+}
+
+// NewPostRepository creates an in-memory PostRepository.
+func NewPostRepository(store *Store) repositories.PostRepository {
+	return &postRepo{store: store}
+}
+
+func (r *postRepo) CreatePost(ctx context.Context, post *models.ContentPost) (*models.ContentPost, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(post.UserID) {
+		return nil, foreignKeyViolation("content_post_user_id_fkey")
+	}
+	if post.SharedPostID != nil {
+		if !r.store.postExists(*post.SharedPostID) {
+			return nil, foreignKeyViolation("content_post_shared_post_id_fkey")
+		}
+		for _, p := range r.store.posts {
+			if post.ShareType == models.ShareRepost && p.ShareType == models.ShareRepost &&
+				p.UserID == post.UserID && p.SharedPostID != nil && *p.SharedPostID == *post.SharedPostID {
+				return nil, uniqueViolation("idx_content_post_one_repost")
+			}
+		}
+	}
+
+	created := models.ContentPost{
+		ID:           uuid.New(),
+		UserID:       post.UserID,
+		PostContent:  post.PostContent,
+		MediaURL:     post.MediaURL,
+		SharedPostID: post.SharedPostID,
+		ShareType:    post.ShareType,
+		Visibility:   post.Visibility,
+		Entities:     entitiesOrEmpty(post.Entities),
+		PostType:     models.PostTypeText,
+		CreatedAt:    time.Now(),
+	}
+	if post.Poll != nil {
+		created.PostType = models.PostTypePoll
+	}
+	if created.Visibility == "" {
+		created.Visibility = models.VisibilityPublic
+	}
+	r.store.posts = append(r.store.posts, created)
+	for i, a := range post.Attachments {
+		a.ID, a.PostID, a.Position = uuid.New(), created.ID, i
+		r.store.attachments = append(r.store.attachments, a)
+	}
+	created.Attachments = r.store.attachmentsOf(created.ID)
+	if post.Poll != nil {
+		r.store.insertPoll(created.ID, post.Poll)
+		created.Poll = r.store.pollOf(uuid.Nil, created.ID)
+	}
+	return &created, nil
+}
+
+func (r *postRepo) GetAllWithDetails(ctx context.Context, viewerID uuid.UUID, page pagination.Params) (pagination.Page[models.PostWithDetails], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	posts := r.store.postDetails(viewerID, func(models.ContentPost) bool { return true })
+	return pagination.Apply(posts, page, postCursor), nil
+}
+
+func (r *postRepo) GetPopular(ctx context.Context, viewerID uuid.UUID, since time.Time, limit int) ([]models.PostWithDetails, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	posts := r.store.postDetails(viewerID, func(p models.ContentPost) bool {
+		return p.Visibility == models.VisibilityPublic && !p.CreatedAt.Before(since)
+	})
+	sort.SliceStable(posts, func(i, j int) bool {
+		a, b := posts[i], posts[j]
+		if sa, sb := a.TotalLikes+a.TotalComments, b.TotalLikes+b.TotalComments; sa != sb {
+			return sa > sb
+		}
+		return pagination.Compare(postCursor(a), postCursor(b)) < 0
+	})
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+	return posts, nil
+}
+
+func postCursor(p models.PostWithDetails) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: p.CreatedAt, ID: p.PostID}
+}
+
+// postDetails renders the posts viewerID can see for which keep reports
+// true the way the feed query does, with the posts they share. It must be
+// called with s.mu held.
+func (s *Store) postDetails(viewerID uuid.UUID, keep func(models.ContentPost) bool) []models.PostWithDetails {
+	var posts []models.PostWithDetails
+	for _, p := range s.posts {
+		if !keep(p) || !s.canView(viewerID, p) {
+			continue
+		}
+		d, ok := s.postDetail(viewerID, p)
+		if !ok {
+			continue
+		}
+		if p.SharedPostID != nil {
+			for _, shared := range s.posts {
+				if shared.ID != *p.SharedPostID || !s.canView(viewerID, shared) {
+					continue
+				}
+				if sd, ok := s.postDetail(viewerID, shared); ok {
+					d.SharedPost = &sd
+				}
+			}
+		}
+		posts = append(posts, d)
+	}
+	return posts
+}
+
+// postDetail renders one post without the post it shares, with its poll
+// read for viewerID. Posts of users without a profile are skipped; users
+// with several profiles are shown with the latest one. It must be called
+// with s.mu held.
+func (s *Store) postDetail(viewerID uuid.UUID, p models.ContentPost) (models.PostWithDetails, bool) {
+	up, ok := s.latestProfile(p.UserID)
+	if !ok {
+		return models.PostWithDetails{}, false
+	}
+	mediaURL := p.MediaURL
+	return models.PostWithDetails{
+		PostID:        p.ID,
+		UserID:        p.UserID,
+		ProfileImage:  deref(up.ProfileImage),
+		FullName:      up.FullName,
+		Designation:   deref(up.Designation),
+		PostContent:   p.PostContent,
+		MediaURL:      &mediaURL,
+		TotalLikes:    s.countLikes(p.ID),
+		TotalComments: s.countComments(p.ID),
+		TotalShares:   s.countShares(p.ID),
+		CreatedAt:     p.CreatedAt,
+		EditedAt:      p.EditedAt,
+		Visibility:    p.Visibility,
+		PostType:      p.PostType,
+		ShareType:     p.ShareType,
+		SharedPostID:  p.SharedPostID,
+		Attachments:   s.attachmentsOf(p.ID),
+		Entities:      p.Entities,
+		Poll:          s.pollOf(viewerID, p.ID),
+	}, true
+}
+
+func (s *Store) latestProfile(userID uuid.UUID) (models.UserProfile, bool) {
+	var (
+		latest models.UserProfile
+		found  bool
+	)
+	for _, up := range s.profiles {
+		if up.UserID != userID {
+			continue
+		}
+		if !found || pagination.Compare(
+			pagination.Cursor{CreatedAt: up.CreatedAt, ID: up.ID},
+			pagination.Cursor{CreatedAt: latest.CreatedAt, ID: latest.ID},
+		) < 0 {
+			latest, found = up, true
+		}
+	}
+	return latest, found
+}
+
+// canView reports whether viewerID can see p, like the visibleTo condition
+// of the SQL queries. It must be called with s.mu held.
+func (s *Store) canView(viewerID uuid.UUID, p models.ContentPost) bool {
+	switch p.Visibility {
+	case models.VisibilityPublic:
+		return true
+	case models.VisibilityFollowers:
+		if slices.ContainsFunc(s.followers, func(f follow) bool {
+			return f.FollowedID == p.UserID && f.FollowerID == viewerID
+		}) {
+			return true
+		}
+	}
+	return p.UserID == viewerID
+}
+
+func (r *postRepo) GetPostsByUserID(ctx context.Context, viewerID, userID uuid.UUID, page pagination.Params) (pagination.Page[models.ContentPost], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var author *models.User
+	for _, u := range r.store.users {
+		if u.ID == userID.String() {
+			author = &models.User{ID: u.ID, Username: u.Username, Email: u.Email}
+			break
+		}
+	}
+	if author == nil {
+		return pagination.Page[models.ContentPost]{}, nil
+	}
+
+	var posts []models.ContentPost
+	for _, p := range r.store.posts {
+		if p.UserID == userID && r.store.canView(viewerID, p) {
+			posts = append(posts, p)
+		}
+	}
+	result := pagination.Apply(posts, page, contentPostCursor)
+	for i := range result.Items {
+		p := &result.Items[i]
+		user := *author
+		p.User = &user
+		p.Attachments = r.store.attachmentsOf(p.ID)
+		p.Poll = r.store.pollOf(viewerID, p.ID)
+	}
+	return result, nil
+}
+
+func contentPostCursor(p models.ContentPost) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+}
+
+func (r *postRepo) GetPostByID(ctx context.Context, viewerID, postID uuid.UUID) (*models.ContentPost, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, p := range r.store.posts {
+		if p.ID == postID {
+			p.Attachments = r.store.attachmentsOf(p.ID)
+			p.Poll = r.store.pollOf(viewerID, p.ID)
+			return &p, nil
+		}
+	}
+	return nil, errNoRows()
+}
+
+func (r *postRepo) CanView(ctx context.Context, viewerID, postID uuid.UUID) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, p := range r.store.posts {
+		if p.ID == postID {
+			return r.store.canView(viewerID, p), nil
+		}
+	}
+	return false, nil
+}
+
+func (r *postRepo) SetVisibility(ctx context.Context, viewerID, postID uuid.UUID, visibility string) (*models.ContentPost, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, p := range r.store.posts {
+		if p.ID == postID {
+			p.Visibility = visibility
+			r.store.posts[i] = p
+			p.Attachments = r.store.attachmentsOf(p.ID)
+			p.Poll = r.store.pollOf(viewerID, p.ID)
+			return &p, nil
+		}
+	}
+	return nil, errNoRows()
+}
+
+func (r *postRepo) UpdatePost(ctx context.Context, viewerID, postID uuid.UUID, content string, entities []models.Entity) (*models.ContentPost, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, p := range r.store.posts {
+		if p.ID != postID {
+			continue
+		}
+		now := time.Now()
+		writtenAt := p.CreatedAt
+		if p.EditedAt != nil {
+			writtenAt = *p.EditedAt
+		}
+		r.store.revisions = append(r.store.revisions, models.PostRevision{
+			ID:          uuid.New(),
+			PostID:      postID,
+			PostContent: p.PostContent,
+			CreatedAt:   writtenAt,
+			ReplacedAt:  now,
+		})
+		p.PostContent = content
+		p.Entities = entitiesOrEmpty(entities)
+		p.EditedAt = &now
+		r.store.posts[i] = p
+		p.Attachments = r.store.attachmentsOf(p.ID)
+		p.Poll = r.store.pollOf(viewerID, p.ID)
+		return &p, nil
+	}
+	return nil, errNoRows()
+}
+
+func (r *postRepo) DeletePost(ctx context.Context, postID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, p := range r.store.posts {
+		if p.ID == postID {
+			r.store.deletePost(postID)
+			return nil
+		}
+	}
+	return errNoRows()
+}
+
+func (r *postRepo) DeleteRepost(ctx context.Context, userID, postID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, p := range r.store.posts {
+		if p.UserID == userID && p.ShareType == models.ShareRepost && p.SharedPostID != nil && *p.SharedPostID == postID {
+			r.store.deletePost(p.ID)
+			return nil
+		}
+	}
+	return errNoRows()
+}
+
+func (r *postRepo) GetRevisions(ctx context.Context, postID uuid.UUID, page pagination.Params) (pagination.Page[models.PostRevision], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	revisions := filter(r.store.revisions, func(rev models.PostRevision) bool { return rev.PostID == postID })
+	return pagination.Apply(revisions, page, func(rev models.PostRevision) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: rev.ReplacedAt, ID: rev.ID}
+	}), nil
+}
+
+// entitiesOrEmpty mirrors the entities column defaulting to an empty array.
+func entitiesOrEmpty(entities []models.Entity) []models.Entity {
+	if entities == nil {
+		return []models.Entity{}
+	}
+	return entities
+}
+
+// attachmentsOf returns the attachments of a post in their order. It must be
+// called with s.mu held.
+func (s *Store) attachmentsOf(postID uuid.UUID) []models.PostAttachment {
+	attachments := []models.PostAttachment{}
+	for _, a := range s.attachments {
+		if a.PostID == postID {
+			attachments = append(attachments, a)
+		}
+	}
+	sort.SliceStable(attachments, func(i, j int) bool { return attachments[i].Position < attachments[j].Position })
+	return attachments
+}
+
+func (s *Store) countLikes(postID uuid.UUID) int {
+	n := 0
+	for _, l := range s.likes {
+		if l.PostID == postID {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Store) countShares(postID uuid.UUID) int {
+	n := 0
+	for _, p := range s.posts {
+		if p.SharedPostID != nil && *p.SharedPostID == postID {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Store) countComments(postID uuid.UUID) int {
+	n := 0
+	for _, c := range s.comments {
+		if c.PostID == postID {
+			n++
+		}
+	}
+	return n
+}
+
+// newestFirst returns a copy of posts ordered by created_at descending.
+func newestFirst(posts []models.ContentPost) []models.ContentPost {
+	sorted := append([]models.ContentPost(nil), posts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+	return sorted
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
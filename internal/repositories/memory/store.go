@@ -0,0 +1,275 @@
+// Package memory provides in-memory implementations of the repository
+// interfaces. They enforce the same unique and foreign key constraints as the
+// Postgres schema and cascade deletes like its ON DELETE CASCADE clauses, so
+// services and handlers behave the same against them in tests.
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ratelimit"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+var (
+	// ErrUniqueViolation mirrors Postgres error 23505.
+	ErrUniqueViolation = errors.New("memory: duplicate key value violates unique constraint")
+	// ErrForeignKeyViolation mirrors Postgres error 23503.
+	ErrForeignKeyViolation = errors.New("memory: insert or update violates foreign key constraint")
+)
+
+type follow struct {
+	FollowerID uuid.UUID
+	FollowedID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type hashtag struct {
+	ID        uuid.UUID
+	Tag       string
+	CreatedAt time.Time
+}
+
+// hashtagUse is a row of post_hashtags or comment_hashtags; OwnerID is the
+// post or comment.
+type hashtagUse struct {
+	OwnerID   uuid.UUID
+	HashtagID uuid.UUID
+	CreatedAt time.Time
+}
+
+type timelineEntry struct {
+	UserID   uuid.UUID
+	PostID   uuid.UUID
+	AuthorID uuid.UUID
+}
+
+// tables holds the rows of every table. Slices keep insertion order, which
+// gives deterministic results where the SQL queries have no ORDER BY.
+type tables struct {
+	users         []models.User
+	otps          []models.OTP
+	posts         []models.ContentPost
+	revisions     []models.PostRevision
+	drafts        []models.PostDraft
+	attachments   []models.PostAttachment
+	polls         []poll
+	pollVotes     []pollVote
+	likes         []models.PostLike
+	comments      []models.PostComment
+	hashtags      []hashtag
+	postTags      []hashtagUse
+	commentTags   []hashtagUse
+	jobs          []models.JobPost
+	profiles      []models.UserProfile
+	videos        []models.VideoProfile
+	educations    []models.UserEducation
+	experiences   []models.UserExperience
+	followers     []follow
+	followings    []follow
+	notifications []models.Notification
+	timeline      []timelineEntry
+}
+
+// clone copies every table so a snapshot is unaffected by later writes.
+func (t *tables) clone() tables {
+	return tables{
+		users:         slices.Clone(t.users),
+		otps:          slices.Clone(t.otps),
+		posts:         slices.Clone(t.posts),
+		revisions:     slices.Clone(t.revisions),
+		drafts:        slices.Clone(t.drafts),
+		attachments:   slices.Clone(t.attachments),
+		polls:         slices.Clone(t.polls),
+		pollVotes:     slices.Clone(t.pollVotes),
+		likes:         slices.Clone(t.likes),
+		comments:      slices.Clone(t.comments),
+		hashtags:      slices.Clone(t.hashtags),
+		postTags:      slices.Clone(t.postTags),
+		commentTags:   slices.Clone(t.commentTags),
+		jobs:          slices.Clone(t.jobs),
+		profiles:      slices.Clone(t.profiles),
+		videos:        slices.Clone(t.videos),
+		educations:    slices.Clone(t.educations),
+		experiences:   slices.Clone(t.experiences),
+		followers:     slices.Clone(t.followers),
+		followings:    slices.Clone(t.followings),
+		notifications: slices.Clone(t.notifications),
+		timeline:      slices.Clone(t.timeline),
+	}
+}
+
+// Store is the in-memory database shared by the repositories.
+type Store struct {
+	mu sync.RWMutex
+	// txMu serialises transactions; see txManager.
+	txMu sync.Mutex
+	tables
+}
+
+// NewStore creates an empty store.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// NewRepositories creates every repository on top of a fresh store.
+func NewRepositories() repositories.Repositories {
+	return NewStore().Repositories()
+}
+
+// Repositories returns every repository backed by this store.
+func (s *Store) Repositories() repositories.Repositories {
+	return repositories.Repositories{
+		Users:          NewUserRepository(s),
+		OTPs:           NewOTPRepository(s),
+		Posts:          NewPostRepository(s),
+		Jobs:           NewJobRepository(s),
+		UserProfiles:   NewUserProfileRepository(s),
+		VideoProfiles:  NewVideoProfileRepository(s),
+		UserEducation:  NewUserEducationRepository(s),
+		UserExperience: NewUserExperienceRepository(s),
+		PostLikes:      NewPostLikeRepository(s),
+		PostComments:   NewPostCommentRepository(s),
+		Follows:        NewFollowRepository(s),
+		Notifications:  NewNotificationRepository(s),
+		Timelines:      NewTimelineRepository(s),
+		Hashtags:       NewHashtagRepository(s),
+		Drafts:         NewDraftRepository(s),
+		Polls:          NewPollRepository(s),
+		RateLimits:     ratelimit.NewMemoryStore(),
+		Idempotency:    idempotency.NewMemoryStore(),
+		Tx:             NewTxManager(s),
+	}
+}
+
+// The helpers below classify errors the way the Postgres repositories do.
+
+func uniqueViolation(constraint string) error {
+	return apperrors.Wrap(apperrors.ErrConflict, fmt.Errorf("%w %q", ErrUniqueViolation, constraint))
+}
+
+func foreignKeyViolation(constraint string) error {
+	return apperrors.Wrap(apperrors.ErrNotFound, fmt.Errorf("%w %q", ErrForeignKeyViolation, constraint))
+}
+
+func errNoRows() error {
+	return apperrors.Wrap(apperrors.ErrNotFound, sql.ErrNoRows)
+}
+
+// parseUUID mirrors Postgres rejecting malformed text compared to a UUID column.
+func parseUUID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, apperrors.Wrap(apperrors.ErrValidation, fmt.Errorf("memory: invalid input syntax for type uuid: %q", s))
+	}
+	return id, nil
+}
+
+// userExists must be called with s.mu held.
+func (s *Store) userExists(id uuid.UUID) bool {
+	for _, u := range s.users {
+		if u.ID == id.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// postExists must be called with s.mu held.
+func (s *Store) postExists(id uuid.UUID) bool {
+	for _, p := range s.posts {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// deletePost removes a post and cascades to its likes, comments, timeline
+// entries, revisions, attachments, poll, hashtags and reposts; quotes of it lose
+// their reference. It must be called with s.mu held for writing.
+func (s *Store) deletePost(id uuid.UUID) {
+	for _, p := range s.posts {
+		if p.ShareType == models.ShareRepost && p.SharedPostID != nil && *p.SharedPostID == id {
+			s.deletePost(p.ID)
+		}
+	}
+	s.posts = filter(s.posts, func(p models.ContentPost) bool { return p.ID != id })
+	for i, p := range s.posts {
+		if p.SharedPostID != nil && *p.SharedPostID == id {
+			s.posts[i].SharedPostID = nil
+		}
+	}
+	s.likes = filter(s.likes, func(l models.PostLike) bool { return l.PostID != id })
+	s.deleteComments(func(c models.PostComment) bool { return c.PostID == id })
+	s.postTags = filter(s.postTags, func(u hashtagUse) bool { return u.OwnerID != id })
+	s.timeline = filter(s.timeline, func(e timelineEntry) bool { return e.PostID != id })
+	s.revisions = filter(s.revisions, func(r models.PostRevision) bool { return r.PostID != id })
+	s.attachments = filter(s.attachments, func(a models.PostAttachment) bool { return a.PostID != id })
+	s.polls = filter(s.polls, func(p poll) bool { return p.PostID != id })
+	s.pollVotes = filter(s.pollVotes, func(v pollVote) bool { return v.PostID != id })
+}
+
+// deleteUser removes a user and cascades to every row referencing it. It must
+// be called with s.mu held for writing.
+func (s *Store) deleteUser(id uuid.UUID) {
+	s.users = filter(s.users, func(u models.User) bool { return u.ID != id.String() })
+
+	for _, p := range s.posts {
+		if p.UserID == id {
+			s.deletePost(p.ID)
+		}
+	}
+	s.likes = filter(s.likes, func(l models.PostLike) bool { return l.UserID != id })
+	s.deleteComments(func(c models.PostComment) bool { return c.UserID == id })
+	s.pollVotes = filter(s.pollVotes, func(v pollVote) bool { return v.UserID != id })
+	s.drafts = filter(s.drafts, func(d models.PostDraft) bool { return d.UserID != id })
+	s.jobs = filter(s.jobs, func(j models.JobPost) bool { return j.UserID != id })
+	s.profiles = filter(s.profiles, func(p models.UserProfile) bool { return p.UserID != id })
+	s.videos = filter(s.videos, func(v models.VideoProfile) bool { return v.UserID != id })
+	s.educations = filter(s.educations, func(e models.UserEducation) bool { return e.UserID != id })
+	s.experiences = filter(s.experiences, func(e models.UserExperience) bool { return e.UserID != id })
+
+	notInvolved := func(f follow) bool { return f.FollowerID != id && f.FollowedID != id }
+	s.followers = filter(s.followers, notInvolved)
+	s.followings = filter(s.followings, notInvolved)
+
+	s.notifications = filter(s.notifications, func(n models.Notification) bool {
+		return n.RecipientUserID != id && n.SenderUserID != id
+	})
+	s.timeline = filter(s.timeline, func(e timelineEntry) bool { return e.UserID != id && e.AuthorID != id })
+}
+
+// deleteComments removes the comments for which drop reports true and
+// cascades to their hashtags. It must be called with s.mu held for writing.
+func (s *Store) deleteComments(drop func(models.PostComment) bool) {
+	dropped := map[uuid.UUID]bool{}
+	s.comments = filter(s.comments, func(c models.PostComment) bool {
+		if drop(c) {
+			dropped[c.ID] = true
+			return false
+		}
+		return true
+	})
+	s.commentTags = filter(s.commentTags, func(u hashtagUse) bool { return !dropped[u.OwnerID] })
+}
+
+// filter returns the elements of rows for which keep reports true.
+func filter[T any](rows []T, keep func(T) bool) []T {
+	kept := rows[:0:0]
+	for _, row := range rows {
+		if keep(row) {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/repotest"
+)
+
+func TestMemoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repositories.Repositories {
+		return memory.NewRepositories()
+	})
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type timelineRepo struct {
+	store *Store
+}
+
+// NewTimelineRepository creates an in-memory TimelineRepository.
+func NewTimelineRepository(store *Store) repositories.TimelineRepository {
+	return &timelineRepo{store: store}
+}
+
+func (r *timelineRepo) FanOut(ctx context.Context, post *models.ContentPost, maxFollowers int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.followerCount(post.UserID) > maxFollowers {
+		return nil
+	}
+	for _, f := range r.store.followers {
+		if f.FollowedID == post.UserID {
+			r.store.addToTimeline(timelineEntry{UserID: f.FollowerID, PostID: post.ID, AuthorID: post.UserID})
+		}
+	}
+	return nil
+}
+
+func (r *timelineRepo) Backfill(ctx context.Context, userID, authorID uuid.UUID, maxFollowers, limit int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.followerCount(authorID) > maxFollowers {
+		return nil
+	}
+	for _, p := range newestFirst(r.store.posts) {
+		if limit == 0 {
+			break
+		}
+		if p.UserID == authorID {
+			r.store.addToTimeline(timelineEntry{UserID: userID, PostID: p.ID, AuthorID: authorID})
+			limit--
+		}
+	}
+	return nil
+}
+
+func (r *timelineRepo) Remove(ctx context.Context, userID, authorID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.timeline = filter(r.store.timeline, func(e timelineEntry) bool {
+		return e.UserID != userID || e.AuthorID != authorID
+	})
+	return nil
+}
+
+func (r *timelineRepo) Home(ctx context.Context, userID uuid.UUID, maxFollowers int, page pagination.Params) (pagination.Page[models.PostWithDetails], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	fannedOut := map[uuid.UUID]bool{}
+	for _, e := range r.store.timeline {
+		if e.UserID == userID {
+			fannedOut[e.PostID] = true
+		}
+	}
+	pulled := map[uuid.UUID]bool{}
+	for _, f := range r.store.followings {
+		if f.FollowerID == userID && r.store.followerCount(f.FollowedID) > maxFollowers {
+			pulled[f.FollowedID] = true
+		}
+	}
+
+	posts := r.store.postDetails(userID, func(p models.ContentPost) bool {
+		return p.UserID == userID || fannedOut[p.ID] || pulled[p.UserID]
+	})
+	return pagination.Apply(posts, page, postCursor), nil
+}
+
+func (r *timelineRepo) Signals(ctx context.Context, viewerID uuid.UUID, postIDs []uuid.UUID, since time.Time) (map[uuid.UUID]models.PostSignals, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	authors := map[uuid.UUID]uuid.UUID{}
+	for _, p := range r.store.posts {
+		authors[p.ID] = p.UserID
+	}
+	affinity := map[uuid.UUID]int{}
+	for _, l := range r.store.likes {
+		if l.UserID == viewerID {
+			affinity[authors[l.PostID]]++
+		}
+	}
+	for _, c := range r.store.comments {
+		if c.UserID == viewerID {
+			affinity[authors[c.PostID]]++
+		}
+	}
+
+	signals := make(map[uuid.UUID]models.PostSignals, len(postIDs))
+	for _, id := range postIDs {
+		author, ok := authors[id]
+		if !ok {
+			continue
+		}
+		s := models.PostSignals{PostID: id, Affinity: affinity[author]}
+		for _, l := range r.store.likes {
+			if l.PostID == id && !l.CreatedAt.Before(since) {
+				s.RecentLikes++
+			}
+		}
+		for _, c := range r.store.comments {
+			if c.PostID == id && !c.CreatedAt.Before(since) {
+				s.RecentComments++
+			}
+		}
+		signals[id] = s
+	}
+	return signals, nil
+}
+
+// followerCount must be called with s.mu held.
+func (s *Store) followerCount(userID uuid.UUID) int {
+	n := 0
+	for _, f := range s.followers {
+		if f.FollowedID == userID {
+			n++
+		}
+	}
+	return n
+}
+
+// addToTimeline inserts e unless it is already there, like the ON CONFLICT
+// clause of the Postgres queries. It must be called with s.mu held for
+// writing.
+func (s *Store) addToTimeline(e timelineEntry) {
+	for _, existing := range s.timeline {
+		if existing.UserID == e.UserID && existing.PostID == e.PostID {
+			return
+		}
+	}
+	s.timeline = append(s.timeline, e)
+}
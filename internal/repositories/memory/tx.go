@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type txKey struct{}
+
+type txManager struct {
+	store *Store
+}
+
+// NewTxManager creates a TxManager over the store. Transactions are
+// serialised against each other and roll back by restoring a snapshot taken
+// when they began. Writes outside a transaction are not isolated from it,
+// which is good enough for tests.
+func NewTxManager(store *Store) repositories.TxManager {
+	return &txManager{store: store}
+}
+
+func (m *txManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	m.store.txMu.Lock()
+	defer m.store.txMu.Unlock()
+
+	m.store.mu.RLock()
+	snapshot := m.store.tables.clone()
+	m.store.mu.RUnlock()
+
+	rollback := func() {
+		m.store.mu.Lock()
+		m.store.tables = snapshot
+		m.store.mu.Unlock()
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type userEducationRepo struct {
+	store *Store
+}
+
+// NewUserEducationRepository creates an in-memory UserEducationRepository.
+func NewUserEducationRepository(store *Store) repositories.UserEducationRepository {
+	return &userEducationRepo{store: store}
+}
+
+func (r *userEducationRepo) Create(ctx context.Context, edu *models.UserEducation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(edu.UserID) {
+		return foreignKeyViolation("user_education_user_id_fkey")
+	}
+
+	now := time.Now()
+	edu.ID = uuid.New()
+	edu.CreatedAt = now
+	edu.UpdatedAt = now
+	r.store.educations = append(r.store.educations, *edu)
+	return nil
+}
+
+func (r *userEducationRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserEducation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var educations []*models.UserEducation
+	for _, e := range r.store.educations {
+		if e.UserID == userID {
+			edu := e
+			educations = append(educations, &edu)
+		}
+	}
+	return educations, nil
+}
+
+func (r *userEducationRepo) Update(ctx context.Context, edu *models.UserEducation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.educations {
+		e := &r.store.educations[i]
+		if e.ID == edu.ID {
+			e.Degree = edu.Degree
+			e.InstitutionName = edu.InstitutionName
+			e.FieldOfStudy = edu.FieldOfStudy
+			e.Grade = edu.Grade
+			e.Year = edu.Year
+			e.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *userEducationRepo) Delete(ctx context.Context, eduID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.educations = filter(r.store.educations, func(e models.UserEducation) bool { return e.ID != eduID })
+	return nil
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type userExperienceRepo struct {
+	store *Store
+}
+
+// NewUserExperienceRepository creates an in-memory UserExperienceRepository.
+func NewUserExperienceRepository(store *Store) repositories.UserExperienceRepository {
+	return &userExperienceRepo{store: store}
+}
+
+func (r *userExperienceRepo) Create(ctx context.Context, exp *models.UserExperience) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(exp.UserID) {
+		return foreignKeyViolation("user_experience_user_id_fkey")
+	}
+
+	now := time.Now()
+	exp.ID = uuid.New()
+	exp.CreatedAt = now
+	exp.UpdatedAt = now
+	r.store.experiences = append(r.store.experiences, *exp)
+	return nil
+}
+
+func (r *userExperienceRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.UserExperience, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var experiences []models.UserExperience
+	for _, e := range r.store.experiences {
+		if e.UserID == userID {
+			experiences = append(experiences, e)
+		}
+	}
+	return experiences, nil
+}
+
+func (r *userExperienceRepo) Update(ctx context.Context, exp *models.UserExperience) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.experiences {
+		e := &r.store.experiences[i]
+		if e.ID == exp.ID {
+			e.JobTitle = exp.JobTitle
+			e.CompanyName = exp.CompanyName
+			e.Location = exp.Location
+			e.JobDescription = exp.JobDescription
+			e.Achievements = exp.Achievements
+			e.StartDate = exp.StartDate
+			e.EndDate = exp.EndDate
+			e.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *userExperienceRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.experiences = filter(r.store.experiences, func(e models.UserExperience) bool { return e.ID != id })
+	return nil
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type userProfileRepo struct {
+	store *Store
+}
+
+// NewUserProfileRepository creates an in-memory UserProfileRepository.
+func NewUserProfileRepository(store *Store) repositories.UserProfileRepository {
+	return &userProfileRepo{store: store}
+}
+
+func (r *userProfileRepo) Create(ctx context.Context, profile *models.UserProfile) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(profile.UserID) {
+		return foreignKeyViolation("user_profile_user_id_fkey")
+	}
+	if r.emailTaken(profile.Email, uuid.Nil) {
+		return uniqueViolation("user_profile_email_key")
+	}
+
+	stored := *profile
+	if stored.DefaultVisibility == "" {
+		stored.DefaultVisibility = models.VisibilityPublic
+	}
+	r.store.profiles = append(r.store.profiles, stored)
+	return nil
+}
+
+func (r *userProfileRepo) GetByUserID(ctx context.Context, userID string) (*models.UserProfile, error) {
+	uid, err := parseUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, p := range r.store.profiles {
+		if p.UserID == uid {
+			return &p, nil
+		}
+	}
+	return nil, errNoRows()
+}
+
+func (r *userProfileRepo) GetAll(ctx context.Context, page pagination.Params) (pagination.Page[*models.UserProfile], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var profiles []*models.UserProfile
+	for _, p := range r.store.profiles {
+		profile := p
+		profiles = append(profiles, &profile)
+	}
+	return pagination.Apply(profiles, page, func(p *models.UserProfile) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+	}), nil
+}
+
+func (r *userProfileRepo) Update(ctx context.Context, userID string, updated *models.UserProfile) (*models.UserProfile, error) {
+	uid, err := parseUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.emailTaken(updated.Email, uid) {
+		return nil, uniqueViolation("user_profile_email_key")
+	}
+
+	var result *models.UserProfile
+	for i := range r.store.profiles {
+		p := &r.store.profiles[i]
+		if p.UserID != uid {
+			continue
+		}
+		p.ProfileImage = updated.ProfileImage
+		p.FullName = updated.FullName
+		p.Designation = updated.Designation
+		p.Organization = updated.Organization
+		p.ProfessionalSummary = updated.ProfessionalSummary
+		p.Location = updated.Location
+		p.Email = updated.Email
+		p.ContactNumber = updated.ContactNumber
+		if updated.DefaultVisibility != "" {
+			p.DefaultVisibility = updated.DefaultVisibility
+		}
+		p.UpdatedAt = updated.UpdatedAt
+		if result == nil {
+			profile := *p
+			result = &profile
+		}
+	}
+	if result == nil {
+		return nil, errNoRows()
+	}
+	return result, nil
+}
+
+func (r *userProfileRepo) Delete(ctx context.Context, userID string) error {
+	uid, err := parseUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.profiles = filter(r.store.profiles, func(p models.UserProfile) bool { return p.UserID != uid })
+	return nil
+}
+
+// emailTaken reports whether a profile of another user already uses email.
+// It must be called with the store lock held.
+func (r *userProfileRepo) emailTaken(email string, owner uuid.UUID) bool {
+	for _, p := range r.store.profiles {
+		if p.Email == email && p.UserID != owner {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+type videoProfileRepo struct {
+	store *Store
+}
+
+// NewVideoProfileRepository creates an in-memory VideoProfileRepository.
+func NewVideoProfileRepository(store *Store) repositories.VideoProfileRepository {
+	return &videoProfileRepo{store: store}
+}
+
+func (r *videoProfileRepo) Create(ctx context.Context, video *models.VideoProfile) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(video.UserID) {
+		return foreignKeyViolation("video_profile_user_id_fkey")
+	}
+
+	now := time.Now()
+	video.ID = uuid.New()
+	video.CreatedAt = now
+	video.UpdatedAt = now
+	r.store.videos = append(r.store.videos, *video)
+	return nil
+}
+
+func (r *videoProfileRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.VideoProfile, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var profiles []*models.VideoProfile
+	for _, v := range r.store.videos {
+		if v.UserID == userID {
+			video := v
+			profiles = append(profiles, &video)
+		}
+	}
+	return profiles, nil
+}
+
+func (r *videoProfileRepo) Update(ctx context.Context, video *models.VideoProfile) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.videos {
+		if r.store.videos[i].ID == video.ID {
+			r.store.videos[i].VideoURL = video.VideoURL
+			r.store.videos[i].UpdatedAt = time.Now()
+			video.UpdatedAt = r.store.videos[i].UpdatedAt
+			return nil
+		}
+	}
+	// UPDATE ... RETURNING scans no row
+	return errNoRows()
+}
+
+func (r *videoProfileRepo) Delete(ctx context.Context, videoID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.videos = filter(r.store.videos, func(v models.VideoProfile) bool { return v.ID != videoID })
+	return nil
+}
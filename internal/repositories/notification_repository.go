@@ -4,49 +4,64 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 )
 
-type NotificationRepository struct {
+// NotificationRepository stores user notifications.
+type NotificationRepository interface {
+	Create(ctx context.Context, n *models.Notification) error
+	GetByUserID(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[models.Notification], error)
+}
+
+type notificationRepo struct {
 	DB *sql.DB
 }
 
-func NewNotificationRepository(db *sql.DB) *NotificationRepository {
-	return &NotificationRepository{DB: db}
+// NewNotificationRepository creates a Postgres-backed NotificationRepository.
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepo{DB: db}
 }
 
-func (r *NotificationRepository) Create(ctx context.Context, n *models.Notification) error {
+func (r *notificationRepo) Create(ctx context.Context, n *models.Notification) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	n.CreatedAt = time.Now()
 
 	if n.SenderUserID == uuid.Nil || n.RecipientUserID == uuid.Nil {
-		return fmt.Errorf("sender_user_id and recipient_user_id cannot be empty")
+		return apperrors.Validation("sender_user_id and recipient_user_id cannot be empty")
 	}
 
 	query := `INSERT INTO notifications (id, recipient_user_id, sender_user_id, type, entity_id, entity_type, message, is_read, created_at)
 			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
-	_, err := r.DB.ExecContext(ctx, query,
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query,
 		n.ID, n.RecipientUserID, n.SenderUserID, n.Type, n.EntityID, n.EntityType, n.Message, n.IsRead, n.CreatedAt)
 
 	if err != nil {
-		log.Printf("Failed to insert notification: %v", err)
-		return fmt.Errorf("failed to create notification: %w", err)
+		return mapError(fmt.Errorf("failed to create notification: %w", err))
 	}
 
 	return nil
 }
 
-func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.Notification, error) {
+func (r *notificationRepo) GetByUserID(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[models.Notification], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("created_at", "id", 2)
 	query := `SELECT id, recipient_user_id, sender_user_id, type, entity_id, entity_type, message, is_read, created_at
-			  FROM notifications WHERE recipient_user_id = $1 ORDER BY created_at DESC`
+			  FROM notifications WHERE recipient_user_id = $1 AND ` + after + `
+			  ` + page.OrderBy("created_at", "id")
 
-	rows, err := r.DB.QueryContext(ctx, query, userID)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, append([]any{userID}, args...)...)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Notification]{}, mapError(err)
 	}
 	defer rows.Close()
 
@@ -58,9 +73,16 @@ func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 			&n.Type, &n.EntityID, &n.EntityType,
 			&n.Message, &n.IsRead, &n.CreatedAt,
 		); err != nil {
-			return nil, err
+			return pagination.Page[models.Notification]{}, mapError(err)
 		}
 		notifications = append(notifications, n)
 	}
-	return notifications, nil
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Notification]{}, mapError(err)
+	}
+	return pagination.NewPage(notifications, page, notificationCursor), nil
+}
+
+func notificationCursor(n models.Notification) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: n.CreatedAt, ID: n.ID}
 }
@@ -7,42 +7,67 @@ import (
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
 )
 
-type OTPRepository struct {
+// OTPRepository stores one-time passwords issued for email verification.
+type OTPRepository interface {
+	InsertOTP(ctx context.Context, email, otp string) error
+	SaveOTP(ctx context.Context, otp models.OTP) error
+	GetOTPByEmail(ctx context.Context, email string) (*models.OTP, error)
+	MarkUserVerified(ctx context.Context, email string) error
+}
+
+type otpRepo struct {
 	DB *sql.DB
 }
 
+// NewOTPRepository creates a Postgres-backed OTPRepository.
+func NewOTPRepository(db *sql.DB) OTPRepository {
+	return &otpRepo{DB: db}
+}
+
 // InsertOTP saves an OTP for the user email into the database
-func (r *OTPRepository) InsertOTP(ctx context.Context, email, otp string) error {
-	query := `INSERT INTO otps (email, otp) VALUES ($1, $2)`
-	_, err := r.DB.ExecContext(ctx, query, email, otp)
-	return err
+func (r *otpRepo) InsertOTP(ctx context.Context, email, otp string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO otps (email, otp, created_at) VALUES ($1, $2, NOW())`
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, email, otp)
+	return mapError(err)
 }
 
 // SaveOTP saves an OTP record in the database
-func (r *OTPRepository) SaveOTP(ctx context.Context, otp models.OTP) error {
+func (r *otpRepo) SaveOTP(ctx context.Context, otp models.OTP) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `INSERT INTO otps (id, email, otp, is_verified, created_at) 
 	          VALUES (uuid_generate_v4(), $1, $2, $3, $4)`
-	_, err := r.DB.ExecContext(ctx, query, otp.Email, otp.OTP, otp.IsVerified, otp.CreatedAt)
-	return err
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, otp.Email, otp.OTP, otp.IsVerified, otp.CreatedAt)
+	return mapError(err)
 }
 
 // GetOTPByEmail retrieves the OTP record by email
-func (r *OTPRepository) GetOTPByEmail(ctx context.Context, email string) (*models.OTP, error) {
+func (r *otpRepo) GetOTPByEmail(ctx context.Context, email string) (*models.OTP, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `SELECT id, email, otp, is_verified, created_at FROM otps WHERE email = $1 ORDER BY created_at DESC LIMIT 1`
-	row := r.DB.QueryRowContext(ctx, query, email)
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, email)
 
 	var otp models.OTP
 	err := row.Scan(&otp.ID, &otp.Email, &otp.OTP, &otp.IsVerified, &otp.CreatedAt)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return &otp, nil
 }
 
 // MarkUserVerified updates a user's verification status
-func (r *OTPRepository) MarkUserVerified(ctx context.Context, email string) error {
+func (r *otpRepo) MarkUserVerified(ctx context.Context, email string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `UPDATE otps SET is_verified = TRUE WHERE email = $1`
-	_, err := r.DB.ExecContext(ctx, query, email)
-	return err
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, email)
+	return mapError(err)
 }
@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// PollRepository stores the votes of poll posts. Polls themselves are
+// created with their post by PostRepository.CreatePost.
+type PollRepository interface {
+	// GetPoll returns the poll of a post with the choices of viewerID.
+	GetPoll(ctx context.Context, viewerID, postID uuid.UUID) (*models.Poll, error)
+	// Vote records the choice of userID. It returns ErrConflict when they
+	// already voted in the poll and ErrNotFound when an option is not one
+	// of the poll's. Run it in a transaction.
+	Vote(ctx context.Context, postID, userID uuid.UUID, optionIDs []uuid.UUID) error
+	// ClaimEnded marks up to limit polls ended at now as reported and
+	// returns their posts, leaving out the ones locked by other
+	// transactions so notifiers running side by side never report a poll
+	// twice. Run it in a transaction that tells the authors.
+	ClaimEnded(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+}
+
+// errAlreadyVoted is the cause of the ErrConflict returned by Vote.
+var errAlreadyVoted = errors.New("user already voted in the poll")
+
+type pollRepo struct {
+	DB *sql.DB
+}
+
+// NewPollRepository creates a Postgres-backed PollRepository.
+func NewPollRepository(db *sql.DB) PollRepository {
+	return &pollRepo{DB: db}
+}
+
+func (r *pollRepo) GetPoll(ctx context.Context, viewerID, postID uuid.UUID) (*models.Poll, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	polls, err := loadPolls(ctx, r.DB, viewerID, []uuid.UUID{postID})
+	if err != nil {
+		return nil, err
+	}
+	poll, ok := polls[postID]
+	if !ok {
+		return nil, mapError(sql.ErrNoRows)
+	}
+	return poll, nil
+}
+
+func (r *pollRepo) Vote(ctx context.Context, postID, userID uuid.UUID, optionIDs []uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// The primary key of post_poll_voters lets a user vote once, even when
+	// their votes race each other.
+	_, err := conn(ctx, r.DB).ExecContext(ctx, `
+		INSERT INTO post_poll_voters (post_id, user_id)
+		VALUES ($1, $2)`, postID, userID)
+	if err = mapError(err); errors.Is(err, apperrors.ErrConflict) {
+		return apperrors.Wrap(apperrors.ErrConflict, errAlreadyVoted)
+	}
+	if err != nil {
+		return err
+	}
+
+	options := make([]string, len(optionIDs))
+	for i, id := range optionIDs {
+		options[i] = id.String()
+	}
+	_, err = conn(ctx, r.DB).ExecContext(ctx, `
+		INSERT INTO post_poll_votes (post_id, option_id, user_id)
+		SELECT $1, option_id, $2
+		FROM unnest($3::uuid[]) AS option_id`, postID, userID, pq.Array(options))
+	return mapError(err)
+}
+
+func (r *pollRepo) ClaimEnded(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, `
+		UPDATE post_polls
+		SET ended_notified_at = $1
+		WHERE post_id IN (
+			SELECT post_id FROM post_polls
+			WHERE ended_notified_at IS NULL AND ends_at <= $1
+			ORDER BY ends_at, post_id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING post_id`, now, limit)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, mapError(err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(err)
+	}
+	return ids, nil
+}
+
+// insertPoll stores the poll of a new post with its options in their order.
+func insertPoll(ctx context.Context, db *sql.DB, postID uuid.UUID, poll *models.Poll) (*models.Poll, error) {
+	stored := &models.Poll{
+		PostID:         postID,
+		MultipleChoice: poll.MultipleChoice,
+		Options:        make([]models.PollOption, 0, len(poll.Options)),
+		Choices:        []uuid.UUID{},
+	}
+	err := conn(ctx, db).QueryRowContext(ctx, `
+		INSERT INTO post_polls (post_id, multiple_choice, ends_at)
+		VALUES ($1, $2, $3)
+		RETURNING ends_at`, postID, poll.MultipleChoice, poll.EndsAt,
+	).Scan(&stored.EndsAt)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	for i, o := range poll.Options {
+		o.PostID, o.Position, o.Votes = postID, i, 0
+		err := conn(ctx, db).QueryRowContext(ctx, `
+			INSERT INTO post_poll_options (post_id, position, label)
+			VALUES ($1, $2, $3)
+			RETURNING id`, o.PostID, o.Position, o.Label,
+		).Scan(&o.ID)
+		if err != nil {
+			return nil, mapError(err)
+		}
+		stored.Options = append(stored.Options, o)
+	}
+	return stored, nil
+}
+
+// loadPolls returns the polls of the given posts by post, with the vote
+// counts and the choices of viewerID. Posts without a poll are left out.
+func loadPolls(ctx context.Context, db *sql.DB, viewerID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]*models.Poll, error) {
+	byPost := make(map[uuid.UUID]*models.Poll)
+	if len(postIDs) == 0 {
+		return byPost, nil
+	}
+	ids := make([]string, len(postIDs))
+	for i, id := range postIDs {
+		ids[i] = id.String()
+	}
+
+	rows, err := conn(ctx, db).QueryContext(ctx, `
+		SELECT pp.post_id, pp.multiple_choice, pp.ends_at,
+			(SELECT COUNT(*) FROM post_poll_voters v WHERE v.post_id = pp.post_id)
+		FROM post_polls pp
+		WHERE pp.post_id = ANY($1::uuid[])`, pq.Array(ids))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		p := &models.Poll{Options: []models.PollOption{}, Choices: []uuid.UUID{}}
+		if err := rows.Scan(&p.PostID, &p.MultipleChoice, &p.EndsAt, &p.TotalVoters); err != nil {
+			return nil, mapError(err)
+		}
+		byPost[p.PostID] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(err)
+	}
+	rows.Close()
+	if len(byPost) == 0 {
+		return byPost, nil
+	}
+
+	rows, err = conn(ctx, db).QueryContext(ctx, `
+		SELECT o.id, o.post_id, o.position, o.label,
+			(SELECT COUNT(*) FROM post_poll_votes v WHERE v.option_id = o.id),
+			EXISTS (SELECT 1 FROM post_poll_votes v WHERE v.option_id = o.id AND v.user_id = $2)
+		FROM post_poll_options o
+		WHERE o.post_id = ANY($1::uuid[])
+		ORDER BY o.post_id, o.position`, pq.Array(ids), viewerID)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			o      models.PollOption
+			chosen bool
+		)
+		if err := rows.Scan(&o.ID, &o.PostID, &o.Position, &o.Label, &o.Votes, &chosen); err != nil {
+			return nil, mapError(err)
+		}
+		p := byPost[o.PostID]
+		p.Options = append(p.Options, o)
+		if chosen {
+			p.Choices = append(p.Choices, o.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(err)
+	}
+	return byPost, nil
+}
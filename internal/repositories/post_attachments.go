@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// insertAttachments stores the attachments of a new post in their order.
+func insertAttachments(ctx context.Context, db *sql.DB, postID uuid.UUID, attachments []models.PostAttachment) ([]models.PostAttachment, error) {
+	stored := make([]models.PostAttachment, 0, len(attachments))
+	for i, a := range attachments {
+		a.PostID, a.Position = postID, i
+		err := conn(ctx, db).QueryRowContext(ctx, `
+			INSERT INTO post_attachments
+				(post_id, position, url, mime_type, size_bytes, width, height, duration_seconds, alt_text)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id`,
+			a.PostID, a.Position, a.URL, a.MimeType, a.SizeBytes, a.Width, a.Height, a.DurationSeconds, a.AltText,
+		).Scan(&a.ID)
+		if err != nil {
+			return nil, mapError(err)
+		}
+		stored = append(stored, a)
+	}
+	return stored, nil
+}
+
+// loadAttachments returns the attachments of the given posts by post, in
+// their order. Posts without attachments get an empty slice.
+func loadAttachments(ctx context.Context, db *sql.DB, postIDs []uuid.UUID) (map[uuid.UUID][]models.PostAttachment, error) {
+	byPost := make(map[uuid.UUID][]models.PostAttachment, len(postIDs))
+	if len(postIDs) == 0 {
+		return byPost, nil
+	}
+	ids := make([]string, len(postIDs))
+	for i, id := range postIDs {
+		ids[i] = id.String()
+		byPost[id] = []models.PostAttachment{}
+	}
+
+	rows, err := conn(ctx, db).QueryContext(ctx, `
+		SELECT id, post_id, position, url, mime_type, size_bytes, width, height, duration_seconds, alt_text
+		FROM post_attachments
+		WHERE post_id = ANY($1::uuid[])
+		ORDER BY post_id, position`, pq.Array(ids))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a models.PostAttachment
+		err := rows.Scan(&a.ID, &a.PostID, &a.Position, &a.URL, &a.MimeType, &a.SizeBytes,
+			&a.Width, &a.Height, &a.DurationSeconds, &a.AltText)
+		if err != nil {
+			return nil, mapError(err)
+		}
+		byPost[a.PostID] = append(byPost[a.PostID], a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(err)
+	}
+	return byPost, nil
+}
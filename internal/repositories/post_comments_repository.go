@@ -1,51 +1,86 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"log"
 
 	"github.com/google/uuid"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 )
 
-type PostCommentRepository struct {
+// PostCommentRepository stores comments on content posts.
+type PostCommentRepository interface {
+	// CreateComment stores comment and sets its ID and creation time.
+	CreateComment(ctx context.Context, comment *models.PostComment) error
+	GetComments(ctx context.Context, postID uuid.UUID, page pagination.Params) (pagination.Page[models.PostComment], error)
+	PostExists(ctx context.Context, postID uuid.UUID) (bool, error)
+}
+
+type postCommentRepo struct {
 	DB *sql.DB
 }
 
-func (repo *PostCommentRepository) CreateComment(userID, postID uuid.UUID, comment string) error {
-	_, err := repo.DB.Exec(`
-        INSERT INTO post_comments (user_id, post_id, comment) 
-        VALUES ($1, $2, $3)`, userID, postID, comment)
+// NewPostCommentRepository creates a Postgres-backed PostCommentRepository.
+func NewPostCommentRepository(db *sql.DB) PostCommentRepository {
+	return &postCommentRepo{DB: db}
+}
+
+func (repo *postCommentRepo) CreateComment(ctx context.Context, comment *models.PostComment) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	entities, err := entitiesValue(comment.Entities)
 	if err != nil {
-		log.Printf("Error inserting comment for user %v on post %v: %v", userID, postID, err)
 		return err
 	}
-	return nil
+
+	err = conn(ctx, repo.DB).QueryRowContext(ctx, `
+        INSERT INTO post_comments (user_id, post_id, comment, entities)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at`, comment.UserID, comment.PostID, comment.Comment, entities,
+	).Scan(&comment.ID, &comment.CreatedAt)
+	return mapError(err)
 }
 
-func (repo *PostCommentRepository) GetComments(postID uuid.UUID) ([]models.PostComment, error) {
-	rows, err := repo.DB.Query(`
-		SELECT id, user_id, post_id, comment, created_at
-		FROM post_comments 
-		WHERE post_id = $1`, postID)
+func (repo *postCommentRepo) GetComments(ctx context.Context, postID uuid.UUID, page pagination.Params) (pagination.Page[models.PostComment], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("created_at", "id", 2)
+	rows, err := conn(ctx, repo.DB).QueryContext(ctx, `
+		SELECT id, user_id, post_id, comment, created_at, entities
+		FROM post_comments
+		WHERE post_id = $1 AND `+after+`
+		`+page.OrderBy("created_at", "id"), append([]any{postID}, args...)...)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.PostComment]{}, mapError(err)
 	}
 	defer rows.Close()
 
 	var comments []models.PostComment
 	for rows.Next() {
 		var comment models.PostComment
-		if err := rows.Scan(&comment.ID, &comment.UserID, &comment.PostID, &comment.Comment, &comment.CreatedAt); err != nil {
-			return nil, err
+		if err := rows.Scan(&comment.ID, &comment.UserID, &comment.PostID, &comment.Comment, &comment.CreatedAt, entitiesColumn{&comment.Entities}); err != nil {
+			return pagination.Page[models.PostComment]{}, mapError(err)
 		}
 		comments = append(comments, comment)
 	}
-	return comments, nil
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.PostComment]{}, mapError(err)
+	}
+	return pagination.NewPage(comments, page, commentCursor), nil
 }
 
-func (repo *PostCommentRepository) PostExists(postID uuid.UUID) (bool, error) {
+func commentCursor(c models.PostComment) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+}
+
+func (repo *postCommentRepo) PostExists(ctx context.Context, postID uuid.UUID) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	var exists bool
-	err := repo.DB.QueryRow("SELECT EXISTS (SELECT 1 FROM content_post WHERE id = $1)", postID).Scan(&exists)
-	return exists, err
+	err := conn(ctx, repo.DB).QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM content_post WHERE id = $1)", postID).Scan(&exists)
+	return exists, mapError(err)
 }
@@ -1,50 +1,63 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 )
 
-type PostLikeRepository struct {
+// PostLikeRepository stores likes on content posts. A user likes a post at most once.
+type PostLikeRepository interface {
+	CreateLike(ctx context.Context, userID, postID uuid.UUID) error
+	RemoveLike(ctx context.Context, userID, postID uuid.UUID) error
+	// GetLikes returns a page of the users who liked a post, latest likes
+	// first.
+	GetLikes(ctx context.Context, postID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error)
+}
+
+type postLikeRepo struct {
 	DB *sql.DB
 }
 
+// NewPostLikeRepository creates a Postgres-backed PostLikeRepository.
+func NewPostLikeRepository(db *sql.DB) PostLikeRepository {
+	return &postLikeRepo{DB: db}
+}
+
 // CreateLike adds a new like for a post by a user
-func (repo *PostLikeRepository) CreateLike(userID, postID uuid.UUID) error {
-	_, err := repo.DB.Exec(`
+func (repo *postLikeRepo) CreateLike(ctx context.Context, userID, postID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.DB).ExecContext(ctx, `
 		INSERT INTO post_likes (user_id, post_id) 
 		VALUES ($1, $2) 
 		ON CONFLICT (user_id, post_id) DO NOTHING`, userID, postID)
-	return err
+	return mapError(err)
 }
 
 // RemoveLike removes a like from a post by a user
-func (repo *PostLikeRepository) RemoveLike(userID, postID uuid.UUID) error {
-	_, err := repo.DB.Exec(`
+func (repo *postLikeRepo) RemoveLike(ctx context.Context, userID, postID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.DB).ExecContext(ctx, `
 		DELETE FROM post_likes 
 		WHERE user_id = $1 AND post_id = $2`, userID, postID)
-	return err
+	return mapError(err)
 }
 
-// GetLikes retrieves all likes for a post
-func (repo *PostLikeRepository) GetLikes(postID uuid.UUID) ([]uuid.UUID, error) {
-	rows, err := repo.DB.Query(`
-		SELECT user_id 
-		FROM post_likes 
-		WHERE post_id = $1`, postID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var likes []uuid.UUID
-	for rows.Next() {
-		var userID uuid.UUID
-		if err := rows.Scan(&userID); err != nil {
-			return nil, err
-		}
-		likes = append(likes, userID)
-	}
-	return likes, nil
+// GetLikes retrieves a page of the likes of a post
+func (repo *postLikeRepo) GetLikes(ctx context.Context, postID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("created_at", "user_id", 2)
+	return queryUserPage(ctx, repo.DB, page, `
+		SELECT created_at, user_id
+		FROM post_likes
+		WHERE post_id = $1 AND `+after+`
+		`+page.OrderBy("created_at", "user_id"), append([]any{postID}, args...)...)
 }
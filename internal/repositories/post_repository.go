@@ -3,39 +3,123 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 )
 
-type PostRepository struct {
+// PostRepository stores content posts and builds the feed views over them.
+type PostRepository interface {
+	// CreatePost stores a post with its attachments or poll; run it in a
+	// transaction when there are any. A post with a poll is a poll post.
+	CreatePost(ctx context.Context, post *models.ContentPost) (*models.ContentPost, error)
+	// GetAllWithDetails and GetPostsByUserID return only the posts
+	// viewerID can see.
+	GetAllWithDetails(ctx context.Context, viewerID uuid.UUID, page pagination.Params) (pagination.Page[models.PostWithDetails], error)
+	GetPostsByUserID(ctx context.Context, viewerID, userID uuid.UUID, page pagination.Params) (pagination.Page[models.ContentPost], error)
+	// GetPopular returns public posts only, with the poll choices of
+	// viewerID.
+	GetPopular(ctx context.Context, viewerID uuid.UUID, since time.Time, limit int) ([]models.PostWithDetails, error)
+	// GetPostByID, SetVisibility and UpdatePost return the post with the
+	// poll choices of viewerID; uuid.Nil reads it as no one.
+	GetPostByID(ctx context.Context, viewerID, postID uuid.UUID) (*models.ContentPost, error)
+	// CanView reports whether viewerID can see a post; it is false when
+	// the post does not exist.
+	CanView(ctx context.Context, viewerID, postID uuid.UUID) (bool, error)
+	// SetVisibility changes who can see a post.
+	SetVisibility(ctx context.Context, viewerID, postID uuid.UUID, visibility string) (*models.ContentPost, error)
+	// UpdatePost replaces the text of a post and its entities, marks it
+	// edited and stores the text it replaced as a revision.
+	UpdatePost(ctx context.Context, viewerID, postID uuid.UUID, content string, entities []models.Entity) (*models.ContentPost, error)
+	// DeletePost deletes a post with its likes, comments, revisions and
+	// reposts; run it in a transaction.
+	DeletePost(ctx context.Context, postID uuid.UUID) error
+	// DeleteRepost deletes the repost of postID by userID.
+	DeleteRepost(ctx context.Context, userID, postID uuid.UUID) error
+	// GetRevisions returns a page of the earlier versions of a post, latest
+	// first.
+	GetRevisions(ctx context.Context, postID uuid.UUID, page pagination.Params) (pagination.Page[models.PostRevision], error)
+}
+
+type postRepo struct {
 	DB *sql.DB
 }
 
-func NewPostRepository(db *sql.DB) *PostRepository {
-	return &PostRepository{DB: db}
+// NewPostRepository creates a Postgres-backed PostRepository.
+func NewPostRepository(db *sql.DB) PostRepository {
+	return &postRepo{DB: db}
 }
 
-func (r *PostRepository) CreatePost(ctx context.Context, post *models.ContentPost) (*models.ContentPost, error) {
+func (r *postRepo) CreatePost(ctx context.Context, post *models.ContentPost) (*models.ContentPost, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	entities, err := entitiesValue(post.Entities)
+	if err != nil {
+		return nil, err
+	}
+
+	postType := models.PostTypeText
+	if post.Poll != nil {
+		postType = models.PostTypePoll
+	}
+
 	query := `
-        INSERT INTO content_post (user_id, post_content, media_url)
-        VALUES ($1, $2, $3)
-        RETURNING id, user_id, post_content, media_url, created_at
-    `
+        INSERT INTO content_post AS cp (user_id, post_content, media_url, entities, shared_post_id, share_type, visibility, post_type)
+        VALUES ($1, $2, $3, $4, $5, $6, COALESCE(NULLIF($7, ''), 'public'), $8)
+        RETURNING ` + postColumns
 
-	row := r.DB.QueryRowContext(ctx, query, post.UserID, post.PostContent, post.MediaURL)
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query,
+		post.UserID, post.PostContent, post.MediaURL, entities, post.SharedPostID, post.ShareType, post.Visibility, postType)
 	var created models.ContentPost
-	err := row.Scan(&created.ID, &created.UserID, &created.PostContent, &created.MediaURL, &created.CreatedAt)
+	if err := row.Scan(postFields(&created)...); err != nil {
+		return nil, mapError(err)
+	}
+
+	created.Attachments, err = insertAttachments(ctx, r.DB, created.ID, post.Attachments)
 	if err != nil {
 		return nil, err
 	}
-
+	if post.Poll != nil {
+		if created.Poll, err = insertPoll(ctx, r.DB, created.ID, post.Poll); err != nil {
+			return nil, err
+		}
+	}
 	return &created, nil
 }
 
-func (r *PostRepository) GetAllWithDetails(ctx context.Context) ([]models.PostWithDetails, error) {
-	query := `
-		SELECT 
+// postColumns are the content_post columns, of the table aliased cp, read
+// by postFields.
+const postColumns = `cp.id, cp.user_id, cp.post_content, cp.media_url, cp.created_at, cp.edited_at, cp.entities, cp.shared_post_id, cp.share_type, cp.visibility, cp.post_type`
+
+// postFields returns the scan destinations of postColumns.
+func postFields(p *models.ContentPost) []any {
+	return []any{&p.ID, &p.UserID, &p.PostContent, &p.MediaURL, &p.CreatedAt, &p.EditedAt, entitiesColumn{&p.Entities}, &p.SharedPostID, &p.ShareType, &p.Visibility, &p.PostType}
+}
+
+// visibleTo is the condition on content_post cp that the user in parameter
+// $n can see the post: it is theirs, public, or for followers and they
+// follow its author. uuid.Nil sees public posts only.
+func visibleTo(n int) string {
+	viewer := "$" + strconv.Itoa(n)
+	return `(cp.user_id = ` + viewer + `
+			OR cp.visibility = 'public'
+			OR (cp.visibility = 'followers' AND EXISTS (
+				SELECT 1 FROM followers f
+				WHERE f.followed_id = cp.user_id AND f.follower_id = ` + viewer + `
+			)))`
+}
+
+// postDetailsQuery selects the columns read by scanPostDetails. Each post is
+// shown with the latest profile of its author; posts of users without a
+// profile are left out. The counts are correlated subqueries so only the
+// posts returned are counted. Callers append a WHERE clause.
+const postDetailsQuery = `
+		SELECT
 			cp.id AS post_id,
 			cp.user_id,
 			up.profile_image,
@@ -43,73 +127,201 @@ func (r *PostRepository) GetAllWithDetails(ctx context.Context) ([]models.PostWi
 			up.designation,
 			cp.post_content,
 			cp.media_url,
-			COUNT(DISTINCT pl.id) AS total_likes,
-			COUNT(DISTINCT pc.id) AS total_comments
+			cp.created_at,
+			cp.edited_at,
+			cp.entities,
+			cp.shared_post_id,
+			cp.share_type,
+			cp.visibility,
+			cp.post_type,
+			(SELECT COUNT(*) FROM post_likes pl WHERE pl.post_id = cp.id) AS total_likes,
+			(SELECT COUNT(*) FROM post_comments pc WHERE pc.post_id = cp.id) AS total_comments,
+			(SELECT COUNT(*) FROM content_post sp WHERE sp.shared_post_id = cp.id) AS total_shares
 		FROM content_post cp
-		JOIN user_profile up ON cp.user_id = up.user_id
-		LEFT JOIN post_likes pl ON cp.id = pl.post_id
-		LEFT JOIN post_comments pc ON cp.id = pc.post_id
-		GROUP BY cp.id, cp.user_id, up.profile_image, up.full_name, up.designation, cp.post_content, cp.media_url
-		ORDER BY cp.created_at DESC;
-	`
+		JOIN LATERAL (
+			SELECT profile_image, full_name, designation
+			FROM user_profile
+			WHERE user_id = cp.user_id
+			ORDER BY created_at DESC, id DESC
+			LIMIT 1
+		) up ON TRUE`
+
+// GetAllWithDetails returns a page of the feed of every user.
+func (r *postRepo) GetAllWithDetails(ctx context.Context, viewerID uuid.UUID, page pagination.Params) (pagination.Page[models.PostWithDetails], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("cp.created_at", "cp.id", 2)
+	query := postDetailsQuery + `
+		WHERE ` + visibleTo(1) + ` AND ` + after + `
+		` + page.OrderBy("cp.created_at", "cp.id")
+
+	posts, err := queryPostDetails(ctx, r.DB, viewerID, query, append([]any{viewerID}, args...)...)
+	if err != nil {
+		return pagination.Page[models.PostWithDetails]{}, err
+	}
+	return pagination.NewPage(posts, page, postCursor), nil
+}
+
+// GetPopular returns up to limit posts created since the given time, most
+// liked and commented first.
+func (r *postRepo) GetPopular(ctx context.Context, viewerID uuid.UUID, since time.Time, limit int) ([]models.PostWithDetails, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT * FROM (` + postDetailsQuery + `
+		WHERE cp.created_at >= $1 AND cp.visibility = 'public'
+	) popular
+	ORDER BY total_likes + total_comments DESC, created_at DESC, post_id DESC
+	LIMIT $2`
+
+	return queryPostDetails(ctx, r.DB, viewerID, query, since, limit)
+}
+
+// queryPostDetails runs a query built on postDetailsQuery and embeds the
+// posts shared by the reposts and quotes among the results that viewerID
+// can see. Polls are read for viewerID.
+func queryPostDetails(ctx context.Context, db *sql.DB, viewerID uuid.UUID, query string, args ...any) ([]models.PostWithDetails, error) {
+	posts, err := scanPostDetails(ctx, db, viewerID, query, args...)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.DB.QueryContext(ctx, query)
+	var shared []string
+	for _, p := range posts {
+		if p.SharedPostID != nil {
+			shared = append(shared, p.SharedPostID.String())
+		}
+	}
+	if len(shared) == 0 {
+		return posts, nil
+	}
+	originals, err := scanPostDetails(ctx, db, viewerID, postDetailsQuery+`
+		WHERE cp.id = ANY($1::uuid[]) AND `+visibleTo(2), pq.Array(shared), viewerID)
 	if err != nil {
 		return nil, err
 	}
+	byID := make(map[uuid.UUID]*models.PostWithDetails, len(originals))
+	for i := range originals {
+		byID[originals[i].PostID] = &originals[i]
+	}
+	for i, p := range posts {
+		if p.SharedPostID != nil {
+			posts[i].SharedPost = byID[*p.SharedPostID]
+		}
+	}
+	return posts, nil
+}
+
+// scanPostDetails runs a query built on postDetailsQuery and loads the
+// attachments of the posts and their polls with the choices of viewerID.
+func scanPostDetails(ctx context.Context, db *sql.DB, viewerID uuid.UUID, query string, args ...any) ([]models.PostWithDetails, error) {
+	rows, err := conn(ctx, db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
 	defer rows.Close()
 
 	var posts []models.PostWithDetails
 
 	for rows.Next() {
 		var (
-			postID        uuid.UUID
-			userID        uuid.UUID
-			profileImg    sql.NullString
-			fullName      string
-			designation   sql.NullString
-			postContent   string
-			mediaURL      sql.NullString
-			totalLikes    int
-			totalComments int
+			post        models.PostWithDetails
+			profileImg  sql.NullString
+			designation sql.NullString
+			mediaURL    sql.NullString
 		)
 
 		err := rows.Scan(
-			&postID,
-			&userID,
+			&post.PostID,
+			&post.UserID,
 			&profileImg,
-			&fullName,
+			&post.FullName,
 			&designation,
-			&postContent,
+			&post.PostContent,
 			&mediaURL,
-			&totalLikes,
-			&totalComments,
+			&post.CreatedAt,
+			&post.EditedAt,
+			entitiesColumn{&post.Entities},
+			&post.SharedPostID,
+			&post.ShareType,
+			&post.Visibility,
+			&post.PostType,
+			&post.TotalLikes,
+			&post.TotalComments,
+			&post.TotalShares,
 		)
 		if err != nil {
-			return nil, err
+			return nil, mapError(err)
 		}
 
-		var mediaPtr *string
+		post.ProfileImage = nullToStrings(profileImg)
+		post.Designation = nullToStrings(designation)
 		if mediaURL.Valid {
-			mediaPtr = &mediaURL.String
+			post.MediaURL = &mediaURL.String
 		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(err)
+	}
+	rows.Close()
 
-		post := models.PostWithDetails{
-			PostID:        postID,
-			UserID:        userID,
-			ProfileImage:  nullToStrings(profileImg),
-			FullName:      fullName,
-			Designation:   nullToStrings(designation),
-			PostContent:   postContent,
-			MediaURL:      mediaPtr,
-			TotalLikes:    totalLikes,
-			TotalComments: totalComments,
+	var (
+		ids   = make([]uuid.UUID, len(posts))
+		polls []uuid.UUID
+	)
+	for i, p := range posts {
+		ids[i] = p.PostID
+		if p.PostType == models.PostTypePoll {
+			polls = append(polls, p.PostID)
 		}
+	}
+	attachments, err := loadAttachments(ctx, db, ids)
+	if err != nil {
+		return nil, err
+	}
+	pollsByPost, err := loadPolls(ctx, db, viewerID, polls)
+	if err != nil {
+		return nil, err
+	}
+	for i := range posts {
+		posts[i].Attachments = attachments[posts[i].PostID]
+		posts[i].Poll = pollsByPost[posts[i].PostID]
+	}
+	return posts, nil
+}
 
-		posts = append(posts, post)
+// attachTo loads the attachments of posts and their polls with the choices
+// of viewerID.
+func (r *postRepo) attachTo(ctx context.Context, viewerID uuid.UUID, posts ...*models.ContentPost) error {
+	var (
+		ids   = make([]uuid.UUID, len(posts))
+		polls []uuid.UUID
+	)
+	for i, p := range posts {
+		ids[i] = p.ID
+		if p.PostType == models.PostTypePoll {
+			polls = append(polls, p.ID)
+		}
+	}
+	attachments, err := loadAttachments(ctx, r.DB, ids)
+	if err != nil {
+		return err
+	}
+	pollsByPost, err := loadPolls(ctx, r.DB, viewerID, polls)
+	if err != nil {
+		return err
 	}
+	for _, p := range posts {
+		p.Attachments = attachments[p.ID]
+		p.Poll = pollsByPost[p.ID]
+	}
+	return nil
+}
 
-	return posts, nil
+func postCursor(p models.PostWithDetails) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: p.CreatedAt, ID: p.PostID}
 }
 
 func nullToStrings(ns sql.NullString) string {
@@ -119,18 +331,20 @@ func nullToStrings(ns sql.NullString) string {
 	return ""
 }
 
-func (r *PostRepository) GetPostsByUserID(userID uuid.UUID) ([]models.ContentPost, error) {
-	rows, err := r.DB.Query(`
-		SELECT 
-			cp.id, cp.user_id, cp.post_content, cp.media_url, cp.created_at,
+func (r *postRepo) GetPostsByUserID(ctx context.Context, viewerID, userID uuid.UUID, page pagination.Params) (pagination.Page[models.ContentPost], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("cp.created_at", "cp.id", 3)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, `
+		SELECT `+postColumns+`,
 			u.id, u.username, u.email
 			FROM content_post cp
 			INNER JOIN users u ON cp.user_id = u.id
-			WHERE cp.user_id = $1
-		ORDER BY cp.created_at DESC
-	`, userID)
+			WHERE cp.user_id = $1 AND `+visibleTo(2)+` AND `+after+`
+		`+page.OrderBy("cp.created_at", "cp.id"), append([]any{userID, viewerID}, args...)...)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.ContentPost]{}, mapError(err)
 	}
 	defer rows.Close()
 
@@ -139,16 +353,183 @@ func (r *PostRepository) GetPostsByUserID(userID uuid.UUID) ([]models.ContentPos
 		var post models.ContentPost
 		var user models.User
 
-		err := rows.Scan(
-			&post.ID, &post.UserID, &post.PostContent, &post.MediaURL, &post.CreatedAt,
-			&user.ID, &user.Username, &user.Email,
-		)
+		err := rows.Scan(append(postFields(&post), &user.ID, &user.Username, &user.Email)...)
 		if err != nil {
-			return nil, err
+			return pagination.Page[models.ContentPost]{}, mapError(err)
 		}
 
 		post.User = &user
 		posts = append(posts, post)
 	}
-	return posts, nil
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.ContentPost]{}, mapError(err)
+	}
+	rows.Close()
+
+	result := pagination.NewPage(posts, page, contentPostCursor)
+	ptrs := make([]*models.ContentPost, len(result.Items))
+	for i := range result.Items {
+		ptrs[i] = &result.Items[i]
+	}
+	if err := r.attachTo(ctx, viewerID, ptrs...); err != nil {
+		return pagination.Page[models.ContentPost]{}, err
+	}
+	return result, nil
+}
+
+func contentPostCursor(p models.ContentPost) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+}
+
+func (r *postRepo) GetPostByID(ctx context.Context, viewerID, postID uuid.UUID) (*models.ContentPost, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var post models.ContentPost
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `
+		SELECT `+postColumns+`
+		FROM content_post cp
+		WHERE cp.id = $1`, postID,
+	).Scan(postFields(&post)...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if err := r.attachTo(ctx, viewerID, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (r *postRepo) CanView(ctx context.Context, viewerID, postID uuid.UUID) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var visible bool
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM content_post cp
+			WHERE cp.id = $1 AND `+visibleTo(2)+`
+		)`, postID, viewerID).Scan(&visible)
+	return visible, mapError(err)
+}
+
+func (r *postRepo) SetVisibility(ctx context.Context, viewerID, postID uuid.UUID, visibility string) (*models.ContentPost, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var post models.ContentPost
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `
+		UPDATE content_post cp
+		SET visibility = $2
+		WHERE cp.id = $1
+		RETURNING `+postColumns, postID, visibility,
+	).Scan(postFields(&post)...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if err := r.attachTo(ctx, viewerID, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (r *postRepo) UpdatePost(ctx context.Context, viewerID, postID uuid.UUID, content string, entities []models.Entity) (*models.ContentPost, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	entitiesJSON, err := entitiesValue(entities)
+	if err != nil {
+		return nil, err
+	}
+
+	// The previous version is locked and saved in the same statement, so
+	// concurrent edits each record the text they replaced.
+	var post models.ContentPost
+	err = conn(ctx, r.DB).QueryRowContext(ctx, `
+		WITH previous AS (
+			SELECT id, post_content, COALESCE(edited_at, created_at) AS written_at
+			FROM content_post
+			WHERE id = $1
+			FOR UPDATE
+		), revision AS (
+			INSERT INTO post_revisions (post_id, post_content, created_at)
+			SELECT id, post_content, written_at FROM previous
+		)
+		UPDATE content_post cp
+		SET post_content = $2, entities = $3, edited_at = CURRENT_TIMESTAMP
+		FROM previous
+		WHERE cp.id = previous.id
+		RETURNING `+postColumns, postID, content, entitiesJSON,
+	).Scan(postFields(&post)...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if err := r.attachTo(ctx, viewerID, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (r *postRepo) DeletePost(ctx context.Context, postID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	// Quotes keep their text when the post they quote is deleted; reposts
+	// have nothing left to show.
+	db := conn(ctx, r.DB)
+	if _, err := db.ExecContext(ctx, `
+		DELETE FROM content_post
+		WHERE shared_post_id = $1 AND share_type = 'repost'`, postID); err != nil {
+		return mapError(err)
+	}
+	var id uuid.UUID
+	err := db.QueryRowContext(ctx, `DELETE FROM content_post WHERE id = $1 RETURNING id`, postID).Scan(&id)
+	return mapError(err)
+}
+
+func (r *postRepo) DeleteRepost(ctx context.Context, userID, postID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var id uuid.UUID
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `
+		DELETE FROM content_post
+		WHERE user_id = $1 AND shared_post_id = $2 AND share_type = 'repost'
+		RETURNING id`, userID, postID).Scan(&id)
+	return mapError(err)
+}
+
+// GetRevisions pages revisions by the time they were replaced, the order
+// in which they were written.
+func (r *postRepo) GetRevisions(ctx context.Context, postID uuid.UUID, page pagination.Params) (pagination.Page[models.PostRevision], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("replaced_at", "id", 2)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, `
+		SELECT id, post_id, post_content, created_at, replaced_at
+		FROM post_revisions
+		WHERE post_id = $1 AND `+after+`
+		`+page.OrderBy("replaced_at", "id"), append([]any{postID}, args...)...)
+	if err != nil {
+		return pagination.Page[models.PostRevision]{}, mapError(err)
+	}
+	defer rows.Close()
+
+	var revisions []models.PostRevision
+	for rows.Next() {
+		var rev models.PostRevision
+		if err := rows.Scan(&rev.ID, &rev.PostID, &rev.PostContent, &rev.CreatedAt, &rev.ReplacedAt); err != nil {
+			return pagination.Page[models.PostRevision]{}, mapError(err)
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.PostRevision]{}, mapError(err)
+	}
+	return pagination.NewPage(revisions, page, revisionCursor), nil
+}
+
+func revisionCursor(rev models.PostRevision) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: rev.ReplacedAt, ID: rev.ID}
 }
@@ -0,0 +1,39 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/repotest"
+	"github.com/sagar-rathod-devops/do-host-network-backend/migrations"
+)
+
+// TestPostgresContract runs the repository contract against a real database.
+// Point TEST_DATABASE_URL at a disposable Postgres instance to enable it; every
+// table is truncated between subtests.
+func TestPostgresContract(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("migrating: %v", err)
+	}
+
+	repotest.Run(t, func(t *testing.T) repositories.Repositories {
+		if _, err := db.Exec(`TRUNCATE users, otps, rate_limit_buckets, idempotency_keys CASCADE`); err != nil {
+			t.Fatalf("truncating tables: %v", err)
+		}
+		return repositories.NewPostgres(db)
+	})
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ratelimit"
+)
+
+type rateLimitRepo struct {
+	DB *sql.DB
+}
+
+// NewRateLimitRepository creates a Postgres-backed rate limit store, so every
+// replica draws from the same buckets.
+func NewRateLimitRepository(db *sql.DB) ratelimit.Store {
+	return &rateLimitRepo{DB: db}
+}
+
+// refilled is the token count of the existing bucket refilled up to now,
+// with $2 the burst and $3 the rate.
+const refilled = `LEAST($2::float8, b.tokens + $3::float8 * GREATEST(EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8, 0))`
+
+// takeTokenQuery refills and takes from the bucket in one statement; the row
+// lock of the upsert serializes concurrent requests for the same key.
+const takeTokenQuery = `
+	INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+	VALUES ($1, $2::float8 - 1, TRUE, NOW())
+	ON CONFLICT (key) DO UPDATE SET
+		tokens = CASE WHEN ` + refilled + ` >= 1 THEN ` + refilled + ` - 1 ELSE ` + refilled + ` END,
+		allowed = ` + refilled + ` >= 1,
+		updated_at = NOW()
+	RETURNING tokens, allowed`
+
+// Take takes a token from the bucket of key, timed by the database clock so
+// replicas with skewed clocks agree.
+func (r *rateLimitRepo) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var tokens float64
+	var allowed bool
+	err := conn(ctx, r.DB).QueryRowContext(ctx, takeTokenQuery, key, limit.Burst, limit.Rate).Scan(&tokens, &allowed)
+	if err != nil {
+		return ratelimit.Result{}, mapError(err)
+	}
+	return limit.Result(tokens, allowed), nil
+}
+
+// Prune deletes buckets untouched for idle.
+func (r *rateLimitRepo) Prune(ctx context.Context, idle time.Duration) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `DELETE FROM rate_limit_buckets WHERE updated_at < NOW() - make_interval(secs => $1)`
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, idle.Seconds())
+	return mapError(err)
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ratelimit"
+)
+
+// Repositories bundles one implementation of every repository so the
+// application and the tests can swap the whole persistence layer at once.
+type Repositories struct {
+	Users          UserRepository
+	OTPs           OTPRepository
+	Posts          PostRepository
+	Jobs           JobRepository
+	UserProfiles   UserProfileRepository
+	VideoProfiles  VideoProfileRepository
+	UserEducation  UserEducationRepository
+	UserExperience UserExperienceRepository
+	PostLikes      PostLikeRepository
+	PostComments   PostCommentRepository
+	Follows        FollowRepository
+	Notifications  NotificationRepository
+	Timelines      TimelineRepository
+	Hashtags       HashtagRepository
+	Drafts         DraftRepository
+	Polls          PollRepository
+	RateLimits     ratelimit.Store
+	Idempotency    idempotency.Store
+	Tx             TxManager
+}
+
+// NewPostgres creates the Postgres-backed implementation of every repository.
+func NewPostgres(db *sql.DB) Repositories {
+	return Repositories{
+		Users:          NewUserRepository(db),
+		OTPs:           NewOTPRepository(db),
+		Posts:          NewPostRepository(db),
+		Jobs:           NewJobRepository(db),
+		UserProfiles:   NewUserProfileRepository(db),
+		VideoProfiles:  NewVideoProfileRepository(db),
+		UserEducation:  NewUserEducationRepository(db),
+		UserExperience: NewUserExperienceRepository(db),
+		PostLikes:      NewPostLikeRepository(db),
+		PostComments:   NewPostCommentRepository(db),
+		Follows:        NewFollowRepository(db),
+		Notifications:  NewNotificationRepository(db),
+		Timelines:      NewTimelineRepository(db),
+		Hashtags:       NewHashtagRepository(db),
+		Drafts:         NewDraftRepository(db),
+		Polls:          NewPollRepository(db),
+		RateLimits:     NewRateLimitRepository(db),
+		Idempotency:    NewIdempotencyRepository(db),
+		Tx:             NewTxManager(db),
+	}
+}
@@ -0,0 +1,1490 @@
+// Package repotest holds the behavioural contract every implementation of the
+// repository interfaces must satisfy. The Postgres and in-memory
+// implementations both run it from their own tests, so a fake can never
+// quietly drift from the real database.
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ratelimit"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+// Factory returns a set of repositories backed by empty storage.
+type Factory func(t *testing.T) repositories.Repositories
+
+// Run executes the whole contract against the repositories returned by newRepos.
+// Every subtest gets fresh storage.
+func Run(t *testing.T, newRepos Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repos repositories.Repositories)
+	}{
+		{"Users", testUsers},
+		{"OTPs", testOTPs},
+		{"Posts", testPosts},
+		{"PostEdits", testPostEdits},
+		{"PostAttachments", testPostAttachments},
+		{"Hashtags", testHashtags},
+		{"Shares", testShares},
+		{"Visibility", testVisibility},
+		{"Polls", testPolls},
+		{"PostLikes", testPostLikes},
+		{"PostComments", testPostComments},
+		{"Follows", testFollows},
+		{"Timelines", testTimelines},
+		{"PopularPosts", testPopularPosts},
+		{"Signals", testSignals},
+		{"DeleteUserCascades", testDeleteUserCascades},
+		{"UserProfiles", testUserProfiles},
+		{"Notifications", testNotifications},
+		{"Drafts", testDrafts},
+		{"Jobs", testJobs},
+		{"Paging", testPaging},
+		{"FollowerPaging", testFollowerPaging},
+		{"VideoProfiles", testVideoProfiles},
+		{"UserEducation", testUserEducation},
+		{"UserExperience", testUserExperience},
+		{"Transactions", testTransactions},
+		{"RateLimits", testRateLimits},
+		{"Idempotency", testIdempotency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepos(t))
+		})
+	}
+}
+
+// CreateUser inserts a user named username and returns its ID.
+func CreateUser(t *testing.T, repos repositories.Repositories, username string) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+
+	now := time.Now()
+	err := repos.Users.CreateUser(ctx, models.User{
+		Email:        username + "@example.com",
+		Username:     username,
+		PasswordHash: "hash-" + username,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", username, err)
+	}
+
+	user, err := repos.Users.GetUserByEmailOrUsername(ctx, username)
+	if err != nil {
+		t.Fatalf("GetUserByEmailOrUsername(%s): %v", username, err)
+	}
+	return uuid.MustParse(user.ID)
+}
+
+// CreatePost inserts a content post authored by userID.
+func CreatePost(t *testing.T, repos repositories.Repositories, userID uuid.UUID, content string) *models.ContentPost {
+	t.Helper()
+
+	post, err := repos.Posts.CreatePost(context.Background(), &models.ContentPost{UserID: userID, PostContent: content})
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	return post
+}
+
+// CreateProfile inserts a profile for userID.
+func CreateProfile(t *testing.T, repos repositories.Repositories, userID uuid.UUID, fullName string) *models.UserProfile {
+	ctx := context.Background()
+	t.Helper()
+
+	now := time.Now()
+	designation := "Front Office Manager"
+	profile := &models.UserProfile{
+		ID:          uuid.New(),
+		UserID:      userID,
+		FullName:    fullName,
+		Designation: &designation,
+		Email:       userID.String() + "@profiles.example.com",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := repos.UserProfiles.Create(ctx, profile); err != nil {
+		t.Fatalf("UserProfiles.Create: %v", err)
+	}
+	return profile
+}
+
+// tick separates consecutive inserts so created_at ordering is deterministic.
+func tick() {
+	time.Sleep(2 * time.Millisecond)
+}
+
+func testUsers(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	id := CreateUser(t, repos, "alice")
+
+	byEmail, err := repos.Users.GetUserByEmail(ctx, "alice@example.com")
+	if err != nil || byEmail.ID != id.String() {
+		t.Fatalf("GetUserByEmail = %v, %v; want user %s", byEmail, err, id)
+	}
+	byID, err := repos.Users.GetUserByID(ctx, id.String())
+	if err != nil || byID.Username != "alice" {
+		t.Fatalf("GetUserByID = %v, %v; want alice", byID, err)
+	}
+
+	dupEmail := models.User{Email: "alice@example.com", Username: "alice2", PasswordHash: "x"}
+	if err := repos.Users.CreateUser(ctx, dupEmail); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("CreateUser with duplicate email error = %v; want ErrConflict", err)
+	}
+	dupName := models.User{Email: "other@example.com", Username: "alice", PasswordHash: "x"}
+	if err := repos.Users.CreateUser(ctx, dupName); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("CreateUser with duplicate username error = %v; want ErrConflict", err)
+	}
+
+	if _, err := repos.Users.GetUserByEmailOrUsername(ctx, "nobody"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetUserByEmailOrUsername(nobody) error = %v; want ErrNotFound", err)
+	}
+	if _, err := repos.Users.GetUserByID(ctx, uuid.NewString()); !errors.Is(err, sql.ErrNoRows) || !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetUserByID(unknown) error = %v; want sql.ErrNoRows classified as ErrNotFound", err)
+	}
+	if _, err := repos.Users.GetUserByID(ctx, "not-a-uuid"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("GetUserByID(malformed) error = %v; want ErrValidation", err)
+	}
+
+	if err := repos.Users.UpdatePassword(ctx, "alice@example.com", "new-hash"); err != nil {
+		t.Fatalf("UpdatePassword: %v", err)
+	}
+	updated, _ := repos.Users.GetUserByEmail(ctx, "alice@example.com")
+	if updated.PasswordHash != "new-hash" {
+		t.Errorf("PasswordHash = %q; want new-hash", updated.PasswordHash)
+	}
+}
+
+func testOTPs(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	email := "otp@example.com"
+
+	if _, err := repos.OTPs.GetOTPByEmail(ctx, email); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("GetOTPByEmail(unknown) error = %v; want sql.ErrNoRows", err)
+	}
+
+	now := time.Now()
+	if err := repos.OTPs.SaveOTP(ctx, models.OTP{Email: email, OTP: "111111", CreatedAt: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("SaveOTP: %v", err)
+	}
+	if err := repos.OTPs.SaveOTP(ctx, models.OTP{Email: email, OTP: "222222", CreatedAt: now}); err != nil {
+		t.Fatalf("SaveOTP: %v", err)
+	}
+
+	latest, err := repos.OTPs.GetOTPByEmail(ctx, email)
+	if err != nil || latest.OTP != "222222" || latest.IsVerified {
+		t.Fatalf("GetOTPByEmail = %+v, %v; want unverified 222222", latest, err)
+	}
+
+	if err := repos.OTPs.MarkUserVerified(ctx, email); err != nil {
+		t.Fatalf("MarkUserVerified: %v", err)
+	}
+	latest, _ = repos.OTPs.GetOTPByEmail(ctx, email)
+	if !latest.IsVerified {
+		t.Error("OTP not verified after MarkUserVerified")
+	}
+}
+
+func testPosts(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+
+	if _, err := repos.Posts.CreatePost(ctx, &models.ContentPost{UserID: uuid.New(), PostContent: "orphan"}); err == nil {
+		t.Error("CreatePost for unknown user succeeded")
+	}
+
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	first := CreatePost(t, repos, alice, "first")
+	tick()
+	second := CreatePost(t, repos, alice, "second")
+
+	posts, err := repos.Posts.GetPostsByUserID(ctx, bob, alice, pagination.First())
+	if err != nil {
+		t.Fatalf("GetPostsByUserID: %v", err)
+	}
+	if len(posts.Items) != 2 || posts.Items[0].ID != second.ID || posts.Items[1].ID != first.ID {
+		t.Fatalf("GetPostsByUserID = %v; want [second first]", posts)
+	}
+	if posts.Items[0].User == nil || posts.Items[0].User.Username != "alice" {
+		t.Errorf("post author = %+v; want alice", posts.Items[0].User)
+	}
+
+	// The feed joins user_profile, so posts only show up once a profile exists.
+	feed, err := repos.Posts.GetAllWithDetails(ctx, bob, pagination.First())
+	if err != nil || len(feed.Items) != 0 {
+		t.Fatalf("GetAllWithDetails without profile = %v, %v; want empty", feed, err)
+	}
+
+	CreateProfile(t, repos, alice, "Alice Example")
+	if err := repos.PostLikes.CreateLike(ctx, bob, second.ID); err != nil {
+		t.Fatalf("CreateLike: %v", err)
+	}
+	if err := repos.PostComments.CreateComment(ctx, &models.PostComment{UserID: bob, PostID: second.ID, Comment: "nice"}); err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+
+	feed, err = repos.Posts.GetAllWithDetails(ctx, bob, pagination.First())
+	if err != nil || len(feed.Items) != 2 {
+		t.Fatalf("GetAllWithDetails = %v, %v; want 2 posts", feed, err)
+	}
+	top := feed.Items[0]
+	if top.PostID != second.ID || top.FullName != "Alice Example" || top.TotalLikes != 1 || top.TotalComments != 1 {
+		t.Errorf("feed[0] = %+v; want second post by Alice with 1 like and 1 comment", top)
+	}
+}
+
+func testPostEdits(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	post := CreatePost(t, repos, alice, "helo")
+
+	got, err := repos.Posts.GetPostByID(ctx, uuid.Nil, post.ID)
+	if err != nil || got.PostContent != "helo" || got.EditedAt != nil {
+		t.Fatalf("GetPostByID = %+v, %v; want the unedited post", got, err)
+	}
+	if _, err := repos.Posts.GetPostByID(ctx, uuid.Nil, uuid.New()); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetPostByID(unknown) error = %v; want ErrNotFound", err)
+	}
+
+	tick()
+	edited, err := repos.Posts.UpdatePost(ctx, alice, post.ID, "hello", nil)
+	if err != nil || edited.PostContent != "hello" || edited.EditedAt == nil || edited.UserID != alice {
+		t.Fatalf("UpdatePost = %+v, %v; want the edited post", edited, err)
+	}
+	tick()
+	if _, err := repos.Posts.UpdatePost(ctx, alice, post.ID, "hello, world", nil); err != nil {
+		t.Fatalf("second UpdatePost: %v", err)
+	}
+	if _, err := repos.Posts.UpdatePost(ctx, alice, uuid.New(), "lost", nil); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("UpdatePost(unknown) error = %v; want ErrNotFound", err)
+	}
+
+	revisions, err := repos.Posts.GetRevisions(ctx, post.ID, pagination.First())
+	if err != nil || len(revisions.Items) != 2 || revisions.Items[0].PostContent != "hello" || revisions.Items[1].PostContent != "helo" {
+		t.Fatalf("GetRevisions = %+v, %v; want [hello helo]", revisions, err)
+	}
+	if !revisions.Items[1].CreatedAt.Equal(post.CreatedAt) || !revisions.Items[0].CreatedAt.Equal(*edited.EditedAt) {
+		t.Errorf("revisions written at %v and %v; want the creation and the first edit", revisions.Items[1].CreatedAt, revisions.Items[0].CreatedAt)
+	}
+
+	mustNoErr(t, repos.PostLikes.CreateLike(ctx, bob, post.ID))
+	mustNoErr(t, repos.PostComments.CreateComment(ctx, &models.PostComment{UserID: bob, PostID: post.ID, Comment: "nice"}))
+	mustNoErr(t, repos.Posts.DeletePost(ctx, post.ID))
+	if err := repos.Posts.DeletePost(ctx, post.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("DeletePost twice error = %v; want ErrNotFound", err)
+	}
+	if likes, _ := repos.PostLikes.GetLikes(ctx, post.ID, pagination.First()); len(likes.Items) != 0 {
+		t.Errorf("likes of a deleted post = %v; want none", likes)
+	}
+	if comments, _ := repos.PostComments.GetComments(ctx, post.ID, pagination.First()); len(comments.Items) != 0 {
+		t.Errorf("comments of a deleted post = %v; want none", comments.Items)
+	}
+	if revisions, _ := repos.Posts.GetRevisions(ctx, post.ID, pagination.First()); len(revisions.Items) != 0 {
+		t.Errorf("revisions of a deleted post = %v; want none", revisions)
+	}
+}
+
+func testPostAttachments(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	CreateProfile(t, repos, alice, "Alice Example")
+	width, height, duration := 640, 480, 12.5
+
+	var post *models.ContentPost
+	err := repos.Tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		post, err = repos.Posts.CreatePost(ctx, &models.ContentPost{
+			UserID:      alice,
+			PostContent: "album",
+			MediaURL:    "https://cdn.example.com/b.mp4",
+			Attachments: []models.PostAttachment{
+				{URL: "https://cdn.example.com/b.mp4", MimeType: "video/mp4", SizeBytes: 2048, DurationSeconds: &duration},
+				{URL: "https://cdn.example.com/a.png", MimeType: "image/png", SizeBytes: 1024, Width: &width, Height: &height, AltText: "a cat"},
+			},
+		})
+		return err
+	})
+	if err != nil || len(post.Attachments) != 2 || post.Attachments[0].ID == uuid.Nil || post.Attachments[1].Position != 1 {
+		t.Fatalf("CreatePost = %+v, %v; want two stored attachments", post, err)
+	}
+	tick()
+	plain := CreatePost(t, repos, alice, "plain")
+
+	check := func(name string, got []models.PostAttachment) {
+		t.Helper()
+		if len(got) != 2 || got[0].MimeType != "video/mp4" || *got[0].DurationSeconds != duration || got[0].Width != nil ||
+			got[1].AltText != "a cat" || *got[1].Width != width || *got[1].Height != height || got[1].SizeBytes != 1024 {
+			t.Errorf("%s attachments = %+v; want the video then the image", name, got)
+		}
+	}
+	posts, err := repos.Posts.GetPostsByUserID(ctx, alice, alice, pagination.First())
+	if err != nil || len(posts.Items) != 2 {
+		t.Fatalf("GetPostsByUserID = %v, %v", posts, err)
+	}
+	for _, p := range posts.Items {
+		if p.ID == post.ID {
+			check("GetPostsByUserID", p.Attachments)
+		} else if p.Attachments == nil || len(p.Attachments) != 0 {
+			t.Errorf("attachments of %q = %#v; want an empty list", p.PostContent, p.Attachments)
+		}
+	}
+	got, err := repos.Posts.GetPostByID(ctx, uuid.Nil, post.ID)
+	if err != nil {
+		t.Fatalf("GetPostByID: %v", err)
+	}
+	check("GetPostByID", got.Attachments)
+	feed, err := repos.Posts.GetAllWithDetails(ctx, alice, pagination.First())
+	if err != nil || len(feed.Items) != 2 || feed.Items[0].PostID != plain.ID {
+		t.Fatalf("GetAllWithDetails = %v, %v", feed, err)
+	}
+	check("GetAllWithDetails", feed.Items[1].Attachments)
+
+	mustNoErr(t, repos.Posts.DeletePost(ctx, post.ID))
+	if got, _ := repos.Posts.GetAllWithDetails(ctx, alice, pagination.First()); len(got.Items) != 1 {
+		t.Errorf("feed after DeletePost = %v; want the plain post only", got.Items)
+	}
+}
+
+func testHashtags(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	CreateProfile(t, repos, alice, "Alice Example")
+
+	users, err := repos.Users.GetUsersByUsernames(ctx, []string{"bob", "nobody"})
+	if err != nil || len(users) != 1 || users[0].ID != bob.String() {
+		t.Fatalf("GetUsersByUsernames = %v, %v; want bob only", users, err)
+	}
+
+	mention := []models.Entity{
+		{Type: models.EntityHashtag, Start: 0, End: 6, Text: "hotel"},
+		{Type: models.EntityMention, Start: 7, End: 11, Text: "bob", UserID: &bob},
+	}
+	tagged, err := repos.Posts.CreatePost(ctx, &models.ContentPost{UserID: alice, PostContent: "#hotel @bob", Entities: mention})
+	if err != nil || len(tagged.Entities) != 2 {
+		t.Fatalf("CreatePost = %+v, %v; want its entities", tagged, err)
+	}
+	tick()
+	other := CreatePost(t, repos, alice, "#Chef")
+	if other.Entities == nil || len(other.Entities) != 0 {
+		t.Errorf("entities of a post without any = %#v; want an empty list", other.Entities)
+	}
+	got, err := repos.Posts.GetPostByID(ctx, uuid.Nil, tagged.ID)
+	if err != nil || len(got.Entities) != 2 || *got.Entities[1].UserID != bob || got.Entities[0].End != 6 {
+		t.Fatalf("GetPostByID entities = %+v, %v", got.Entities, err)
+	}
+
+	mustNoErr(t, repos.Hashtags.IndexPost(ctx, tagged.ID, []string{"hotel", "travel"}))
+	mustNoErr(t, repos.Hashtags.IndexPost(ctx, other.ID, []string{"hotel", "chef"}))
+	comment := &models.PostComment{UserID: bob, PostID: other.ID, Comment: "#chef", Entities: []models.Entity{{Type: models.EntityHashtag, End: 5, Text: "chef"}}}
+	mustNoErr(t, repos.PostComments.CreateComment(ctx, comment))
+	if comment.ID == uuid.Nil || comment.CreatedAt.IsZero() {
+		t.Fatalf("CreateComment left comment = %+v; want its ID and creation time", comment)
+	}
+	mustNoErr(t, repos.Hashtags.IndexComment(ctx, comment.ID, []string{"chef"}))
+	if err := repos.Hashtags.IndexPost(ctx, uuid.New(), []string{"hotel"}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("IndexPost(unknown) error = %v; want ErrNotFound", err)
+	}
+
+	comments, err := repos.PostComments.GetComments(ctx, other.ID, pagination.First())
+	if err != nil || len(comments.Items) != 1 || len(comments.Items[0].Entities) != 1 {
+		t.Fatalf("GetComments = %+v, %v; want the comment with its hashtag", comments, err)
+	}
+
+	hotel, err := repos.Hashtags.GetPosts(ctx, bob, "hotel", pagination.First())
+	if err != nil || len(hotel.Items) != 2 || hotel.Items[0].PostID != other.ID || len(hotel.Items[1].Entities) != 2 {
+		t.Fatalf("GetPosts(hotel) = %+v, %v; want both posts, newest first", hotel, err)
+	}
+
+	trending, err := repos.Hashtags.Trending(ctx, time.Now().Add(-time.Hour), 2)
+	want := []models.HashtagCount{{Tag: "chef", Count: 2}, {Tag: "hotel", Count: 2}}
+	if err != nil || !slices.Equal(trending, want) {
+		t.Errorf("Trending = %v, %v; want %v", trending, err, want)
+	}
+	if trending, _ := repos.Hashtags.Trending(ctx, time.Now().Add(time.Hour), 10); trending == nil || len(trending) != 0 {
+		t.Errorf("Trending outside the window = %#v; want an empty list", trending)
+	}
+
+	// Re-indexing drops the tags a post no longer uses.
+	mustNoErr(t, repos.Hashtags.IndexPost(ctx, tagged.ID, []string{"travel"}))
+	if hotel, _ := repos.Hashtags.GetPosts(ctx, bob, "hotel", pagination.First()); len(hotel.Items) != 1 || hotel.Items[0].PostID != other.ID {
+		t.Errorf("GetPosts(hotel) after re-indexing = %v; want the other post only", hotel.Items)
+	}
+
+	mustNoErr(t, repos.Posts.DeletePost(ctx, other.ID))
+	trending, err = repos.Hashtags.Trending(ctx, time.Now().Add(-time.Hour), 10)
+	want = []models.HashtagCount{{Tag: "travel", Count: 1}}
+	if err != nil || !slices.Equal(trending, want) {
+		t.Errorf("Trending after DeletePost = %v, %v; want %v", trending, err, want)
+	}
+}
+
+func testShares(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	CreateProfile(t, repos, alice, "Alice Example")
+	CreateProfile(t, repos, bob, "Bob Example")
+	original := CreatePost(t, repos, alice, "Grand opening")
+	tick()
+
+	share := func(shareType, content string) (*models.ContentPost, error) {
+		return repos.Posts.CreatePost(ctx, &models.ContentPost{UserID: bob, PostContent: content, SharedPostID: &original.ID, ShareType: shareType})
+	}
+	repost, err := share(models.ShareRepost, "")
+	if err != nil || repost.ShareType != models.ShareRepost || *repost.SharedPostID != original.ID {
+		t.Fatalf("repost = %+v, %v", repost, err)
+	}
+	if _, err := share(models.ShareRepost, ""); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("second repost error = %v; want ErrConflict", err)
+	}
+	tick()
+	quote, err := share(models.ShareQuote, "See you there")
+	if err != nil {
+		t.Fatalf("quote: %v", err)
+	}
+	lost := uuid.New()
+	if _, err := repos.Posts.CreatePost(ctx, &models.ContentPost{UserID: bob, SharedPostID: &lost, ShareType: models.ShareRepost}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("repost of an unknown post error = %v; want ErrNotFound", err)
+	}
+	if got, err := repos.Posts.GetPostByID(ctx, uuid.Nil, quote.ID); err != nil || got.ShareType != models.ShareQuote || *got.SharedPostID != original.ID {
+		t.Errorf("GetPostByID(quote) = %+v, %v", got, err)
+	}
+
+	feed, err := repos.Posts.GetAllWithDetails(ctx, alice, pagination.First())
+	if err != nil || len(feed.Items) != 3 {
+		t.Fatalf("GetAllWithDetails = %v, %v; want the original and both shares", feed, err)
+	}
+	q, r, o := feed.Items[0], feed.Items[1], feed.Items[2]
+	if o.PostID != original.ID || o.TotalShares != 2 || o.SharedPost != nil {
+		t.Errorf("original = %+v; want two shares", o)
+	}
+	if r.ShareType != models.ShareRepost || r.SharedPost == nil || r.SharedPost.PostID != original.ID || r.SharedPost.FullName != "Alice Example" || r.SharedPost.TotalShares != 2 {
+		t.Errorf("repost = %+v; want the original embedded", r)
+	}
+	if q.PostContent != "See you there" || q.SharedPost == nil || q.SharedPost.PostContent != "Grand opening" {
+		t.Errorf("quote = %+v; want the original embedded", q)
+	}
+
+	if err := repos.Posts.DeleteRepost(ctx, alice, original.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("DeleteRepost by a user who did not repost error = %v; want ErrNotFound", err)
+	}
+	mustNoErr(t, repos.Posts.DeleteRepost(ctx, bob, original.ID))
+	again, err := share(models.ShareRepost, "")
+	if err != nil {
+		t.Fatalf("repost after DeleteRepost: %v", err)
+	}
+
+	// Deleting the original deletes its reposts and keeps its quotes.
+	mustNoErr(t, repos.Tx.WithTx(ctx, func(ctx context.Context) error { return repos.Posts.DeletePost(ctx, original.ID) }))
+	if _, err := repos.Posts.GetPostByID(ctx, uuid.Nil, again.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("repost of a deleted post error = %v; want ErrNotFound", err)
+	}
+	feed, err = repos.Posts.GetAllWithDetails(ctx, alice, pagination.First())
+	if err != nil || len(feed.Items) != 1 || feed.Items[0].PostID != quote.ID || feed.Items[0].SharedPostID != nil || feed.Items[0].SharedPost != nil {
+		t.Errorf("feed after deleting the original = %+v, %v; want the quote alone", feed.Items, err)
+	}
+}
+
+func testVisibility(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	carol := CreateUser(t, repos, "carol")
+	for _, id := range []uuid.UUID{alice, bob, carol} {
+		CreateProfile(t, repos, id, "Example")
+	}
+	mustNoErr(t, repos.Follows.FollowUser(ctx, bob, alice))
+
+	post := func(content, visibility string) *models.ContentPost {
+		t.Helper()
+		p, err := repos.Posts.CreatePost(ctx, &models.ContentPost{UserID: alice, PostContent: content, Visibility: visibility})
+		if err != nil {
+			t.Fatalf("CreatePost(%s): %v", visibility, err)
+		}
+		mustNoErr(t, repos.Hashtags.IndexPost(ctx, p.ID, []string{"hotel"}))
+		tick()
+		return p
+	}
+	public := post("#hotel open", "")
+	followers := post("#hotel staff party", models.VisibilityFollowers)
+	private := post("#hotel notes", models.VisibilityOnlyMe)
+	if public.Visibility != models.VisibilityPublic || followers.Visibility != models.VisibilityFollowers {
+		t.Fatalf("visibility = %q, %q; want public by default and followers", public.Visibility, followers.Visibility)
+	}
+	quote, err := repos.Posts.CreatePost(ctx, &models.ContentPost{UserID: carol, PostContent: "Finally", SharedPostID: &public.ID, ShareType: models.ShareQuote})
+	if err != nil {
+		t.Fatalf("quote: %v", err)
+	}
+
+	for _, c := range []struct {
+		name   string
+		viewer uuid.UUID
+		want   []uuid.UUID
+	}{
+		{"author", alice, []uuid.UUID{private.ID, followers.ID, public.ID}},
+		{"follower", bob, []uuid.UUID{followers.ID, public.ID}},
+		{"stranger", carol, []uuid.UUID{public.ID}},
+	} {
+		posts, err := repos.Posts.GetPostsByUserID(ctx, c.viewer, alice, pagination.First())
+		var got []uuid.UUID
+		for _, p := range posts.Items {
+			got = append(got, p.ID)
+		}
+		if err != nil || !slices.Equal(got, c.want) {
+			t.Errorf("GetPostsByUserID for the %s = %v, %v; want %v", c.name, got, err, c.want)
+		}
+
+		feed, err := repos.Posts.GetAllWithDetails(ctx, c.viewer, pagination.First())
+		got = nil
+		for _, p := range feed.Items {
+			got = append(got, p.PostID)
+		}
+		if want := append([]uuid.UUID{quote.ID}, c.want...); err != nil || !slices.Equal(got, want) {
+			t.Errorf("GetAllWithDetails for the %s = %v, %v; want %v", c.name, got, err, want)
+		}
+
+		tagged, err := repos.Hashtags.GetPosts(ctx, c.viewer, "hotel", pagination.First())
+		if err != nil || len(tagged.Items) != len(c.want) {
+			t.Errorf("Hashtags.GetPosts for the %s = %d posts, %v; want %d", c.name, len(tagged.Items), err, len(c.want))
+		}
+
+		for _, p := range []*models.ContentPost{public, followers, private} {
+			visible, err := repos.Posts.CanView(ctx, c.viewer, p.ID)
+			if want := slices.Contains(c.want, p.ID); err != nil || visible != want {
+				t.Errorf("CanView(%s, %s post) = %v, %v; want %v", c.name, p.Visibility, visible, err, want)
+			}
+		}
+	}
+	if visible, err := repos.Posts.CanView(ctx, alice, uuid.New()); err != nil || visible {
+		t.Errorf("CanView(unknown post) = %v, %v; want false", visible, err)
+	}
+
+	popular, err := repos.Posts.GetPopular(ctx, alice, time.Now().Add(-time.Hour), 10)
+	if err != nil || len(popular) != 2 {
+		t.Errorf("GetPopular = %v, %v; want the public post and the quote", popular, err)
+	}
+	trending, err := repos.Hashtags.Trending(ctx, time.Now().Add(-time.Hour), 10)
+	if err != nil || len(trending) != 1 || trending[0].Count != 1 {
+		t.Errorf("Trending = %v, %v; want hotel counted once, for the public post", trending, err)
+	}
+
+	// The quote stays visible once the post it quotes is hidden, without it.
+	hidden, err := repos.Posts.SetVisibility(ctx, alice, public.ID, models.VisibilityOnlyMe)
+	if err != nil || hidden.Visibility != models.VisibilityOnlyMe {
+		t.Fatalf("SetVisibility = %+v, %v", hidden, err)
+	}
+	feed, err := repos.Posts.GetAllWithDetails(ctx, carol, pagination.First())
+	if err != nil || len(feed.Items) != 1 || feed.Items[0].PostID != quote.ID || feed.Items[0].SharedPost != nil {
+		t.Errorf("GetAllWithDetails after hiding the quoted post = %+v, %v; want the quote alone", feed.Items, err)
+	}
+	if _, err := repos.Posts.SetVisibility(ctx, alice, uuid.New(), models.VisibilityPublic); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("SetVisibility(unknown) error = %v; want ErrNotFound", err)
+	}
+}
+
+func testPolls(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	carol := CreateUser(t, repos, "carol")
+	CreateProfile(t, repos, alice, "Alice Example")
+
+	now := time.Now()
+	post, err := repos.Posts.CreatePost(ctx, &models.ContentPost{
+		UserID:      alice,
+		PostContent: "Best shift?",
+		Poll: &models.Poll{
+			MultipleChoice: true,
+			EndsAt:         now.Add(time.Hour),
+			Options:        []models.PollOption{{Label: "Morning"}, {Label: "Evening"}, {Label: "Night"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreatePost(poll): %v", err)
+	}
+	if post.PostType != models.PostTypePoll || post.Poll == nil || len(post.Poll.Options) != 3 || post.Poll.Options[2].Label != "Night" {
+		t.Fatalf("CreatePost(poll) = %+v; want a poll post with its 3 options in order", post)
+	}
+	morning, evening, night := post.Poll.Options[0].ID, post.Poll.Options[1].ID, post.Poll.Options[2].ID
+	text := CreatePost(t, repos, alice, "No poll here")
+	if text.PostType != models.PostTypeText || text.Poll != nil {
+		t.Errorf("CreatePost = %+v; want a text post without a poll", text)
+	}
+	if _, err := repos.Polls.GetPoll(ctx, bob, text.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetPoll(text post) error = %v; want ErrNotFound", err)
+	}
+
+	mustNoErr(t, repos.Polls.Vote(ctx, post.ID, bob, []uuid.UUID{morning, night}))
+	mustNoErr(t, repos.Polls.Vote(ctx, post.ID, carol, []uuid.UUID{night}))
+	if err := repos.Polls.Vote(ctx, post.ID, bob, []uuid.UUID{evening}); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("second Vote error = %v; want ErrConflict", err)
+	}
+	if err := repos.Polls.Vote(ctx, post.ID, alice, []uuid.UUID{uuid.New()}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Vote for another option error = %v; want ErrNotFound", err)
+	}
+
+	poll, err := repos.Polls.GetPoll(ctx, bob, post.ID)
+	if err != nil {
+		t.Fatalf("GetPoll: %v", err)
+	}
+	votes := []int{poll.Options[0].Votes, poll.Options[1].Votes, poll.Options[2].Votes}
+	if poll.TotalVoters != 2 || !slices.Equal(votes, []int{1, 0, 2}) || len(poll.Choices) != 2 {
+		t.Errorf("GetPoll(bob) = %+v; want 2 voters, votes [1 0 2] and bob's 2 choices", poll)
+	}
+	if poll, _ := repos.Polls.GetPoll(ctx, alice, post.ID); len(poll.Choices) != 0 {
+		t.Errorf("choices of alice = %v; want none", poll.Choices)
+	}
+
+	feed, err := repos.Posts.GetAllWithDetails(ctx, carol, pagination.First())
+	if err != nil || len(feed.Items) != 2 {
+		t.Fatalf("GetAllWithDetails = %v, %v; want 2 posts", feed, err)
+	}
+	for _, p := range feed.Items {
+		wantType := models.PostTypeText
+		if p.PostID == post.ID {
+			wantType = models.PostTypePoll
+		}
+		if p.PostType != wantType || (p.Poll != nil) != (p.PostID == post.ID) {
+			t.Errorf("feed post %s = %+v; want a poll only on the poll post", p.PostContent, p)
+		}
+		if p.Poll != nil && (len(p.Poll.Choices) != 1 || p.Poll.Choices[0] != night) {
+			t.Errorf("feed choices of carol = %v; want [night]", p.Poll.Choices)
+		}
+	}
+	posts, err := repos.Posts.GetPostsByUserID(ctx, bob, alice, pagination.First())
+	if err != nil || len(posts.Items) != 2 || posts.Items[1].Poll == nil || len(posts.Items[1].Poll.Choices) != 2 {
+		t.Errorf("GetPostsByUserID = %+v, %v; want the poll with bob's choices", posts, err)
+	}
+	if got, err := repos.Posts.GetPostByID(ctx, carol, post.ID); err != nil || got.Poll == nil || !slices.Equal(got.Poll.Choices, []uuid.UUID{night}) {
+		t.Errorf("GetPostByID(carol) = %+v, %v; want the poll with carol's choice", got, err)
+	}
+	if got, err := repos.Posts.SetVisibility(ctx, bob, post.ID, models.VisibilityPublic); err != nil || got.Poll == nil || len(got.Poll.Choices) != 2 {
+		t.Errorf("SetVisibility read by bob = %+v, %v; want the poll with bob's choices", got, err)
+	}
+
+	if ended, err := repos.Polls.ClaimEnded(ctx, now, 10); err != nil || len(ended) != 0 {
+		t.Errorf("ClaimEnded before the end = %v, %v; want none", ended, err)
+	}
+	ended, err := repos.Polls.ClaimEnded(ctx, now.Add(2*time.Hour), 10)
+	if err != nil || len(ended) != 1 || ended[0] != post.ID {
+		t.Fatalf("ClaimEnded = %v, %v; want the poll", ended, err)
+	}
+	if ended, err := repos.Polls.ClaimEnded(ctx, now.Add(2*time.Hour), 10); err != nil || len(ended) != 0 {
+		t.Errorf("ClaimEnded again = %v, %v; want none", ended, err)
+	}
+
+	mustNoErr(t, repos.Users.DeleteUser(ctx, bob.String()))
+	if poll, _ := repos.Polls.GetPoll(ctx, carol, post.ID); poll.TotalVoters != 1 {
+		t.Errorf("voters after deleting bob = %d; want 1", poll.TotalVoters)
+	}
+	mustNoErr(t, repos.Posts.DeletePost(ctx, post.ID))
+	if _, err := repos.Polls.GetPoll(ctx, carol, post.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetPoll of a deleted post error = %v; want ErrNotFound", err)
+	}
+
+	// An end time given with an offset is kept as the same instant.
+	at := now.Add(30 * time.Minute).In(time.FixedZone("EST", -5*60*60)).Truncate(time.Microsecond)
+	zoned, err := repos.Posts.CreatePost(ctx, &models.ContentPost{
+		UserID:      alice,
+		PostContent: "Lunch?",
+		Poll:        &models.Poll{EndsAt: at, Options: []models.PollOption{{Label: "Yes"}, {Label: "No"}}},
+	})
+	if err != nil || !zoned.Poll.EndsAt.Equal(at) {
+		t.Fatalf("CreatePost(zoned poll) = %+v, %v; want it to end at %v", zoned, err, at)
+	}
+	if poll, err := repos.Polls.GetPoll(ctx, carol, zoned.ID); err != nil || !poll.EndsAt.Equal(at) {
+		t.Errorf("GetPoll(zoned) = %+v, %v; want it to end at %v", poll, err, at)
+	}
+	if ended, err := repos.Polls.ClaimEnded(ctx, at.Add(-time.Minute), 10); err != nil || len(ended) != 0 {
+		t.Errorf("ClaimEnded before the zoned end = %v, %v; want none", ended, err)
+	}
+	if ended, err := repos.Polls.ClaimEnded(ctx, at.Add(time.Minute), 10); err != nil || len(ended) != 1 || ended[0] != zoned.ID {
+		t.Errorf("ClaimEnded after the zoned end = %v, %v; want the zoned poll", ended, err)
+	}
+}
+
+func testPostLikes(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	post := CreatePost(t, repos, alice, "hello")
+
+	for i := 0; i < 2; i++ {
+		if err := repos.PostLikes.CreateLike(ctx, alice, post.ID); err != nil {
+			t.Fatalf("CreateLike #%d: %v", i+1, err)
+		}
+	}
+	likes, err := repos.PostLikes.GetLikes(ctx, post.ID, pagination.First())
+	if err != nil || len(likes.Items) != 1 || likes.Items[0] != alice {
+		t.Fatalf("GetLikes = %v, %v; want exactly [alice]", likes, err)
+	}
+
+	if err := repos.PostLikes.RemoveLike(ctx, alice, post.ID); err != nil {
+		t.Fatalf("RemoveLike: %v", err)
+	}
+	if likes, _ := repos.PostLikes.GetLikes(ctx, post.ID, pagination.First()); len(likes.Items) != 0 {
+		t.Errorf("GetLikes after RemoveLike = %v; want empty", likes)
+	}
+
+	if err := repos.PostLikes.CreateLike(ctx, alice, uuid.New()); err == nil {
+		t.Error("CreateLike on unknown post succeeded")
+	}
+}
+
+func testPostComments(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	post := CreatePost(t, repos, alice, "hello")
+
+	exists, err := repos.PostComments.PostExists(ctx, post.ID)
+	if err != nil || !exists {
+		t.Fatalf("PostExists = %v, %v; want true", exists, err)
+	}
+	if exists, _ := repos.PostComments.PostExists(ctx, uuid.New()); exists {
+		t.Error("PostExists(unknown) = true")
+	}
+
+	if err := repos.PostComments.CreateComment(ctx, &models.PostComment{UserID: alice, PostID: post.ID, Comment: "first!"}); err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+	comments, err := repos.PostComments.GetComments(ctx, post.ID, pagination.First())
+	if err != nil || len(comments.Items) != 1 || comments.Items[0].Comment != "first!" || comments.Items[0].UserID != alice {
+		t.Fatalf("GetComments = %v, %v; want one comment by alice", comments, err)
+	}
+
+	if err := repos.PostComments.CreateComment(ctx, &models.PostComment{UserID: alice, PostID: uuid.New(), Comment: "lost"}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("CreateComment on unknown post error = %v; want ErrNotFound", err)
+	}
+}
+
+func testFollows(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+
+	for i := 0; i < 2; i++ {
+		if err := repos.Follows.FollowUser(ctx, alice, bob); err != nil {
+			t.Fatalf("FollowUser #%d: %v", i+1, err)
+		}
+	}
+
+	followers, err := repos.Follows.GetFollowers(ctx, bob, pagination.First())
+	if err != nil || len(followers.Items) != 1 || followers.Items[0] != alice {
+		t.Fatalf("GetFollowers(bob) = %v, %v; want [alice]", followers, err)
+	}
+	followings, err := repos.Follows.GetFollowings(ctx, alice, pagination.First())
+	if err != nil || len(followings.Items) != 1 || followings.Items[0] != bob {
+		t.Fatalf("GetFollowings(alice) = %v, %v; want [bob]", followings, err)
+	}
+
+	if err := repos.Follows.UnfollowUser(ctx, alice, bob); err != nil {
+		t.Fatalf("UnfollowUser: %v", err)
+	}
+	if followers, _ := repos.Follows.GetFollowers(ctx, bob, pagination.First()); len(followers.Items) != 0 {
+		t.Errorf("GetFollowers after unfollow = %v; want empty", followers)
+	}
+
+	if err := repos.Follows.FollowUser(ctx, alice, uuid.New()); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("FollowUser on unknown user error = %v; want ErrNotFound", err)
+	}
+}
+
+// testTimelines uses a fan-out limit of one follower: alice, with one
+// follower, is fanned out on write, dave, with two, is read on request.
+func testTimelines(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	const maxFollowers = 1
+
+	users := map[string]uuid.UUID{}
+	for _, name := range []string{"alice", "bob", "carol", "dave", "eve"} {
+		users[name] = CreateUser(t, repos, name)
+		CreateProfile(t, repos, users[name], name)
+	}
+	alice, bob, carol, dave, eve := users["alice"], users["bob"], users["carol"], users["dave"], users["eve"]
+	mustNoErr(t, repos.Follows.FollowUser(ctx, bob, alice))
+	mustNoErr(t, repos.Follows.FollowUser(ctx, bob, dave))
+	mustNoErr(t, repos.Follows.FollowUser(ctx, carol, dave))
+
+	publish := func(author uuid.UUID, content string) *models.ContentPost {
+		t.Helper()
+		tick()
+		post := CreatePost(t, repos, author, content)
+		mustNoErr(t, repos.Timelines.FanOut(ctx, post, maxFollowers))
+		return post
+	}
+	fromAlice := publish(alice, "from alice")
+	fromDave := publish(dave, "from dave")
+	fromBob := publish(bob, "from bob")
+
+	home := func(userID uuid.UUID) []uuid.UUID {
+		t.Helper()
+		page, err := repos.Timelines.Home(ctx, userID, maxFollowers, pagination.First())
+		if err != nil {
+			t.Fatalf("Home: %v", err)
+		}
+		var ids []uuid.UUID
+		for _, p := range page.Items {
+			ids = append(ids, p.PostID)
+		}
+		return ids
+	}
+
+	if got, want := home(bob), []uuid.UUID{fromBob.ID, fromDave.ID, fromAlice.ID}; !slices.Equal(got, want) {
+		t.Errorf("Home(bob) = %v; want own, pulled and fanned out posts %v", got, want)
+	}
+	if got, want := home(carol), []uuid.UUID{fromDave.ID}; !slices.Equal(got, want) {
+		t.Errorf("Home(carol) = %v; want %v", got, want)
+	}
+	if got := home(eve); len(got) != 0 {
+		t.Errorf("Home(eve) = %v; want empty", got)
+	}
+
+	mustNoErr(t, repos.Timelines.Remove(ctx, bob, alice))
+	if got, want := home(bob), []uuid.UUID{fromBob.ID, fromDave.ID}; !slices.Equal(got, want) {
+		t.Errorf("Home(bob) after Remove = %v; want %v", got, want)
+	}
+	mustNoErr(t, repos.Timelines.Backfill(ctx, bob, alice, maxFollowers, 10))
+	if got, want := home(bob), []uuid.UUID{fromBob.ID, fromDave.ID, fromAlice.ID}; !slices.Equal(got, want) {
+		t.Errorf("Home(bob) after Backfill = %v; want %v", got, want)
+	}
+
+	// dave is read on request, so following him backfills nothing.
+	mustNoErr(t, repos.Timelines.Backfill(ctx, eve, dave, maxFollowers, 10))
+	if got := home(eve); len(got) != 0 {
+		t.Errorf("Home(eve) after Backfill of a pulled author = %v; want empty", got)
+	}
+}
+
+func testPopularPosts(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	CreateProfile(t, repos, alice, "Alice Example")
+
+	liked := CreatePost(t, repos, alice, "liked")
+	tick()
+	CreatePost(t, repos, alice, "quiet")
+	tick()
+	newest := CreatePost(t, repos, alice, "newest")
+	mustNoErr(t, repos.PostLikes.CreateLike(ctx, bob, liked.ID))
+
+	got, err := repos.Posts.GetPopular(ctx, bob, time.Now().Add(-time.Hour), 2)
+	if err != nil || len(got) != 2 || got[0].PostID != liked.ID || got[1].PostID != newest.ID {
+		t.Fatalf("GetPopular = %v, %v; want [liked newest]", got, err)
+	}
+	if got, _ := repos.Posts.GetPopular(ctx, bob, time.Now().Add(time.Hour), 2); len(got) != 0 {
+		t.Errorf("GetPopular of the future = %v; want empty", got)
+	}
+}
+
+func testSignals(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	carol := CreateUser(t, repos, "carol")
+
+	byAlice := CreatePost(t, repos, alice, "by alice")
+	byCarol := CreatePost(t, repos, carol, "by carol")
+	older := CreatePost(t, repos, alice, "older")
+	mustNoErr(t, repos.PostLikes.CreateLike(ctx, bob, older.ID))
+	mustNoErr(t, repos.PostComments.CreateComment(ctx, &models.PostComment{UserID: bob, PostID: older.ID, Comment: "nice"}))
+	mustNoErr(t, repos.PostComments.CreateComment(ctx, &models.PostComment{UserID: bob, PostID: older.ID, Comment: "really"}))
+	mustNoErr(t, repos.PostLikes.CreateLike(ctx, carol, byAlice.ID))
+
+	got, err := repos.Timelines.Signals(ctx, bob, []uuid.UUID{byAlice.ID, byCarol.ID, older.ID, uuid.New()}, time.Now().Add(-time.Hour))
+	if err != nil || len(got) != 3 {
+		t.Fatalf("Signals = %v, %v; want the three known posts", got, err)
+	}
+	// bob liked and commented on alice's posts three times, never on carol's.
+	if s := got[byAlice.ID]; s.RecentLikes != 1 || s.RecentComments != 0 || s.Affinity != 3 {
+		t.Errorf("Signals[byAlice] = %+v; want 1 like and affinity 3", s)
+	}
+	if s := got[older.ID]; s.RecentLikes != 1 || s.RecentComments != 2 || s.Affinity != 3 {
+		t.Errorf("Signals[older] = %+v; want 1 like, 2 comments and affinity 3", s)
+	}
+	if s := got[byCarol.ID]; s != (models.PostSignals{PostID: byCarol.ID}) {
+		t.Errorf("Signals[byCarol] = %+v; want no signals", s)
+	}
+
+	got, err = repos.Timelines.Signals(ctx, bob, []uuid.UUID{older.ID}, time.Now().Add(time.Hour))
+	if s := got[older.ID]; err != nil || s.RecentLikes != 0 || s.RecentComments != 0 || s.Affinity != 3 {
+		t.Errorf("Signals of the future = %+v, %v; want only affinity", s, err)
+	}
+}
+
+func testDeleteUserCascades(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+
+	alicePost := CreatePost(t, repos, alice, "by alice")
+	bobPost := CreatePost(t, repos, bob, "by bob")
+	CreateProfile(t, repos, alice, "Alice Example")
+
+	mustNoErr(t, repos.PostLikes.CreateLike(ctx, bob, alicePost.ID))
+	mustNoErr(t, repos.PostLikes.CreateLike(ctx, alice, bobPost.ID))
+	mustNoErr(t, repos.PostComments.CreateComment(ctx, &models.PostComment{UserID: bob, PostID: alicePost.ID, Comment: "on alice"}))
+	mustNoErr(t, repos.PostComments.CreateComment(ctx, &models.PostComment{UserID: alice, PostID: bobPost.ID, Comment: "on bob"}))
+	mustNoErr(t, repos.Follows.FollowUser(ctx, alice, bob))
+
+	if err := repos.Users.DeleteUser(ctx, alice.String()); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	if _, err := repos.Users.GetUserByID(ctx, alice.String()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetUserByID(deleted) error = %v; want sql.ErrNoRows", err)
+	}
+	if exists, _ := repos.PostComments.PostExists(ctx, alicePost.ID); exists {
+		t.Error("post of deleted user still exists")
+	}
+	if likes, _ := repos.PostLikes.GetLikes(ctx, bobPost.ID, pagination.First()); len(likes.Items) != 0 {
+		t.Errorf("likes by deleted user = %v; want none", likes)
+	}
+	if comments, _ := repos.PostComments.GetComments(ctx, bobPost.ID, pagination.First()); len(comments.Items) != 0 {
+		t.Errorf("comments by deleted user = %v; want none", comments)
+	}
+	if followers, _ := repos.Follows.GetFollowers(ctx, bob, pagination.First()); len(followers.Items) != 0 {
+		t.Errorf("followers of bob = %v; want none", followers)
+	}
+	if _, err := repos.UserProfiles.GetByUserID(ctx, alice.String()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("profile of deleted user error = %v; want sql.ErrNoRows", err)
+	}
+}
+
+func testUserProfiles(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	profile := CreateProfile(t, repos, alice, "Alice Example")
+
+	got, err := repos.UserProfiles.GetByUserID(ctx, alice.String())
+	if err != nil || got.ID != profile.ID || got.FullName != "Alice Example" || got.DefaultVisibility != models.VisibilityPublic {
+		t.Fatalf("GetByUserID = %+v, %v; want Alice's profile with public posts by default", got, err)
+	}
+	all, err := repos.UserProfiles.GetAll(ctx, pagination.First())
+	if err != nil || len(all.Items) != 1 {
+		t.Fatalf("GetAll = %v, %v; want 1 profile", all, err)
+	}
+
+	duplicate := *profile
+	duplicate.ID = uuid.New()
+	duplicate.UserID = bob
+	if err := repos.UserProfiles.Create(ctx, &duplicate); err == nil {
+		t.Error("Create with a duplicate email succeeded")
+	}
+
+	changes := *profile
+	changes.FullName = "Alice Renamed"
+	changes.UpdatedAt = time.Now()
+	updated, err := repos.UserProfiles.Update(ctx, alice.String(), &changes)
+	if err != nil || updated.FullName != "Alice Renamed" || updated.DefaultVisibility != models.VisibilityPublic {
+		t.Fatalf("Update = %+v, %v; want renamed profile keeping its default visibility", updated, err)
+	}
+	changes.DefaultVisibility = models.VisibilityFollowers
+	if updated, err := repos.UserProfiles.Update(ctx, alice.String(), &changes); err != nil || updated.DefaultVisibility != models.VisibilityFollowers {
+		t.Fatalf("Update of the default visibility = %+v, %v", updated, err)
+	}
+	if _, err := repos.UserProfiles.Update(ctx, bob.String(), &changes); err == nil {
+		t.Error("Update of a missing profile succeeded")
+	}
+
+	if err := repos.UserProfiles.Delete(ctx, alice.String()); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repos.UserProfiles.GetByUserID(ctx, alice.String()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetByUserID after Delete error = %v; want sql.ErrNoRows", err)
+	}
+}
+
+func testNotifications(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+
+	if err := repos.Notifications.Create(ctx, &models.Notification{ID: uuid.New(), RecipientUserID: alice}); err == nil {
+		t.Error("Create without sender succeeded")
+	}
+
+	older := &models.Notification{ID: uuid.New(), RecipientUserID: alice, SenderUserID: bob, Type: "follow", Message: "bob followed you"}
+	mustNoErr(t, repos.Notifications.Create(ctx, older))
+	tick()
+	newer := &models.Notification{ID: uuid.New(), RecipientUserID: alice, SenderUserID: bob, Type: "like", Message: "bob liked your post"}
+	mustNoErr(t, repos.Notifications.Create(ctx, newer))
+
+	got, err := repos.Notifications.GetByUserID(ctx, alice, pagination.First())
+	if err != nil || len(got.Items) != 2 || got.Items[0].ID != newer.ID || got.Items[1].ID != older.ID {
+		t.Fatalf("GetByUserID = %v, %v; want [newer older]", got, err)
+	}
+	if got, _ := repos.Notifications.GetByUserID(ctx, bob, pagination.First()); len(got.Items) != 0 {
+		t.Errorf("GetByUserID(bob) = %v; want empty", got)
+	}
+}
+
+func testDrafts(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+
+	if err := repos.Drafts.Create(ctx, &models.PostDraft{UserID: uuid.New(), PostContent: "ghost"}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Create for a missing user error = %v; want ErrNotFound", err)
+	}
+
+	now := time.Now()
+	past, later := now.Add(-time.Hour), now.Add(time.Hour)
+	draft := &models.PostDraft{UserID: alice, PostContent: "Thinking about it"}
+	mustNoErr(t, repos.Drafts.Create(ctx, draft))
+	if draft.ID == uuid.Nil || draft.CreatedAt.IsZero() {
+		t.Fatalf("Create left %+v without an ID or timestamps", draft)
+	}
+	tick()
+	due := &models.PostDraft{UserID: alice, PostContent: "Due", Visibility: models.VisibilityFollowers, PublishAt: &past}
+	mustNoErr(t, repos.Drafts.Create(ctx, due))
+	tick()
+	pending := &models.PostDraft{UserID: bob, PostContent: "Tomorrow", PublishAt: &later}
+	mustNoErr(t, repos.Drafts.Create(ctx, pending))
+
+	got, err := repos.Drafts.GetByID(ctx, due.ID)
+	if err != nil || got.PostContent != "Due" || got.Visibility != models.VisibilityFollowers || got.PublishAt == nil {
+		t.Fatalf("GetByID = %+v, %v", got, err)
+	}
+	drafts, err := repos.Drafts.GetByUserID(ctx, alice, false, pagination.First())
+	if err != nil || len(drafts.Items) != 1 || drafts.Items[0].ID != draft.ID {
+		t.Errorf("GetByUserID(drafts) = %v, %v; want [draft]", drafts, err)
+	}
+	scheduled, err := repos.Drafts.GetByUserID(ctx, alice, true, pagination.First())
+	if err != nil || len(scheduled.Items) != 1 || scheduled.Items[0].ID != due.ID {
+		t.Errorf("GetByUserID(scheduled) = %v, %v; want [due]", scheduled, err)
+	}
+
+	claimed, err := repos.Drafts.ClaimDue(ctx, now, nil)
+	if err != nil || claimed.ID != due.ID {
+		t.Fatalf("ClaimDue = %+v, %v; want the due post", claimed, err)
+	}
+	if _, err := repos.Drafts.ClaimDue(ctx, now, []uuid.UUID{due.ID}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("ClaimDue skipping the due post error = %v; want ErrNotFound", err)
+	}
+	if claimed, err := repos.Drafts.ClaimDue(ctx, later, []uuid.UUID{due.ID}); err != nil || claimed.ID != pending.ID {
+		t.Errorf("ClaimDue(later) = %+v, %v; want the pending post", claimed, err)
+	}
+
+	draft.PostContent, draft.PublishAt = "Decided", &later
+	updated, err := repos.Drafts.Update(ctx, draft)
+	if err != nil || updated.PostContent != "Decided" || updated.PublishAt == nil || updated.UserID != alice {
+		t.Fatalf("Update = %+v, %v; want a scheduled post", updated, err)
+	}
+	if _, err := repos.Drafts.Update(ctx, &models.PostDraft{ID: uuid.New(), PostContent: "x"}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Update of a missing draft error = %v; want ErrNotFound", err)
+	}
+
+	mustNoErr(t, repos.Drafts.Delete(ctx, due.ID))
+	if err := repos.Drafts.Delete(ctx, due.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Delete twice error = %v; want ErrNotFound", err)
+	}
+	if _, err := repos.Drafts.GetByID(ctx, due.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetByID after Delete error = %v; want ErrNotFound", err)
+	}
+
+	mustNoErr(t, repos.Users.DeleteUser(ctx, bob.String()))
+	if _, err := repos.Drafts.GetByID(ctx, pending.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("draft of a deleted user error = %v; want ErrNotFound", err)
+	}
+
+	// A publish time given with an offset is kept as the same instant.
+	at := now.Add(30 * time.Minute).In(time.FixedZone("EST", -5*60*60)).Truncate(time.Microsecond)
+	zoned := &models.PostDraft{UserID: alice, PostContent: "Zoned", PublishAt: &at}
+	mustNoErr(t, repos.Drafts.Create(ctx, zoned))
+	if got, err := repos.Drafts.GetByID(ctx, zoned.ID); err != nil || got.PublishAt == nil || !got.PublishAt.Equal(at) {
+		t.Errorf("GetByID(zoned) = %+v, %v; want publish_at %v", got, err, at)
+	}
+	if claimed, err := repos.Drafts.ClaimDue(ctx, at.Add(-time.Minute), nil); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("ClaimDue before the zoned publish time = %+v, %v; want ErrNotFound", claimed, err)
+	}
+	if claimed, err := repos.Drafts.ClaimDue(ctx, at.Add(time.Minute), nil); err != nil || claimed.ID != zoned.ID {
+		t.Errorf("ClaimDue after the zoned publish time = %+v, %v; want the zoned post", claimed, err)
+	}
+}
+
+func testJobs(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+
+	now := time.Now()
+	newJob := func(userID uuid.UUID, title string, createdAt time.Time) *models.JobPost {
+		return &models.JobPost{
+			UserID:          userID,
+			JobTitle:        title,
+			CompanyName:     "Grand Hotel",
+			JobDescription:  "Run the front desk",
+			Location:        "Mumbai",
+			PostDate:        createdAt,
+			LastDateToApply: createdAt.AddDate(0, 1, 0),
+			CreatedAt:       createdAt,
+		}
+	}
+
+	if err := repos.Jobs.CreateJobPost(ctx, newJob(uuid.New(), "orphan", now)); err == nil {
+		t.Error("CreateJobPost for unknown user succeeded")
+	}
+
+	older := newJob(alice, "Concierge", now.Add(-time.Hour))
+	newer := newJob(alice, "Night Auditor", now)
+	mustNoErr(t, repos.Jobs.CreateJobPost(ctx, older))
+	mustNoErr(t, repos.Jobs.CreateJobPost(ctx, newer))
+	if older.ID == uuid.Nil {
+		t.Error("CreateJobPost did not assign an ID")
+	}
+
+	jobs, err := repos.Jobs.GetAll(ctx, pagination.First())
+	if err != nil || len(jobs.Items) != 2 || jobs.Items[0].ID != newer.ID {
+		t.Fatalf("GetAll = %v, %v; want newest job first", jobs, err)
+	}
+	if jobs.NextCursor != "" {
+		t.Errorf("GetAll NextCursor = %q; want none on the last page", jobs.NextCursor)
+	}
+}
+
+// testPaging walks the job board one item at a time. Two of the jobs share a
+// timestamp, so the walk also covers the id tie-break of the cursor.
+func testPaging(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	want := map[uuid.UUID]bool{}
+	for i, createdAt := range []time.Time{now.Add(-time.Hour), now, now, now.Add(-2 * time.Hour)} {
+		job := &models.JobPost{
+			UserID:          alice,
+			JobTitle:        fmt.Sprintf("Job %d", i),
+			CompanyName:     "Grand Hotel",
+			JobDescription:  "Run the front desk",
+			Location:        "Mumbai",
+			PostDate:        createdAt,
+			LastDateToApply: createdAt.AddDate(0, 1, 0),
+			CreatedAt:       createdAt,
+		}
+		mustNoErr(t, repos.Jobs.CreateJobPost(ctx, job))
+		want[job.ID] = true
+	}
+
+	var (
+		seen []models.JobPost
+		page = pagination.Params{Limit: 1}
+	)
+	for {
+		got, err := repos.Jobs.GetAll(ctx, page)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(got.Items) > 1 {
+			t.Fatalf("GetAll returned %d items; want at most 1", len(got.Items))
+		}
+		seen = append(seen, got.Items...)
+		if got.NextCursor == "" {
+			break
+		}
+		if len(seen) > len(want) {
+			t.Fatalf("paging did not terminate after %d items", len(seen))
+		}
+		after, err := pagination.Decode(got.NextCursor)
+		if err != nil {
+			t.Fatalf("Decode(%q): %v", got.NextCursor, err)
+		}
+		page.After = &after
+	}
+
+	if len(seen) != len(want) {
+		t.Fatalf("paged through %d jobs; want %d", len(seen), len(want))
+	}
+	for i, job := range seen {
+		if !want[job.ID] {
+			t.Errorf("job %v seen twice or unknown", job.ID)
+		}
+		delete(want, job.ID)
+		if i > 0 && job.CreatedAt.After(seen[i-1].CreatedAt) {
+			t.Errorf("job %d is newer than job %d", i, i-1)
+		}
+	}
+}
+
+// testFollowerPaging walks the followers of a user one at a time, latest
+// follow first.
+func testFollowerPaging(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	bob := CreateUser(t, repos, "bob")
+
+	var want []uuid.UUID
+	for _, name := range []string{"alice", "carol", "dave"} {
+		follower := CreateUser(t, repos, name)
+		mustNoErr(t, repos.Follows.FollowUser(ctx, follower, bob))
+		want = append([]uuid.UUID{follower}, want...)
+		tick()
+	}
+
+	var (
+		seen []uuid.UUID
+		page = pagination.Params{Limit: 1}
+	)
+	for {
+		got, err := repos.Follows.GetFollowers(ctx, bob, page)
+		if err != nil {
+			t.Fatalf("GetFollowers: %v", err)
+		}
+		if len(got.Items) > 1 {
+			t.Fatalf("GetFollowers returned %d items; want at most 1", len(got.Items))
+		}
+		seen = append(seen, got.Items...)
+		if got.NextCursor == "" {
+			break
+		}
+		if len(seen) > len(want) {
+			t.Fatalf("paging did not terminate after %d items", len(seen))
+		}
+		after, err := pagination.Decode(got.NextCursor)
+		if err != nil {
+			t.Fatalf("Decode(%q): %v", got.NextCursor, err)
+		}
+		page.After = &after
+	}
+
+	if !slices.Equal(seen, want) {
+		t.Errorf("paged followers = %v; want %v", seen, want)
+	}
+}
+
+func testVideoProfiles(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+
+	video := &models.VideoProfile{UserID: alice, VideoURL: "https://cdn.example.com/a.mp4"}
+	mustNoErr(t, repos.VideoProfiles.Create(ctx, video))
+
+	videos, err := repos.VideoProfiles.GetByUserID(ctx, alice)
+	if err != nil || len(videos) != 1 || videos[0].ID != video.ID {
+		t.Fatalf("GetByUserID = %v, %v; want the created video", videos, err)
+	}
+
+	video.VideoURL = "https://cdn.example.com/b.mp4"
+	mustNoErr(t, repos.VideoProfiles.Update(ctx, video))
+	videos, _ = repos.VideoProfiles.GetByUserID(ctx, alice)
+	if videos[0].VideoURL != video.VideoURL {
+		t.Errorf("VideoURL = %q; want %q", videos[0].VideoURL, video.VideoURL)
+	}
+	if err := repos.VideoProfiles.Update(ctx, &models.VideoProfile{ID: uuid.New(), VideoURL: "x"}); err == nil {
+		t.Error("Update of unknown video succeeded")
+	}
+
+	mustNoErr(t, repos.VideoProfiles.Delete(ctx, video.ID))
+	if videos, _ := repos.VideoProfiles.GetByUserID(ctx, alice); len(videos) != 0 {
+		t.Errorf("GetByUserID after Delete = %v; want empty", videos)
+	}
+}
+
+func testUserEducation(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+
+	edu := &models.UserEducation{
+		UserID:          alice,
+		Degree:          "BSc",
+		InstitutionName: "IHM Mumbai",
+		FieldOfStudy:    "Hospitality",
+		Grade:           "A",
+		Year:            "2019",
+	}
+	mustNoErr(t, repos.UserEducation.Create(ctx, edu))
+
+	edu.Degree = "MSc"
+	mustNoErr(t, repos.UserEducation.Update(ctx, edu))
+	educations, err := repos.UserEducation.GetByUserID(ctx, alice)
+	if err != nil || len(educations) != 1 || educations[0].Degree != "MSc" {
+		t.Fatalf("GetByUserID = %v, %v; want the updated entry", educations, err)
+	}
+
+	mustNoErr(t, repos.UserEducation.Delete(ctx, edu.ID))
+	if educations, _ := repos.UserEducation.GetByUserID(ctx, alice); len(educations) != 0 {
+		t.Errorf("GetByUserID after Delete = %v; want empty", educations)
+	}
+}
+
+func testUserExperience(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+
+	exp := &models.UserExperience{
+		UserID:      alice,
+		JobTitle:    "Front Desk Agent",
+		CompanyName: "Grand Hotel",
+		StartDate:   "2020-01-01",
+		EndDate:     "2022-01-01",
+	}
+	mustNoErr(t, repos.UserExperience.Create(ctx, exp))
+
+	exp.JobTitle = "Front Desk Manager"
+	mustNoErr(t, repos.UserExperience.Update(ctx, exp))
+	experiences, err := repos.UserExperience.GetByUserID(ctx, alice)
+	if err != nil || len(experiences) != 1 || experiences[0].JobTitle != "Front Desk Manager" {
+		t.Fatalf("GetByUserID = %v, %v; want the updated entry", experiences, err)
+	}
+
+	mustNoErr(t, repos.UserExperience.Delete(ctx, exp.ID))
+	if experiences, _ := repos.UserExperience.GetByUserID(ctx, alice); len(experiences) != 0 {
+		t.Errorf("GetByUserID after Delete = %v; want empty", experiences)
+	}
+}
+
+func testTransactions(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	alice := CreateUser(t, repos, "alice")
+	bob := CreateUser(t, repos, "bob")
+	errBoom := errors.New("boom")
+
+	err := repos.Tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := repos.Follows.FollowUser(ctx, alice, bob); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("WithTx error = %v; want %v", err, errBoom)
+	}
+	if followers, _ := repos.Follows.GetFollowers(ctx, bob, pagination.First()); len(followers.Items) != 0 {
+		t.Fatalf("followers after rollback = %v; want none", followers)
+	}
+
+	// A nested WithTx joins the outer transaction, so the outer failure
+	// undoes the inner write too.
+	err = repos.Tx.WithTx(ctx, func(ctx context.Context) error {
+		mustNoErr(t, repos.Tx.WithTx(ctx, func(ctx context.Context) error {
+			return repos.Follows.FollowUser(ctx, bob, alice)
+		}))
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("nested WithTx error = %v; want %v", err, errBoom)
+	}
+	if followers, _ := repos.Follows.GetFollowers(ctx, alice, pagination.First()); len(followers.Items) != 0 {
+		t.Fatalf("followers after nested rollback = %v; want none", followers)
+	}
+
+	mustNoErr(t, repos.Tx.WithTx(ctx, func(ctx context.Context) error {
+		return repos.Follows.FollowUser(ctx, alice, bob)
+	}))
+	if followers, _ := repos.Follows.GetFollowers(ctx, bob, pagination.First()); len(followers.Items) != 1 {
+		t.Errorf("followers after commit = %v; want [alice]", followers)
+	}
+}
+
+func testRateLimits(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	// A rate this low cannot refill a token while the test runs.
+	limit := ratelimit.Limit{Rate: 1e-6, Burst: 2}
+
+	for i, want := range []int{1, 0} {
+		res, err := repos.RateLimits.Take(ctx, "test:a", limit)
+		mustNoErr(t, err)
+		if !res.Allowed || res.Remaining != want || res.Limit != 2 {
+			t.Fatalf("take %d = %+v; want allowed with %d remaining", i, res, want)
+		}
+	}
+
+	res, err := repos.RateLimits.Take(ctx, "test:a", limit)
+	mustNoErr(t, err)
+	if res.Allowed || res.RetryAfter <= 0 {
+		t.Fatalf("take over the limit = %+v; want rejected with a retry delay", res)
+	}
+
+	res, err = repos.RateLimits.Take(ctx, "test:b", limit)
+	mustNoErr(t, err)
+	if !res.Allowed {
+		t.Fatal("another key shares the bucket")
+	}
+
+	// Pruning only recent buckets keeps them, and their state.
+	mustNoErr(t, repos.RateLimits.Prune(ctx, time.Hour))
+	if res, _ := repos.RateLimits.Take(ctx, "test:a", limit); res.Allowed {
+		t.Error("pruning recent buckets refilled them")
+	}
+}
+
+func testIdempotency(t *testing.T, repos repositories.Repositories) {
+	ctx := context.Background()
+	store := repos.Idempotency
+	policy := idempotency.Policy{TTL: time.Hour, LockTimeout: time.Hour}
+
+	lock, rec, err := store.Reserve(ctx, "test:a", "fp", policy)
+	mustNoErr(t, err)
+	if rec != nil {
+		t.Fatalf("first reserve = %+v; want nil", rec)
+	}
+	_, rec, err = store.Reserve(ctx, "test:a", "other", policy)
+	mustNoErr(t, err)
+	if rec == nil || rec.Fingerprint != "fp" || rec.Response != nil {
+		t.Fatalf("reserve while running = %+v; want the running request", rec)
+	}
+
+	// A released key is free again.
+	mustNoErr(t, store.Release(ctx, lock))
+	lock, rec, err = store.Reserve(ctx, "test:a", "fp", policy)
+	mustNoErr(t, err)
+	if rec != nil {
+		t.Fatalf("reserve after release = %+v; want nil", rec)
+	}
+
+	want := idempotency.Response{Status: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)}
+	mustNoErr(t, store.Complete(ctx, lock, want))
+	mustNoErr(t, store.Release(ctx, lock))
+	mustNoErr(t, store.Prune(ctx))
+	_, rec, err = store.Reserve(ctx, "test:a", "fp", policy)
+	mustNoErr(t, err)
+	if rec == nil || rec.Response == nil || rec.Response.Status != want.Status ||
+		rec.Response.ContentType != want.ContentType || string(rec.Response.Body) != string(want.Body) {
+		t.Fatalf("reserve after completion = %+v; want the stored response", rec)
+	}
+
+	// A lock timeout of zero lets a retry take over a running request,
+	// after which the original can neither complete nor release the key.
+	stale, _, err := store.Reserve(ctx, "test:b", "fp", policy)
+	mustNoErr(t, err)
+	lock, rec, err = store.Reserve(ctx, "test:b", "fp", idempotency.Policy{TTL: time.Hour})
+	mustNoErr(t, err)
+	if rec != nil {
+		t.Fatalf("reserve of an abandoned key = %+v; want nil", rec)
+	}
+	if err := store.Complete(ctx, stale, want); !errors.Is(err, idempotency.ErrLockLost) {
+		t.Fatalf("complete after takeover = %v; want ErrLockLost", err)
+	}
+	if err := store.Release(ctx, stale); !errors.Is(err, idempotency.ErrLockLost) {
+		t.Fatalf("release after takeover = %v; want ErrLockLost", err)
+	}
+	_, rec, err = store.Reserve(ctx, "test:b", "fp", policy)
+	mustNoErr(t, err)
+	if rec == nil || rec.Response != nil {
+		t.Fatalf("reserve after stale release = %+v; want the running retry", rec)
+	}
+	mustNoErr(t, store.Complete(ctx, lock, want))
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
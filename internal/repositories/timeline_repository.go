@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+)
+
+// TimelineRepository stores the home timelines written when a post is
+// published ("fan-out on write") and reads home feeds from them. Authors
+// with more than maxFollowers followers are never fanned out; their posts
+// are read from content_post when a feed is requested ("fan-out on read").
+type TimelineRepository interface {
+	// FanOut adds post to the timelines of its author's followers unless
+	// the author has more than maxFollowers of them.
+	FanOut(ctx context.Context, post *models.ContentPost, maxFollowers int) error
+	// Backfill adds the latest limit posts of authorID to the timeline of
+	// userID unless the author has more than maxFollowers followers.
+	Backfill(ctx context.Context, userID, authorID uuid.UUID, maxFollowers, limit int) error
+	// Remove drops the posts of authorID from the timeline of userID.
+	Remove(ctx context.Context, userID, authorID uuid.UUID) error
+	// Home returns a page of the home feed of userID: the user's own posts,
+	// their timeline and the posts of the followed authors with more than
+	// maxFollowers followers.
+	Home(ctx context.Context, userID uuid.UUID, maxFollowers int, page pagination.Params) (pagination.Page[models.PostWithDetails], error)
+	// Signals returns the ranking signals of the given posts for viewerID,
+	// counting recent likes and comments since the given time.
+	Signals(ctx context.Context, viewerID uuid.UUID, postIDs []uuid.UUID, since time.Time) (map[uuid.UUID]models.PostSignals, error)
+}
+
+type timelineRepo struct {
+	DB *sql.DB
+}
+
+// NewTimelineRepository creates a Postgres-backed TimelineRepository.
+func NewTimelineRepository(db *sql.DB) TimelineRepository {
+	return &timelineRepo{DB: db}
+}
+
+func (r *timelineRepo) FanOut(ctx context.Context, post *models.ContentPost, maxFollowers int) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, `
+		INSERT INTO home_timeline (user_id, post_id, author_id)
+		SELECT f.follower_id, $1, $2
+		FROM followers f
+		WHERE f.followed_id = $2
+		  AND (SELECT COUNT(*) FROM followers WHERE followed_id = $2) <= $3
+		ON CONFLICT (user_id, post_id) DO NOTHING`, post.ID, post.UserID, maxFollowers)
+	return mapError(err)
+}
+
+func (r *timelineRepo) Backfill(ctx context.Context, userID, authorID uuid.UUID, maxFollowers, limit int) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, `
+		INSERT INTO home_timeline (user_id, post_id, author_id)
+		SELECT $1, cp.id, cp.user_id
+		FROM content_post cp
+		WHERE cp.user_id = $2
+		  AND (SELECT COUNT(*) FROM followers WHERE followed_id = $2) <= $3
+		ORDER BY cp.created_at DESC, cp.id DESC
+		LIMIT $4
+		ON CONFLICT (user_id, post_id) DO NOTHING`, userID, authorID, maxFollowers, limit)
+	return mapError(err)
+}
+
+func (r *timelineRepo) Remove(ctx context.Context, userID, authorID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, `
+		DELETE FROM home_timeline
+		WHERE user_id = $1 AND author_id = $2`, userID, authorID)
+	return mapError(err)
+}
+
+func (r *timelineRepo) Home(ctx context.Context, userID uuid.UUID, maxFollowers int, page pagination.Params) (pagination.Page[models.PostWithDetails], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("cp.created_at", "cp.id", 3)
+	query := postDetailsQuery + `
+		WHERE (
+			cp.user_id = $1
+			OR cp.id IN (SELECT post_id FROM home_timeline WHERE user_id = $1)
+			OR cp.user_id IN (
+				SELECT f.followed_id
+				FROM followings f
+				WHERE f.follower_id = $1
+				  AND (SELECT COUNT(*) FROM followers WHERE followed_id = f.followed_id) > $2
+			)
+		) AND ` + visibleTo(1) + ` AND ` + after + `
+		` + page.OrderBy("cp.created_at", "cp.id")
+
+	posts, err := queryPostDetails(ctx, r.DB, userID, query, append([]any{userID, maxFollowers}, args...)...)
+	if err != nil {
+		return pagination.Page[models.PostWithDetails]{}, err
+	}
+	return pagination.NewPage(posts, page, postCursor), nil
+}
+
+func (r *timelineRepo) Signals(ctx context.Context, viewerID uuid.UUID, postIDs []uuid.UUID, since time.Time) (map[uuid.UUID]models.PostSignals, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	ids := make([]string, len(postIDs))
+	for i, id := range postIDs {
+		ids[i] = id.String()
+	}
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, `
+		SELECT
+			cp.id,
+			(SELECT COUNT(*) FROM post_likes pl WHERE pl.post_id = cp.id AND pl.created_at >= $3),
+			(SELECT COUNT(*) FROM post_comments pc WHERE pc.post_id = cp.id AND pc.created_at >= $3),
+			(SELECT COUNT(*) FROM post_likes pl JOIN content_post ap ON ap.id = pl.post_id
+			  WHERE pl.user_id = $1 AND ap.user_id = cp.user_id)
+			+ (SELECT COUNT(*) FROM post_comments pc JOIN content_post ap ON ap.id = pc.post_id
+			  WHERE pc.user_id = $1 AND ap.user_id = cp.user_id)
+		FROM content_post cp
+		WHERE cp.id = ANY($2::uuid[])`, viewerID, pq.Array(ids), since)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	signals := make(map[uuid.UUID]models.PostSignals, len(postIDs))
+	for rows.Next() {
+		var s models.PostSignals
+		if err := rows.Scan(&s.PostID, &s.RecentLikes, &s.RecentComments, &s.Affinity); err != nil {
+			return nil, mapError(err)
+		}
+		signals[s.PostID] = s
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(err)
+	}
+	return signals, nil
+}
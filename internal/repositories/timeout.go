@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultQueryTimeout bounds every repository call. A caller's earlier
+// deadline still wins; a zero value disables the default.
+var DefaultQueryTimeout = 5 * time.Second
+
+// withQueryTimeout derives the context a repository method runs its queries
+// with, so a request that is cancelled or runs out of time also stops its
+// queries.
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if DefaultQueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, DefaultQueryTimeout)
+}
@@ -3,8 +3,8 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"errors"
-	"log"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
 )
 
 // TokenBlacklistRepository defines the methods to interact with the token blacklist storage.
@@ -18,32 +18,21 @@ type tokenBlacklistRepo struct {
 
 // NewTokenBlacklistRepository creates a new instance of TokenBlacklistRepository.
 func NewTokenBlacklistRepository(db *sql.DB) TokenBlacklistRepository {
-	if db == nil {
-		log.Fatal("Database connection cannot be nil")
-	}
 	return &tokenBlacklistRepo{DB: db}
 }
 
 // BlacklistToken adds a token to the token_blacklist table
 func (r *tokenBlacklistRepo) BlacklistToken(ctx context.Context, userID, token string) error {
-	log.Printf("BlacklistToken: Received userID: %s, token: %s", userID, token)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	if userID == "" {
-		log.Println("BlacklistToken: userID is empty")
-		return errors.New("user ID cannot be empty")
+		return apperrors.InvalidField("user_id", "is required")
 	}
 	if token == "" {
-		log.Println("BlacklistToken: token is empty")
-		return errors.New("token cannot be empty")
-	}
-
-	log.Printf("BlacklistToken: Blacklisting token for userID: %s", userID)
-	_, err := r.DB.ExecContext(ctx, `INSERT INTO token_blacklist (user_id, token) VALUES ($1, $2)`, userID, token)
-	if err != nil {
-		log.Printf("BlacklistToken: Error blacklisting token for userID: %s, error: %v", userID, err)
-		return errors.New("database error: " + err.Error())
+		return apperrors.InvalidField("token", "is required")
 	}
 
-	log.Printf("BlacklistToken: Token successfully blacklisted for userID: %s", userID)
-	return nil
+	_, err := conn(ctx, r.DB).ExecContext(ctx, `INSERT INTO token_blacklist (user_id, token) VALUES ($1, $2)`, userID, token)
+	return mapError(err)
 }
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+)
+
+// tracedConn starts a client span around every statement so a slow request
+// shows which queries it waited on. Statements only hold placeholders, so
+// their text is recorded as is; the arguments never are.
+type tracedConn struct {
+	DBTX
+}
+
+func (c tracedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+	defer span.End()
+
+	res, err := c.DBTX.ExecContext(ctx, query, args...)
+	tracing.RecordError(span, err)
+	return res, err
+}
+
+// QueryContext's span ends when the query returns, before its rows are read.
+func (c tracedConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startQuery(ctx, query)
+	defer span.End()
+
+	rows, err := c.DBTX.QueryContext(ctx, query, args...)
+	tracing.RecordError(span, err)
+	return rows, err
+}
+
+func (c tracedConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startQuery(ctx, query)
+	defer span.End()
+
+	row := c.DBTX.QueryRowContext(ctx, query, args...)
+	tracing.RecordError(span, row.Err())
+	return row
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := queryOperation(query)
+	return tracing.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(strings.TrimSpace(query)),
+		),
+	)
+}
+
+// queryOperation returns the SQL keyword a statement starts with, such as
+// SELECT or INSERT, which names its span.
+func queryOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+)
+
+// DBTX is the subset of *sql.DB and *sql.Tx used by the repositories, so the
+// same query code runs inside or outside a transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// TxManager runs a unit of work inside one transaction. Repository calls made
+// with the context handed to fn join that transaction; nested WithTx calls
+// join the outermost one. fn may run more than once when the transaction is
+// retried, so it must not have side effects outside the database.
+type TxManager interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+const (
+	defaultTxRetries = 3
+	txRetryBaseDelay = 10 * time.Millisecond
+)
+
+type txKey struct{}
+
+type sqlTxManager struct {
+	DB         *sql.DB
+	Isolation  sql.IsolationLevel
+	MaxRetries int
+}
+
+// NewTxManager creates a TxManager that runs SERIALIZABLE transactions and
+// retries them on serialization failures and deadlocks.
+func NewTxManager(db *sql.DB) TxManager {
+	return &sqlTxManager{
+		DB:         db,
+		Isolation:  sql.LevelSerializable,
+		MaxRetries: defaultTxRetries,
+	}
+}
+
+func (m *sqlTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := m.run(ctx, fn)
+		if err == nil || !isRetryable(err) || attempt >= m.MaxRetries {
+			return err
+		}
+
+		// Exponential backoff with jitter so competing transactions spread out.
+		delay := txRetryBaseDelay << attempt
+		delay += time.Duration(rand.Int63n(int64(delay)))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("retrying transaction: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (m *sqlTxManager) run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	ctx, span := tracing.Start(ctx, "db.transaction", trace.WithAttributes(semconv.DBSystemPostgreSQL))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	tx, err := m.DB.BeginTx(ctx, &sql.TxOptions{Isolation: m.Isolation})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// isRetryable reports whether err is a serialization failure or a deadlock,
+// both of which Postgres expects the client to retry.
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "40001" || pqErr.Code == "40P01"
+}
+
+// conn returns the transaction carried by ctx, or db when there is none.
+func conn(ctx context.Context, db *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tracedConn{tx}
+	}
+	return tracedConn{db}
+}
@@ -1,17 +1,34 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/google/uuid"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
 )
 
-type UserEducationRepository struct {
+// UserEducationRepository stores the education entries of a user profile.
+type UserEducationRepository interface {
+	Create(ctx context.Context, edu *models.UserEducation) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserEducation, error)
+	Update(ctx context.Context, edu *models.UserEducation) error
+	Delete(ctx context.Context, eduID uuid.UUID) error
+}
+
+type userEducationRepo struct {
 	DB *sql.DB
 }
 
-func (r *UserEducationRepository) Create(edu *models.UserEducation) error {
+// NewUserEducationRepository creates a Postgres-backed UserEducationRepository.
+func NewUserEducationRepository(db *sql.DB) UserEducationRepository {
+	return &userEducationRepo{DB: db}
+}
+
+func (r *userEducationRepo) Create(ctx context.Context, edu *models.UserEducation) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
         INSERT INTO user_education (
             id, user_id, degree, institution_name,
@@ -24,22 +41,26 @@ func (r *UserEducationRepository) Create(edu *models.UserEducation) error {
     `
 
 	edu.ID = uuid.New()
-	return r.DB.QueryRow(
+	err := conn(ctx, r.DB).QueryRowContext(ctx,
 		query,
 		edu.ID, edu.UserID, edu.Degree, edu.InstitutionName,
 		edu.FieldOfStudy, edu.Grade, edu.Year,
 	).Scan(&edu.CreatedAt, &edu.UpdatedAt)
+	return mapError(err)
 }
 
-func (r *UserEducationRepository) GetByUserID(userID uuid.UUID) ([]*models.UserEducation, error) {
+func (r *userEducationRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserEducation, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `SELECT id, user_id, degree, institution_name,
                      field_of_study, grade, year,
                      created_at, updated_at
               FROM user_education WHERE user_id = $1`
 
-	rows, err := r.DB.Query(query, userID)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, userID)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 	defer rows.Close()
 
@@ -52,7 +73,7 @@ func (r *UserEducationRepository) GetByUserID(userID uuid.UUID) ([]*models.UserE
 			&edu.CreatedAt, &edu.UpdatedAt,
 		)
 		if err != nil {
-			return nil, err
+			return nil, mapError(err)
 		}
 		educations = append(educations, &edu)
 	}
@@ -60,7 +81,10 @@ func (r *UserEducationRepository) GetByUserID(userID uuid.UUID) ([]*models.UserE
 	return educations, nil
 }
 
-func (r *UserEducationRepository) Update(edu *models.UserEducation) error {
+func (r *userEducationRepo) Update(ctx context.Context, edu *models.UserEducation) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
         UPDATE user_education
         SET degree = $1,
@@ -71,16 +95,19 @@ func (r *UserEducationRepository) Update(edu *models.UserEducation) error {
             updated_at = NOW()
         WHERE id = $6
     `
-	_, err := r.DB.Exec(
+	_, err := conn(ctx, r.DB).ExecContext(ctx,
 		query,
 		edu.Degree, edu.InstitutionName, edu.FieldOfStudy,
 		edu.Grade, edu.Year, edu.ID,
 	)
-	return err
+	return mapError(err)
 }
 
-func (r *UserEducationRepository) Delete(eduID uuid.UUID) error {
+func (r *userEducationRepo) Delete(ctx context.Context, eduID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `DELETE FROM user_education WHERE id = $1`
-	_, err := r.DB.Exec(query, eduID)
-	return err
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, eduID)
+	return mapError(err)
 }
@@ -8,43 +8,60 @@ import (
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
 )
 
-type UserExperienceRepository struct {
+// UserExperienceRepository stores the work history of a user profile.
+type UserExperienceRepository interface {
+	Create(ctx context.Context, exp *models.UserExperience) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.UserExperience, error)
+	Update(ctx context.Context, exp *models.UserExperience) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type userExperienceRepo struct {
 	DB *sql.DB
 }
 
-func NewUserExperienceRepository(db *sql.DB) *UserExperienceRepository {
-	return &UserExperienceRepository{DB: db}
+// NewUserExperienceRepository creates a Postgres-backed UserExperienceRepository.
+func NewUserExperienceRepository(db *sql.DB) UserExperienceRepository {
+	return &userExperienceRepo{DB: db}
 }
 
-func (r *UserExperienceRepository) Create(ctx context.Context, exp *models.UserExperience) error {
+func (r *userExperienceRepo) Create(ctx context.Context, exp *models.UserExperience) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		INSERT INTO user_experience (
 			id, user_id, job_title, company_name, location,
 			job_description, achievements, start_date, end_date
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9
+			$1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, '')::date
 		) RETURNING created_at, updated_at
 	`
 
 	exp.ID = uuid.New()
 
-	return r.DB.QueryRowContext(ctx, query,
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query,
 		exp.ID, exp.UserID, exp.JobTitle, exp.CompanyName, exp.Location,
 		exp.JobDescription, exp.Achievements, exp.StartDate, exp.EndDate,
 	).Scan(&exp.CreatedAt, &exp.UpdatedAt)
+	return mapError(err)
 }
 
-func (r *UserExperienceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.UserExperience, error) {
+func (r *userExperienceRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.UserExperience, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT id, user_id, job_title, company_name, location,
-			   job_description, achievements, start_date, end_date, created_at, updated_at
+			   job_description, achievements, to_char(start_date, 'YYYY-MM-DD'),
+			   COALESCE(to_char(end_date, 'YYYY-MM-DD'), ''), created_at, updated_at
 		FROM user_experience
 		WHERE user_id = $1
 	`
 
-	rows, err := r.DB.QueryContext(ctx, query, userID)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, userID)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 	defer rows.Close()
 
@@ -57,7 +74,7 @@ func (r *UserExperienceRepository) GetByUserID(ctx context.Context, userID uuid.
 			&exp.CreatedAt, &exp.UpdatedAt,
 		)
 		if err != nil {
-			return nil, err
+			return nil, mapError(err)
 		}
 		experiences = append(experiences, exp)
 	}
@@ -65,7 +82,10 @@ func (r *UserExperienceRepository) GetByUserID(ctx context.Context, userID uuid.
 	return experiences, nil
 }
 
-func (r *UserExperienceRepository) Update(ctx context.Context, exp *models.UserExperience) error {
+func (r *userExperienceRepo) Update(ctx context.Context, exp *models.UserExperience) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		UPDATE user_experience SET
 			job_title = $1,
@@ -74,21 +94,24 @@ func (r *UserExperienceRepository) Update(ctx context.Context, exp *models.UserE
 			job_description = $4,
 			achievements = $5,
 			start_date = $6,
-			end_date = $7,
+			end_date = NULLIF($7, '')::date,
 			updated_at = NOW()
 		WHERE id = $8
 	`
-	_, err := r.DB.ExecContext(ctx, query,
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query,
 		exp.JobTitle, exp.CompanyName, exp.Location,
 		exp.JobDescription, exp.Achievements,
 		exp.StartDate, exp.EndDate,
 		exp.ID,
 	)
-	return err
+	return mapError(err)
 }
 
-func (r *UserExperienceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *userExperienceRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `DELETE FROM user_experience WHERE id = $1`
-	_, err := r.DB.ExecContext(ctx, query, id)
-	return err
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, id)
+	return mapError(err)
 }
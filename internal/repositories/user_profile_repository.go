@@ -1,30 +1,47 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"log"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 )
 
-type UserProfileRepository struct {
+// UserProfileRepository stores public user profiles.
+type UserProfileRepository interface {
+	Create(ctx context.Context, profile *models.UserProfile) error
+	GetByUserID(ctx context.Context, userID string) (*models.UserProfile, error)
+	GetAll(ctx context.Context, page pagination.Params) (pagination.Page[*models.UserProfile], error)
+	// Update replaces a profile; an empty DefaultVisibility keeps the
+	// current one.
+	Update(ctx context.Context, userID string, updated *models.UserProfile) (*models.UserProfile, error)
+	Delete(ctx context.Context, userID string) error
+}
+
+type userProfileRepo struct {
 	DB *sql.DB
 }
 
-func NewUserProfileRepository(db *sql.DB) *UserProfileRepository {
-	return &UserProfileRepository{DB: db}
+// NewUserProfileRepository creates a Postgres-backed UserProfileRepository.
+func NewUserProfileRepository(db *sql.DB) UserProfileRepository {
+	return &userProfileRepo{DB: db}
 }
 
-func (r *UserProfileRepository) Create(profile *models.UserProfile) error {
+func (r *userProfileRepo) Create(ctx context.Context, profile *models.UserProfile) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
                 INSERT INTO user_profile (
                         id, user_id, profile_image, full_name, designation, organization,
-                        professional_summary, location, email, contact_number, created_at, updated_at
-                ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+                        professional_summary, location, email, contact_number, default_visibility,
+                        created_at, updated_at
+                ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,COALESCE(NULLIF($11, ''), 'public'),$12,$13)
         `
-	_, err := r.DB.Exec(query,
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query,
 		profile.ID,
 		profile.UserID,
 		profile.ProfileImage,
@@ -35,21 +52,22 @@ func (r *UserProfileRepository) Create(profile *models.UserProfile) error {
 		profile.Location,
 		profile.Email,
 		profile.ContactNumber,
+		profile.DefaultVisibility,
 		profile.CreatedAt,
 		profile.UpdatedAt,
 	)
-	if err != nil {
-		println("DB Insert Error:", err.Error()) // ← helpful
-	}
-	return err
+	return mapError(err)
 }
 
-func (r *UserProfileRepository) GetByUserID(userID string) (*models.UserProfile, error) {
+func (r *userProfileRepo) GetByUserID(ctx context.Context, userID string) (*models.UserProfile, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `SELECT id, user_id, profile_image, full_name, designation, organization,
                           professional_summary, location, email, contact_number,
-                          created_at, updated_at FROM user_profile WHERE user_id = $1`
+                          default_visibility, created_at, updated_at FROM user_profile WHERE user_id = $1`
 
-	row := r.DB.QueryRow(query, userID)
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, userID)
 
 	var profile models.UserProfile
 	err := row.Scan(
@@ -63,25 +81,31 @@ func (r *UserProfileRepository) GetByUserID(userID string) (*models.UserProfile,
 		&profile.Location,
 		&profile.Email,
 		&profile.ContactNumber,
+		&profile.DefaultVisibility,
 		&profile.CreatedAt,
 		&profile.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return &profile, nil
 }
 
-func (r *UserProfileRepository) GetAll() ([]*models.UserProfile, error) {
+func (r *userProfileRepo) GetAll(ctx context.Context, page pagination.Params) (pagination.Page[*models.UserProfile], error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	after, args := page.Where("created_at", "id", 1)
 	query := `SELECT id, user_id, profile_image, full_name, designation, organization,
                      professional_summary, location, email, contact_number,
-                     created_at, updated_at FROM user_profile`
+                     default_visibility, created_at, updated_at FROM user_profile
+              WHERE ` + after + `
+              ` + page.OrderBy("created_at", "id")
 
-	rows, err := r.DB.Query(query)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, args...)
 	if err != nil {
-		log.Printf("DB query error: %v", err)
-		return nil, err
+		return pagination.Page[*models.UserProfile]{}, mapError(err)
 	}
 	defer rows.Close()
 
@@ -100,6 +124,7 @@ func (r *UserProfileRepository) GetAll() ([]*models.UserProfile, error) {
 			location            sql.NullString
 			email               sql.NullString
 			contactNumber       sql.NullString
+			defaultVisibility   string
 
 			createdAt time.Time
 			updatedAt time.Time
@@ -116,24 +141,22 @@ func (r *UserProfileRepository) GetAll() ([]*models.UserProfile, error) {
 			&location,
 			&email,
 			&contactNumber,
+			&defaultVisibility,
 			&createdAt,
 			&updatedAt,
 		)
 		if err != nil {
-			log.Printf("Row scan error: %v", err)
-			return nil, err
+			return pagination.Page[*models.UserProfile]{}, mapError(err)
 		}
 
 		id, err := uuid.Parse(idStr)
 		if err != nil {
-			log.Printf("UUID parse error for ID: %v", err)
-			return nil, err
+			return pagination.Page[*models.UserProfile]{}, mapError(err)
 		}
 
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			log.Printf("UUID parse error for UserID: %v", err)
-			return nil, err
+			return pagination.Page[*models.UserProfile]{}, mapError(err)
 		}
 
 		profile := &models.UserProfile{
@@ -147,6 +170,7 @@ func (r *UserProfileRepository) GetAll() ([]*models.UserProfile, error) {
 			Location:            nullToString(location),              // *string
 			Email:               derefString(nullToString(email)),    // string
 			ContactNumber:       nullToString(contactNumber),         // *string
+			DefaultVisibility:   defaultVisibility,
 			CreatedAt:           createdAt,
 			UpdatedAt:           updatedAt,
 		}
@@ -155,11 +179,14 @@ func (r *UserProfileRepository) GetAll() ([]*models.UserProfile, error) {
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Printf("Rows iteration error: %v", err)
-		return nil, err
+		return pagination.Page[*models.UserProfile]{}, mapError(err)
 	}
 
-	return profiles, nil
+	return pagination.NewPage(profiles, page, profileCursor), nil
+}
+
+func profileCursor(p *models.UserProfile) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
 }
 
 // Helper function to convert sql.NullString to plain string
@@ -177,7 +204,10 @@ func derefString(s *string) string {
 	return *s
 }
 
-func (r *UserProfileRepository) Update(userID string, updated *models.UserProfile) (*models.UserProfile, error) {
+func (r *userProfileRepo) Update(ctx context.Context, userID string, updated *models.UserProfile) (*models.UserProfile, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		UPDATE user_profile SET
 			profile_image = $1,
@@ -188,12 +218,14 @@ func (r *UserProfileRepository) Update(userID string, updated *models.UserProfil
 			location = $6,
 			email = $7,
 			contact_number = $8,
-			updated_at = $9
-		WHERE user_id = $10
+			default_visibility = COALESCE(NULLIF($9, ''), default_visibility),
+			updated_at = $10
+		WHERE user_id = $11
 		RETURNING id, user_id, profile_image, full_name, designation, organization,
-				  professional_summary, location, email, contact_number, created_at, updated_at`
+				  professional_summary, location, email, contact_number, default_visibility,
+				  created_at, updated_at`
 
-	row := r.DB.QueryRow(query,
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query,
 		updated.ProfileImage,
 		updated.FullName,
 		updated.Designation,
@@ -202,6 +234,7 @@ func (r *UserProfileRepository) Update(userID string, updated *models.UserProfil
 		updated.Location,
 		updated.Email,
 		updated.ContactNumber,
+		updated.DefaultVisibility,
 		updated.UpdatedAt,
 		userID,
 	)
@@ -218,18 +251,22 @@ func (r *UserProfileRepository) Update(userID string, updated *models.UserProfil
 		&profile.Location,
 		&profile.Email,
 		&profile.ContactNumber,
+		&profile.DefaultVisibility,
 		&profile.CreatedAt,
 		&profile.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return &profile, nil
 }
 
-func (r *UserProfileRepository) Delete(userID string) error {
+func (r *userProfileRepo) Delete(ctx context.Context, userID string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `DELETE FROM user_profile WHERE user_id = $1`
-	_, err := r.DB.Exec(query, userID)
-	return err
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, userID)
+	return mapError(err)
 }
@@ -1,32 +1,53 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/google/uuid"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
 )
 
-type VideoProfileRepository struct {
+// VideoProfileRepository stores the video introductions attached to a profile.
+type VideoProfileRepository interface {
+	Create(ctx context.Context, video *models.VideoProfile) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.VideoProfile, error)
+	Update(ctx context.Context, video *models.VideoProfile) error
+	Delete(ctx context.Context, videoID uuid.UUID) error
+}
+
+type videoProfileRepo struct {
 	DB *sql.DB
 }
 
-func (r *VideoProfileRepository) Create(video *models.VideoProfile) error {
+// NewVideoProfileRepository creates a Postgres-backed VideoProfileRepository.
+func NewVideoProfileRepository(db *sql.DB) VideoProfileRepository {
+	return &videoProfileRepo{DB: db}
+}
+
+func (r *videoProfileRepo) Create(ctx context.Context, video *models.VideoProfile) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
         INSERT INTO video_profile (id, user_id, video_url)
         VALUES ($1, $2, $3)
         RETURNING created_at, updated_at
     `
 	video.ID = uuid.New()
-	return r.DB.QueryRow(query, video.ID, video.UserID, video.VideoURL).
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, video.ID, video.UserID, video.VideoURL).
 		Scan(&video.CreatedAt, &video.UpdatedAt)
+	return mapError(err)
 }
 
-func (r *VideoProfileRepository) GetByUserID(userID uuid.UUID) ([]*models.VideoProfile, error) {
+func (r *videoProfileRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.VideoProfile, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `SELECT id, user_id, video_url, created_at, updated_at FROM video_profile WHERE user_id = $1`
-	rows, err := r.DB.Query(query, userID)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, userID)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 	defer rows.Close()
 
@@ -35,25 +56,32 @@ func (r *VideoProfileRepository) GetByUserID(userID uuid.UUID) ([]*models.VideoP
 		var v models.VideoProfile
 		err := rows.Scan(&v.ID, &v.UserID, &v.VideoURL, &v.CreatedAt, &v.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return nil, mapError(err)
 		}
 		profiles = append(profiles, &v)
 	}
 	return profiles, nil
 }
 
-func (r *VideoProfileRepository) Update(video *models.VideoProfile) error {
+func (r *videoProfileRepo) Update(ctx context.Context, video *models.VideoProfile) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
         UPDATE video_profile
         SET video_url = $1, updated_at = NOW()
         WHERE id = $2
         RETURNING updated_at
     `
-	return r.DB.QueryRow(query, video.VideoURL, video.ID).Scan(&video.UpdatedAt)
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, video.VideoURL, video.ID).Scan(&video.UpdatedAt)
+	return mapError(err)
 }
 
-func (r *VideoProfileRepository) Delete(videoID uuid.UUID) error {
+func (r *videoProfileRepo) Delete(ctx context.Context, videoID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `DELETE FROM video_profile WHERE id = $1`
-	_, err := r.DB.Exec(query, videoID)
-	return err
+	_, err := conn(ctx, r.DB).ExecContext(ctx, query, videoID)
+	return mapError(err)
 }
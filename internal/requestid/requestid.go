@@ -0,0 +1,30 @@
+// Package requestid carries the ID of the HTTP request being served through
+// a context, so errors and log lines can be correlated with it.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header that carries the request ID in both directions.
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+// New returns a fresh request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -2,32 +2,54 @@ package services
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
-	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 	"github.com/sagar-rathod-devops/do-host-network-backend/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var otpLength = 6 // Global variable for OTP length
 
+// Errors returned by AuthService.
+var (
+	ErrUserExists         = apperrors.Conflict("user_exists", "A user with this email or username already exists")
+	ErrInvalidCredentials = apperrors.Unauthorized("invalid_credentials", "Invalid email/username or password")
+	ErrEmailNotVerified   = apperrors.Forbidden("email_not_verified", "Email not verified. Please verify your email before logging in")
+	ErrOTPNotFound        = apperrors.NotFound("otp_not_found", "OTP not found or expired")
+	ErrInvalidOTP         = apperrors.InvalidField("otp", "The OTP is not valid")
+	ErrEmailNotRegistered = apperrors.NotFound("user_not_found", "No user registered with this email")
+)
+
+// Mailer delivers transactional email such as OTP codes.
+type Mailer interface {
+	SendEmail(to, subject, body string) error
+}
+
 type AuthService struct {
-	DB                  *sql.DB
+	Tx                  repositories.TxManager
 	UserRepository      repositories.UserRepository
 	OTPRepository       repositories.OTPRepository
+	TokenSecret         string
 	TokenExpiration     time.Duration
 	OTPLifespan         time.Duration
 	BlacklistRepository repositories.TokenBlacklistRepository
-	// Config              config.Config
+	Mailer              Mailer
+	Metrics             *metrics.Metrics
 }
 
 // RegisterUserWithOTP handles the registration of a new user and sends an OTP.
 func (s *AuthService) RegisterUserWithOTP(ctx context.Context, email, username, password string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.RegisterUserWithOTP")
+	defer span.End()
+
 	// Hash the password
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
@@ -43,12 +65,6 @@ func (s *AuthService) RegisterUserWithOTP(ctx context.Context, email, username,
 		UpdatedAt:    time.Now(),
 	}
 
-	// Save the user in the database
-	err = s.UserRepository.CreateUser(ctx, user)
-	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
-	}
-
 	// Generate OTP
 	otp := utils.GenerateOTP(otpLength)
 
@@ -60,11 +76,24 @@ func (s *AuthService) RegisterUserWithOTP(ctx context.Context, email, username,
 		CreatedAt:  time.Now(),
 	}
 
-	// Save OTP to the database
-	err = s.OTPRepository.SaveOTP(ctx, otpRecord)
+	// Save the user and the OTP together so a failure cannot leave an
+	// account without a code to verify it
+	err = s.Tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.UserRepository.CreateUser(ctx, user); err != nil {
+			if errors.Is(err, apperrors.ErrConflict) {
+				return ErrUserExists
+			}
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		if err := s.OTPRepository.SaveOTP(ctx, otpRecord); err != nil {
+			return fmt.Errorf("failed to save OTP: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to save OTP: %w", err)
+		return err
 	}
+	s.Metrics.Registered()
 
 	// Send OTP email to the user
 	subject := "Verify Your Email Address with Do Host Network"
@@ -81,16 +110,38 @@ If you did not request this email, please contact our support team or ignore thi
 Best regards,
 The Do Host Network Team`, otp)
 
-	err = utils.SendEmail(email, subject, body)
+	err = s.Mailer.SendEmail(email, subject, body)
 	if err != nil {
 		return fmt.Errorf("failed to send OTP email: %w", err)
 	}
 
-	fmt.Println("Registration and OTP email sent successfully to", email)
+	logging.FromContext(ctx).InfoContext(ctx, "registration OTP sent", "email", email)
 	return nil
 }
 
 func (s *AuthService) LoginUser(ctx context.Context, identifier, password string) (string, string, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.LoginUser")
+	defer span.End()
+
+	token, userID, err := s.login(ctx, identifier, password)
+	s.Metrics.LoginAttempted(loginOutcome(err))
+	return token, userID, err
+}
+
+func loginOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, ErrInvalidCredentials):
+		return "invalid_credentials"
+	case errors.Is(err, ErrEmailNotVerified):
+		return "email_not_verified"
+	default:
+		return "error"
+	}
+}
+
+func (s *AuthService) login(ctx context.Context, identifier, password string) (string, string, error) {
 	// Step 1: Check if OTP is verified
 	otpRecord, err := s.OTPRepository.GetOTPByEmail(ctx, identifier)
 	if err != nil {
@@ -98,27 +149,25 @@ func (s *AuthService) LoginUser(ctx context.Context, identifier, password string
 		otpRecord = nil
 	}
 	if otpRecord != nil && !otpRecord.IsVerified {
-		return "", "", errors.New("email not verified. Please verify your email before logging in")
+		return "", "", ErrEmailNotVerified
 	}
 
 	// Step 2: Fetch user details from repository using email or username
-	user, err := s.UserRepository.GetUserByEmailOrUsername(identifier)
+	user, err := s.UserRepository.GetUserByEmailOrUsername(ctx, identifier)
 	if err != nil {
-		return "", "", errors.New("invalid email/username or password")
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return "", "", ErrInvalidCredentials
+		}
+		return "", "", err
 	}
 
 	// Step 3: Compare hashed password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", "", errors.New("invalid email/username or password")
-	}
-
-	// Step 4: Load config and generate JWT
-	cfg, err := config.LoadConfig(".")
-	if err != nil {
-		return "", "", fmt.Errorf("failed to load config: %w", err)
+		return "", "", ErrInvalidCredentials
 	}
 
-	token, err := utils.GenerateToken(24*time.Hour, user.ID, cfg.TokenSecret)
+	// Step 4: Generate JWT
+	token, err := utils.GenerateToken(24*time.Hour, user.ID, s.TokenSecret)
 	if err != nil {
 		return "", "", err
 	}
@@ -145,13 +194,19 @@ func (s *AuthService) LoginUser(ctx context.Context, identifier, password string
 
 // VerifyOTP handles OTP verification
 func (s *AuthService) VerifyOTP(ctx context.Context, email, otp string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.VerifyOTP")
+	defer span.End()
+
 	storedOTP, err := s.OTPRepository.GetOTPByEmail(ctx, email)
 	if err != nil {
-		return errors.New("OTP not found or expired")
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return ErrOTPNotFound
+		}
+		return err
 	}
 
 	if storedOTP.OTP != otp {
-		return errors.New("invalid OTP")
+		return ErrInvalidOTP
 	}
 
 	return s.OTPRepository.MarkUserVerified(ctx, email)
@@ -159,11 +214,14 @@ func (s *AuthService) VerifyOTP(ctx context.Context, email, otp string) error {
 
 // ForgotPassword generates an OTP for password reset and sends an email
 func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.ForgotPassword")
+	defer span.End()
+
 	// Step 1: Check if user exists
-	user, err := s.UserRepository.GetUserByEmail(email)
+	user, err := s.UserRepository.GetUserByEmail(ctx, email)
 	if err != nil {
-		if err.Error() == "user not found" {
-			return fmt.Errorf("no user registered with this email")
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return ErrEmailNotRegistered
 		}
 		return fmt.Errorf("failed to check user: %w", err)
 	}
@@ -198,17 +256,20 @@ This OTP is valid for the next 10 minutes. If you did not request this password
 Best regards,
 The Do Host Network Team`, user.Username, otp)
 
-	err = utils.SendEmail(user.Email, subject, body)
+	err = s.Mailer.SendEmail(user.Email, subject, body)
 	if err != nil {
 		return fmt.Errorf("failed to send OTP email: %w", err)
 	}
 
-	fmt.Println("Password reset email sent successfully to", user.Email)
+	logging.FromContext(ctx).InfoContext(ctx, "password reset OTP sent", "email", user.Email)
 	return nil
 }
 
 // ResetPassword resets the user's password
 func (s *AuthService) ResetPassword(ctx context.Context, email, otp, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.ResetPassword")
+	defer span.End()
+
 	if err := s.VerifyOTP(ctx, email, otp); err != nil {
 		return err
 	}
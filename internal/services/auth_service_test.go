@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+	"github.com/sagar-rathod-devops/do-host-network-backend/utils"
+)
+
+const testTokenSecret = "test-secret"
+
+type sentEmail struct {
+	To, Subject, Body string
+}
+
+// fakeMailer records every email instead of talking to SMTP.
+type fakeMailer struct {
+	sent []sentEmail
+}
+
+func (m *fakeMailer) SendEmail(to, subject, body string) error {
+	m.sent = append(m.sent, sentEmail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+var otpPattern = regexp.MustCompile(`Your OTP is: (\d+)`)
+
+// lastOTP extracts the code from the most recent email.
+func (m *fakeMailer) lastOTP(t *testing.T) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatal("no email sent")
+	}
+	match := otpPattern.FindStringSubmatch(m.sent[len(m.sent)-1].Body)
+	if match == nil {
+		t.Fatalf("no OTP in email body %q", m.sent[len(m.sent)-1].Body)
+	}
+	return match[1]
+}
+
+func newAuthService() (*services.AuthService, *fakeMailer) {
+	repos := memory.NewRepositories()
+	mailer := &fakeMailer{}
+	return &services.AuthService{
+		Tx:             repos.Tx,
+		UserRepository: repos.Users,
+		OTPRepository:  repos.OTPs,
+		Mailer:         mailer,
+		TokenSecret:    testTokenSecret,
+	}, mailer
+}
+
+func TestAuthServiceRegisterVerifyLogin(t *testing.T) {
+	ctx := context.Background()
+	auth, mailer := newAuthService()
+
+	if err := auth.RegisterUserWithOTP(ctx, "alice@example.com", "alice", "s3cret!"); err != nil {
+		t.Fatalf("RegisterUserWithOTP: %v", err)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].To != "alice@example.com" {
+		t.Fatalf("sent = %+v; want one email to alice", mailer.sent)
+	}
+
+	if _, _, err := auth.LoginUser(ctx, "alice@example.com", "s3cret!"); err == nil {
+		t.Fatal("LoginUser before OTP verification succeeded")
+	}
+
+	if err := auth.VerifyOTP(ctx, "alice@example.com", mailer.lastOTP(t)); err != nil {
+		t.Fatalf("VerifyOTP: %v", err)
+	}
+
+	token, userID, err := auth.LoginUser(ctx, "alice@example.com", "s3cret!")
+	if err != nil {
+		t.Fatalf("LoginUser: %v", err)
+	}
+	sub, err := utils.ValidateToken(token, testTokenSecret)
+	if err != nil || sub != userID {
+		t.Errorf("token subject = %v, %v; want %s", sub, err, userID)
+	}
+
+	if _, _, err := auth.LoginUser(ctx, "alice", "wrong"); err == nil {
+		t.Error("LoginUser with wrong password succeeded")
+	}
+}
+
+func TestAuthServiceRegisterDuplicateEmail(t *testing.T) {
+	ctx := context.Background()
+	auth, _ := newAuthService()
+
+	if err := auth.RegisterUserWithOTP(ctx, "alice@example.com", "alice", "pw"); err != nil {
+		t.Fatalf("RegisterUserWithOTP: %v", err)
+	}
+	if err := auth.RegisterUserWithOTP(ctx, "alice@example.com", "alice2", "pw"); err == nil {
+		t.Fatal("second registration with the same email succeeded")
+	}
+}
+
+// failingOTPs is an OTPRepository whose SaveOTP always fails.
+type failingOTPs struct {
+	repositories.OTPRepository
+}
+
+func (failingOTPs) SaveOTP(context.Context, models.OTP) error {
+	return errors.New("otp store unavailable")
+}
+
+func TestAuthServiceRegisterRollsBackUserWhenOTPFails(t *testing.T) {
+	ctx := context.Background()
+	auth, mailer := newAuthService()
+	auth.OTPRepository = failingOTPs{auth.OTPRepository}
+
+	if err := auth.RegisterUserWithOTP(ctx, "alice@example.com", "alice", "pw"); err == nil {
+		t.Fatal("RegisterUserWithOTP succeeded with a failing OTP store")
+	}
+	if len(mailer.sent) != 0 {
+		t.Errorf("sent %d emails for a failed registration; want 0", len(mailer.sent))
+	}
+	if _, err := auth.UserRepository.GetUserByEmail(ctx, "alice@example.com"); err == nil {
+		t.Error("user was kept after the registration was rolled back")
+	}
+}
+
+func TestAuthServiceVerifyOTPRejectsWrongCode(t *testing.T) {
+	ctx := context.Background()
+	auth, mailer := newAuthService()
+
+	if err := auth.RegisterUserWithOTP(ctx, "alice@example.com", "alice", "pw"); err != nil {
+		t.Fatalf("RegisterUserWithOTP: %v", err)
+	}
+	wrong := "0" + mailer.lastOTP(t)
+	if err := auth.VerifyOTP(ctx, "alice@example.com", wrong); err == nil {
+		t.Fatal("VerifyOTP accepted a wrong code")
+	}
+}
+
+func TestAuthServiceResetPassword(t *testing.T) {
+	ctx := context.Background()
+	auth, mailer := newAuthService()
+
+	if err := auth.RegisterUserWithOTP(ctx, "alice@example.com", "alice", "old-password"); err != nil {
+		t.Fatalf("RegisterUserWithOTP: %v", err)
+	}
+	if err := auth.ForgotPassword(ctx, "nobody@example.com"); err == nil {
+		t.Error("ForgotPassword for an unknown email succeeded")
+	}
+
+	if err := auth.ForgotPassword(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	if err := auth.ResetPassword(ctx, "alice@example.com", mailer.lastOTP(t), "new-password"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	if _, _, err := auth.LoginUser(ctx, "alice", "new-password"); err != nil {
+		t.Errorf("LoginUser with new password: %v", err)
+	}
+	if _, _, err := auth.LoginUser(ctx, "alice", "old-password"); err == nil {
+		t.Error("LoginUser with old password succeeded")
+	}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+)
+
+var (
+	// ErrDraftNotFound is returned when a draft does not exist or belongs
+	// to another user.
+	ErrDraftNotFound = apperrors.NotFound("draft_not_found", "Draft not found")
+	// ErrPublishAtInPast is returned when a post is scheduled for a time
+	// that has already passed.
+	ErrPublishAtInPast = apperrors.InvalidField("publish_at", "must be in the future")
+)
+
+// NotificationPostPublished tells authors their scheduled post was published.
+const NotificationPostPublished = "post_published"
+
+// defaultPublishBatch is how many scheduled posts PublishDue publishes per
+// run when DraftService.BatchSize is not set.
+const defaultPublishBatch = 100
+
+// DraftService keeps the drafts and scheduled posts of users and publishes
+// the scheduled ones when they are due.
+type DraftService struct {
+	Repo  repositories.DraftRepository
+	Posts *PostService
+	Tx    repositories.TxManager
+
+	// Notifications, when set, tells authors their scheduled posts were
+	// published.
+	Notifications repositories.NotificationRepository
+
+	// BatchSize bounds the posts published by one PublishDue run;
+	// defaultPublishBatch when zero.
+	BatchSize int
+
+	// Now returns the current time; time.Now when nil.
+	Now func() time.Time
+}
+
+func (s *DraftService) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+func (s *DraftService) batchSize() int {
+	if s.BatchSize > 0 {
+		return s.BatchSize
+	}
+	return defaultPublishBatch
+}
+
+// SaveDraft stores a draft, or a scheduled post when it has a PublishAt.
+func (s *DraftService) SaveDraft(ctx context.Context, draft *models.PostDraft) (*models.PostDraft, error) {
+	ctx, span := tracing.Start(ctx, "DraftService.SaveDraft")
+	defer span.End()
+
+	if draft.PublishAt != nil && !draft.PublishAt.After(s.now()) {
+		return nil, ErrPublishAtInPast
+	}
+	if err := s.Repo.Create(ctx, draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// GetDraft returns a draft of userID.
+func (s *DraftService) GetDraft(ctx context.Context, userID, draftID uuid.UUID) (*models.PostDraft, error) {
+	ctx, span := tracing.Start(ctx, "DraftService.GetDraft")
+	defer span.End()
+
+	draft, err := s.Repo.GetByID(ctx, draftID)
+	if errors.Is(err, apperrors.ErrNotFound) || (err == nil && draft.UserID != userID) {
+		return nil, ErrDraftNotFound
+	}
+	return draft, err
+}
+
+// ListDrafts returns a page of the drafts of userID, or of their scheduled
+// posts when scheduled is set, newest first.
+func (s *DraftService) ListDrafts(ctx context.Context, userID uuid.UUID, scheduled bool, page pagination.Params) (pagination.Page[models.PostDraft], error) {
+	ctx, span := tracing.Start(ctx, "DraftService.ListDrafts")
+	defer span.End()
+
+	return s.Repo.GetByUserID(ctx, userID, scheduled, page)
+}
+
+// UpdateDraft replaces the text, visibility and publish time of a draft of
+// userID. Setting PublishAt schedules a draft; clearing it turns a
+// scheduled post back into a draft.
+func (s *DraftService) UpdateDraft(ctx context.Context, userID uuid.UUID, changes *models.PostDraft) (*models.PostDraft, error) {
+	ctx, span := tracing.Start(ctx, "DraftService.UpdateDraft")
+	defer span.End()
+
+	if _, err := s.GetDraft(ctx, userID, changes.ID); err != nil {
+		return nil, err
+	}
+	if changes.PublishAt != nil && !changes.PublishAt.After(s.now()) {
+		return nil, ErrPublishAtInPast
+	}
+	updated, err := s.Repo.Update(ctx, changes)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		// Published or deleted meanwhile.
+		return nil, ErrDraftNotFound
+	}
+	return updated, err
+}
+
+// DeleteDraft discards a draft of userID or cancels a scheduled post.
+func (s *DraftService) DeleteDraft(ctx context.Context, userID, draftID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "DraftService.DeleteDraft")
+	defer span.End()
+
+	if _, err := s.GetDraft(ctx, userID, draftID); err != nil {
+		return err
+	}
+	err := s.Repo.Delete(ctx, draftID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return ErrDraftNotFound
+	}
+	return err
+}
+
+// PublishDraft publishes a draft or scheduled post of userID right away.
+func (s *DraftService) PublishDraft(ctx context.Context, userID, draftID uuid.UUID) (*models.ContentPost, error) {
+	ctx, span := tracing.Start(ctx, "DraftService.PublishDraft")
+	defer span.End()
+
+	draft, err := s.GetDraft(ctx, userID, draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	var post *models.ContentPost
+	err = s.Tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		post, err = s.publish(ctx, draft)
+		return err
+	})
+	if errors.Is(err, apperrors.ErrNotFound) {
+		// Published by the scheduler or deleted meanwhile.
+		return nil, ErrDraftNotFound
+	}
+	return post, err
+}
+
+// PublishDue publishes the scheduled posts that are due, each in its own
+// transaction, up to BatchSize of them. Posts that fail to publish are left
+// for the next run and reported together.
+func (s *DraftService) PublishDue(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "DraftService.PublishDue")
+	defer span.End()
+
+	var (
+		failed []uuid.UUID
+		errs   []error
+	)
+	for range s.batchSize() {
+		var claimed *models.PostDraft
+		err := s.Tx.WithTx(ctx, func(ctx context.Context) error {
+			var err error
+			claimed, err = s.Repo.ClaimDue(ctx, s.now(), failed)
+			if errors.Is(err, apperrors.ErrNotFound) {
+				claimed = nil
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			post, err := s.publish(ctx, claimed)
+			if err != nil {
+				return err
+			}
+			return s.notifyPublished(ctx, post)
+		})
+		switch {
+		case err != nil && claimed != nil:
+			failed = append(failed, claimed.ID)
+			errs = append(errs, fmt.Errorf("publishing scheduled post %s: %w", claimed.ID, err))
+		case err != nil:
+			return errors.Join(append(errs, err)...)
+		case claimed == nil:
+			return errors.Join(errs...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// publish deletes draft and creates its post. Call it in a transaction.
+func (s *DraftService) publish(ctx context.Context, draft *models.PostDraft) (*models.ContentPost, error) {
+	if err := s.Repo.Delete(ctx, draft.ID); err != nil {
+		return nil, err
+	}
+	return s.Posts.CreatePost(ctx, &models.ContentPost{
+		UserID:      draft.UserID,
+		PostContent: draft.PostContent,
+		Visibility:  draft.Visibility,
+	})
+}
+
+// notifyPublished tells the author of post that the scheduler published it.
+func (s *DraftService) notifyPublished(ctx context.Context, post *models.ContentPost) error {
+	if s.Notifications == nil {
+		return nil
+	}
+	return s.Notifications.Create(ctx, &models.Notification{
+		ID:              uuid.New(),
+		RecipientUserID: post.UserID,
+		SenderUserID:    post.UserID,
+		Type:            NotificationPostPublished,
+		EntityID:        post.ID,
+		EntityType:      EntityTypePost,
+		Message:         "Your scheduled post was published",
+	})
+}
@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/repotest"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+func TestDraftServicePublishesScheduledPostsWhenDue(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	posts := &services.PostService{Repo: repos.Posts, Tx: repos.Tx, Profiles: repos.UserProfiles}
+	now := time.Now()
+	drafts := &services.DraftService{Repo: repos.Drafts, Posts: posts, Tx: repos.Tx, Notifications: repos.Notifications, Now: func() time.Time { return now }}
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+	profile := repotest.CreateProfile(t, repos, alice, "Alice")
+	profile.DefaultVisibility = models.VisibilityFollowers
+	if _, err := repos.UserProfiles.Update(ctx, alice.String(), profile); err != nil {
+		t.Fatalf("Update profile: %v", err)
+	}
+
+	past := now.Add(-time.Minute)
+	if _, err := drafts.SaveDraft(ctx, &models.PostDraft{UserID: alice, PostContent: "Too late", PublishAt: &past}); !errors.Is(err, services.ErrPublishAtInPast) {
+		t.Fatalf("SaveDraft in the past error = %v; want ErrPublishAtInPast", err)
+	}
+	at := now.Add(time.Hour)
+	scheduled, err := drafts.SaveDraft(ctx, &models.PostDraft{UserID: alice, PostContent: "Grand opening!", PublishAt: &at})
+	if err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+	if _, err := drafts.GetDraft(ctx, bob, scheduled.ID); !errors.Is(err, services.ErrDraftNotFound) {
+		t.Errorf("GetDraft by another user error = %v; want ErrDraftNotFound", err)
+	}
+	if err := drafts.DeleteDraft(ctx, bob, scheduled.ID); !errors.Is(err, services.ErrDraftNotFound) {
+		t.Errorf("DeleteDraft by another user error = %v; want ErrDraftNotFound", err)
+	}
+
+	// Nothing is due yet.
+	if err := drafts.PublishDue(ctx); err != nil {
+		t.Fatalf("PublishDue: %v", err)
+	}
+	if got, _ := repos.Posts.GetPostsByUserID(ctx, alice, alice, pagination.First()); len(got.Items) != 0 {
+		t.Fatalf("posts before the publish time = %v; want none", got)
+	}
+
+	now = at
+	if err := drafts.PublishDue(ctx); err != nil {
+		t.Fatalf("PublishDue: %v", err)
+	}
+	published, err := repos.Posts.GetPostsByUserID(ctx, alice, alice, pagination.First())
+	if err != nil || len(published.Items) != 1 || published.Items[0].PostContent != "Grand opening!" || published.Items[0].Visibility != models.VisibilityFollowers {
+		t.Fatalf("published posts = %+v, %v; want the scheduled post with the author's default visibility", published, err)
+	}
+	if _, err := drafts.GetDraft(ctx, alice, scheduled.ID); !errors.Is(err, services.ErrDraftNotFound) {
+		t.Errorf("GetDraft after publishing error = %v; want ErrDraftNotFound", err)
+	}
+	notes, _ := repos.Notifications.GetByUserID(ctx, alice, pagination.First())
+	if len(notes.Items) != 1 || notes.Items[0].Type != services.NotificationPostPublished || notes.Items[0].EntityID != published.Items[0].ID {
+		t.Errorf("notifications = %+v; want one for the published post", notes.Items)
+	}
+}
+
+func TestDraftServiceEditsAndPublishesDrafts(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	posts := &services.PostService{Repo: repos.Posts, Tx: repos.Tx}
+	drafts := &services.DraftService{Repo: repos.Drafts, Posts: posts, Tx: repos.Tx, Notifications: repos.Notifications}
+	alice := repotest.CreateUser(t, repos, "alice")
+
+	draft, err := drafts.SaveDraft(ctx, &models.PostDraft{UserID: alice, PostContent: "Hiring"})
+	if err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+	at := time.Now().Add(time.Hour)
+	if _, err := drafts.UpdateDraft(ctx, alice, &models.PostDraft{ID: draft.ID, PostContent: "Hiring chefs", PublishAt: &at}); err != nil {
+		t.Fatalf("UpdateDraft: %v", err)
+	}
+	if got, _ := drafts.ListDrafts(ctx, alice, true, pagination.First()); len(got.Items) != 1 || got.Items[0].PostContent != "Hiring chefs" {
+		t.Fatalf("ListDrafts(scheduled) = %+v; want the edited post", got.Items)
+	}
+	if _, err := drafts.UpdateDraft(ctx, alice, &models.PostDraft{ID: draft.ID, PostContent: "Hiring chefs"}); err != nil {
+		t.Fatalf("UpdateDraft back to a draft: %v", err)
+	}
+	if got, _ := drafts.ListDrafts(ctx, alice, false, pagination.First()); len(got.Items) != 1 {
+		t.Fatalf("ListDrafts = %+v; want the unscheduled draft", got.Items)
+	}
+
+	post, err := drafts.PublishDraft(ctx, alice, draft.ID)
+	if err != nil || post.PostContent != "Hiring chefs" || post.Visibility != models.VisibilityPublic {
+		t.Fatalf("PublishDraft = %+v, %v", post, err)
+	}
+	if _, err := drafts.PublishDraft(ctx, alice, draft.ID); !errors.Is(err, services.ErrDraftNotFound) {
+		t.Errorf("PublishDraft twice error = %v; want ErrDraftNotFound", err)
+	}
+	if notes, _ := repos.Notifications.GetByUserID(ctx, alice, pagination.First()); len(notes.Items) != 0 {
+		t.Errorf("notifications = %+v; want none for a post published by hand", notes.Items)
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/entities"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+)
+
+// Notification type and entity types of mentions.
+const (
+	NotificationMention = "mention"
+	EntityTypePost      = "post"
+	EntityTypeComment   = "comment"
+)
+
+// EntityService finds the hashtags and @mentions of posts and comments,
+// indexes the hashtags and notifies the mentioned users.
+type EntityService struct {
+	Users         repositories.UserRepository
+	Hashtags      repositories.HashtagRepository
+	Notifications repositories.NotificationRepository
+
+	// Posts, when set, keeps mentions from notifying users who cannot see
+	// the post mentioning them or the post commented on.
+	Posts repositories.PostRepository
+}
+
+// Parse returns the entities of text with mentions resolved to the IDs of
+// their users. Mentions of unknown usernames are kept without a user ID.
+func (s *EntityService) Parse(ctx context.Context, text string) ([]models.Entity, error) {
+	ctx, span := tracing.Start(ctx, "EntityService.Parse")
+	defer span.End()
+
+	found := entities.Parse(text)
+	usernames := entities.Mentions(found)
+	if len(usernames) == 0 {
+		return found, nil
+	}
+	users, err := s.Users.GetUsersByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]uuid.UUID, len(users))
+	for _, u := range users {
+		if id, err := uuid.Parse(u.ID); err == nil {
+			ids[u.Username] = id
+		}
+	}
+	for i, e := range found {
+		if id, ok := ids[e.Text]; ok && e.Type == models.EntityMention {
+			found[i].UserID = &id
+		}
+	}
+	return found, nil
+}
+
+// IndexPost indexes the hashtags of post and notifies the users it mentions
+// that were not mentioned in previous, the entities of the text it
+// replaced. Call it in the transaction that writes the post.
+func (s *EntityService) IndexPost(ctx context.Context, post *models.ContentPost, previous []models.Entity) error {
+	ctx, span := tracing.Start(ctx, "EntityService.IndexPost")
+	defer span.End()
+
+	if err := s.Hashtags.IndexPost(ctx, post.ID, entities.Hashtags(post.Entities)); err != nil {
+		return err
+	}
+	return s.notifyMentions(ctx, post.UserID, post.ID, post.ID, EntityTypePost, post.Entities, previous)
+}
+
+// IndexComment indexes the hashtags of comment and notifies the users it
+// mentions. Call it in the transaction that writes the comment.
+func (s *EntityService) IndexComment(ctx context.Context, comment *models.PostComment) error {
+	ctx, span := tracing.Start(ctx, "EntityService.IndexComment")
+	defer span.End()
+
+	if err := s.Hashtags.IndexComment(ctx, comment.ID, entities.Hashtags(comment.Entities)); err != nil {
+		return err
+	}
+	return s.notifyMentions(ctx, comment.UserID, comment.PostID, comment.ID, EntityTypeComment, comment.Entities, nil)
+}
+
+// notifyMentions notifies every user mentioned in current but not in
+// previous once, except the author and the users who cannot see postID.
+func (s *EntityService) notifyMentions(ctx context.Context, authorID, postID, entityID uuid.UUID, entityType string, current, previous []models.Entity) error {
+	notified := map[uuid.UUID]bool{authorID: true}
+	for _, e := range previous {
+		if e.UserID != nil {
+			notified[*e.UserID] = true
+		}
+	}
+	for _, e := range current {
+		if e.UserID == nil || notified[*e.UserID] {
+			continue
+		}
+		notified[*e.UserID] = true
+		if s.Posts != nil {
+			visible, err := s.Posts.CanView(ctx, *e.UserID, postID)
+			if err != nil {
+				return err
+			}
+			if !visible {
+				continue
+			}
+		}
+		err := s.Notifications.Create(ctx, &models.Notification{
+			ID:              uuid.New(),
+			RecipientUserID: *e.UserID,
+			SenderUserID:    authorID,
+			Type:            NotificationMention,
+			EntityID:        entityID,
+			EntityType:      entityType,
+			Message:         "You were mentioned in a " + entityType,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PostsByHashtag returns a page of the posts using tag that viewerID can
+// see, newest first. tag must be normalized with entities.NormalizeTag.
+func (s *EntityService) PostsByHashtag(ctx context.Context, viewerID uuid.UUID, tag string, page pagination.Params) (pagination.Page[models.PostWithDetails], error) {
+	ctx, span := tracing.Start(ctx, "EntityService.PostsByHashtag")
+	defer span.End()
+
+	return s.Hashtags.GetPosts(ctx, viewerID, tag, page)
+}
+
+// Trending returns the limit hashtags used most over the last window.
+func (s *EntityService) Trending(ctx context.Context, window time.Duration, limit int) ([]models.HashtagCount, error) {
+	ctx, span := tracing.Start(ctx, "EntityService.Trending")
+	defer span.End()
+
+	return s.Hashtags.Trending(ctx, time.Now().Add(-window), limit)
+}
@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/repotest"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+func TestMentionsNotifyEachUserOnce(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	entities := &services.EntityService{Users: repos.Users, Hashtags: repos.Hashtags, Notifications: repos.Notifications}
+	posts := services.NewPostService(repos.Posts)
+	posts.Tx, posts.Entities = repos.Tx, entities
+	comments := &services.PostCommentService{PostCommentRepository: repos.PostComments, Tx: repos.Tx, Entities: entities}
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+	carol := repotest.CreateUser(t, repos, "carol")
+
+	post, err := posts.CreatePost(ctx, &models.ContentPost{UserID: alice, PostContent: "Welcome @bob and @ghost to #Hotel life, says @alice"})
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	if len(post.Entities) != 4 || *post.Entities[0].UserID != bob || post.Entities[1].UserID != nil || post.Entities[2].Text != "hotel" {
+		t.Fatalf("entities = %+v; want bob resolved, ghost unresolved and the hashtag", post.Entities)
+	}
+
+	// Editing notifies only the users the post did not mention yet.
+	if _, err := posts.UpdatePost(ctx, alice, post.ID, "Welcome @bob and @carol to #travel"); err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+	if err := comments.CommentOnPost(ctx, carol, post.ID, "Thanks @alice! #travel"); err != nil {
+		t.Fatalf("CommentOnPost: %v", err)
+	}
+
+	for _, c := range []struct {
+		name       string
+		user       uuid.UUID
+		entityType string
+	}{
+		{"bob", bob, services.EntityTypePost},
+		{"carol", carol, services.EntityTypePost},
+		{"alice", alice, services.EntityTypeComment},
+	} {
+		got, err := repos.Notifications.GetByUserID(ctx, c.user, pagination.First())
+		if err != nil || len(got.Items) != 1 || got.Items[0].Type != services.NotificationMention || got.Items[0].EntityType != c.entityType {
+			t.Errorf("notifications of %s = %+v, %v; want one mention in a %s", c.name, got.Items, err, c.entityType)
+		}
+	}
+
+	hotel, err := entities.PostsByHashtag(ctx, bob, "hotel", pagination.First())
+	if err != nil || len(hotel.Items) != 0 {
+		t.Errorf("PostsByHashtag(hotel) after the edit = %v, %v; want none", hotel.Items, err)
+	}
+	trending, err := entities.Trending(ctx, time.Hour, 10)
+	if err != nil || len(trending) != 1 || trending[0] != (models.HashtagCount{Tag: "travel", Count: 2}) {
+		t.Errorf("Trending = %v, %v; want travel used twice", trending, err)
+	}
+}
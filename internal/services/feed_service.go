@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ranking"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+)
+
+// Defaults of FeedService.
+const (
+	DefaultFanOutLimit   = 1000
+	DefaultBackfillSize  = 50
+	DefaultPopularWindow = 7 * 24 * time.Hour
+	DefaultCandidatePool = 200
+	DefaultSignalWindow  = 24 * time.Hour
+)
+
+// Sources of a home feed page.
+const (
+	FeedSourceFollowing = "following"
+	FeedSourcePopular   = "popular"
+)
+
+// HomeFeed is a page of a home feed. Source tells whether it lists the posts
+// of the viewer's network or, while that has nothing to show, popular posts.
+// Ranked feeds also name the Ranker that ordered them; Scores[i] is then the
+// score of Page.Items[i].
+type HomeFeed struct {
+	Page   pagination.Page[models.PostWithDetails]
+	Source string
+	Ranker string
+	Scores []ranking.Score
+}
+
+// FeedService builds the home feed of every user from the posts of the
+// people they follow and their own. A post by an author with at most
+// FanOutLimit followers is written to the timeline of each follower when it
+// is published; the posts of more followed authors are read when a feed is
+// requested, so publishing never costs more than FanOutLimit writes.
+type FeedService struct {
+	Timelines repositories.TimelineRepository
+	Posts     repositories.PostRepository
+
+	// FanOutLimit, BackfillSize and PopularWindow default to
+	// DefaultFanOutLimit, DefaultBackfillSize and DefaultPopularWindow.
+	FanOutLimit   int
+	BackfillSize  int
+	PopularWindow time.Duration
+
+	// Rankers order the top feed; each viewer is assigned one variant.
+	// CandidatePool is the number of latest posts ranked and SignalWindow
+	// the period recent likes and comments are counted over; they default
+	// to DefaultCandidatePool and DefaultSignalWindow.
+	Rankers       *ranking.Experiment
+	CandidatePool int
+	SignalWindow  time.Duration
+}
+
+func (s *FeedService) fanOutLimit() int {
+	if s.FanOutLimit > 0 {
+		return s.FanOutLimit
+	}
+	return DefaultFanOutLimit
+}
+
+func (s *FeedService) backfillSize() int {
+	if s.BackfillSize > 0 {
+		return s.BackfillSize
+	}
+	return DefaultBackfillSize
+}
+
+func (s *FeedService) candidatePool() int {
+	if s.CandidatePool > 0 {
+		return s.CandidatePool
+	}
+	return DefaultCandidatePool
+}
+
+func (s *FeedService) signalWindow() time.Duration {
+	if s.SignalWindow > 0 {
+		return s.SignalWindow
+	}
+	return DefaultSignalWindow
+}
+
+func (s *FeedService) popularWindow() time.Duration {
+	if s.PopularWindow > 0 {
+		return s.PopularWindow
+	}
+	return DefaultPopularWindow
+}
+
+// Publish adds a new post to the timelines of its author's followers. Call
+// it in the transaction that creates the post.
+func (s *FeedService) Publish(ctx context.Context, post *models.ContentPost) error {
+	ctx, span := tracing.Start(ctx, "FeedService.Publish")
+	defer span.End()
+
+	return s.Timelines.FanOut(ctx, post, s.fanOutLimit())
+}
+
+// Follow adds the latest posts of followedID to the timeline of followerID.
+// Call it in the transaction that records the follow.
+func (s *FeedService) Follow(ctx context.Context, followerID, followedID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "FeedService.Follow")
+	defer span.End()
+
+	return s.Timelines.Backfill(ctx, followerID, followedID, s.fanOutLimit(), s.backfillSize())
+}
+
+// Unfollow drops the posts of followedID from the timeline of followerID.
+func (s *FeedService) Unfollow(ctx context.Context, followerID, followedID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "FeedService.Unfollow")
+	defer span.End()
+
+	return s.Timelines.Remove(ctx, followerID, followedID)
+}
+
+// Home returns a page of the home feed of userID. When the first page is
+// empty, typically for a new user who follows nobody yet, it lists the most
+// popular recent posts instead, on a single page.
+func (s *FeedService) Home(ctx context.Context, userID uuid.UUID, page pagination.Params) (HomeFeed, error) {
+	ctx, span := tracing.Start(ctx, "FeedService.Home")
+	defer span.End()
+
+	feed, err := s.Timelines.Home(ctx, userID, s.fanOutLimit(), page)
+	if err != nil {
+		return HomeFeed{}, err
+	}
+	if len(feed.Items) > 0 || page.After != nil {
+		return HomeFeed{Page: feed, Source: FeedSourceFollowing}, nil
+	}
+
+	return s.popular(ctx, userID, page.Limit)
+}
+
+// Top returns the best limit posts of the home feed of userID by the score
+// of the ranker assigned to the user, on a single page. It ranks the latest
+// CandidatePool posts and falls back to popular posts like Home.
+func (s *FeedService) Top(ctx context.Context, userID uuid.UUID, limit int) (HomeFeed, error) {
+	ctx, span := tracing.Start(ctx, "FeedService.Top")
+	defer span.End()
+
+	latest, err := s.Timelines.Home(ctx, userID, s.fanOutLimit(), pagination.Params{Limit: s.candidatePool()})
+	if err != nil {
+		return HomeFeed{}, err
+	}
+	if len(latest.Items) == 0 {
+		return s.popular(ctx, userID, limit)
+	}
+
+	now := time.Now()
+	ids := make([]uuid.UUID, len(latest.Items))
+	for i, p := range latest.Items {
+		ids[i] = p.PostID
+	}
+	signals, err := s.Timelines.Signals(ctx, userID, ids, now.Add(-s.signalWindow()))
+	if err != nil {
+		return HomeFeed{}, err
+	}
+
+	candidates := make([]ranking.Candidate, len(latest.Items))
+	for i, p := range latest.Items {
+		candidates[i] = ranking.Candidate{Post: p, Signals: signals[p.PostID]}
+	}
+	ranker := s.ranker(userID)
+	ranked := ranking.Rank(ranker, candidates, now)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	feed := HomeFeed{Source: FeedSourceFollowing, Ranker: ranker.Name()}
+	for _, r := range ranked {
+		feed.Page.Items = append(feed.Page.Items, r.Post)
+		feed.Scores = append(feed.Scores, r.Score)
+	}
+	return feed, nil
+}
+
+func (s *FeedService) ranker(userID uuid.UUID) ranking.Ranker {
+	if s.Rankers == nil {
+		r, _ := ranking.New(ranking.Baseline)
+		return r
+	}
+	return s.Rankers.For(userID)
+}
+
+func (s *FeedService) popular(ctx context.Context, userID uuid.UUID, limit int) (HomeFeed, error) {
+	popular, err := s.Posts.GetPopular(ctx, userID, time.Now().Add(-s.popularWindow()), limit)
+	if err != nil {
+		return HomeFeed{}, err
+	}
+	return HomeFeed{Page: pagination.Page[models.PostWithDetails]{Items: popular}, Source: FeedSourcePopular}, nil
+}
@@ -0,0 +1,97 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/repotest"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+func TestFeedServiceHome(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	feed := &services.FeedService{Timelines: repos.Timelines, Posts: repos.Posts}
+	posts := &services.PostService{Repo: repos.Posts, Feed: feed, Tx: repos.Tx}
+	follows := &services.FollowService{FollowRepository: repos.Follows, Tx: repos.Tx, Feed: feed}
+
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+	repotest.CreateProfile(t, repos, alice, "Alice Example")
+	repotest.CreateProfile(t, repos, bob, "Bob Example")
+
+	before, err := posts.CreatePost(ctx, &models.ContentPost{UserID: alice, PostContent: "before the follow"})
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+
+	// bob follows nobody yet, so he is shown popular posts.
+	home, err := feed.Home(ctx, bob, pagination.First())
+	if err != nil || home.Source != services.FeedSourcePopular || len(home.Page.Items) != 1 {
+		t.Fatalf("Home before following = %+v, %v; want alice's post as popular", home, err)
+	}
+
+	if err := follows.FollowUser(ctx, bob, alice); err != nil {
+		t.Fatalf("FollowUser: %v", err)
+	}
+	after, err := posts.CreatePost(ctx, &models.ContentPost{UserID: alice, PostContent: "after the follow"})
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+
+	home, err = feed.Home(ctx, bob, pagination.First())
+	if err != nil || home.Source != services.FeedSourceFollowing || len(home.Page.Items) != 2 ||
+		home.Page.Items[0].PostID != after.ID || home.Page.Items[1].PostID != before.ID {
+		t.Fatalf("Home after following = %+v, %v; want the backfilled and the fanned out post", home, err)
+	}
+
+	if err := follows.UnfollowUser(ctx, bob, alice); err != nil {
+		t.Fatalf("UnfollowUser: %v", err)
+	}
+	if home, _ := feed.Home(ctx, bob, pagination.First()); home.Source != services.FeedSourcePopular {
+		t.Errorf("Home after unfollowing source = %q; want popular", home.Source)
+	}
+}
+
+func TestFeedServiceTopRanksEngagedPostsFirst(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	feed := &services.FeedService{Timelines: repos.Timelines, Posts: repos.Posts}
+	follows := &services.FollowService{FollowRepository: repos.Follows, Tx: repos.Tx, Feed: feed}
+
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+	carol := repotest.CreateUser(t, repos, "carol")
+	repotest.CreateProfile(t, repos, alice, "Alice Example")
+
+	liked := repotest.CreatePost(t, repos, alice, "liked")
+	newest := repotest.CreatePost(t, repos, alice, "newest")
+	if err := follows.FollowUser(ctx, bob, alice); err != nil {
+		t.Fatalf("FollowUser: %v", err)
+	}
+	for _, u := range []uuid.UUID{alice, carol} {
+		if err := repos.PostLikes.CreateLike(ctx, u, liked.ID); err != nil {
+			t.Fatalf("CreateLike: %v", err)
+		}
+	}
+
+	top, err := feed.Top(ctx, bob, 10)
+	if err != nil || top.Source != services.FeedSourceFollowing || top.Ranker != "baseline" || len(top.Page.Items) != 2 ||
+		top.Page.Items[0].PostID != liked.ID || top.Page.Items[1].PostID != newest.ID {
+		t.Fatalf("Top = %+v, %v; want the liked post before the newest by baseline", top, err)
+	}
+	if len(top.Scores) != 2 || top.Scores[0].Total <= top.Scores[1].Total {
+		t.Errorf("Top scores = %+v; want one decreasing score per post", top.Scores)
+	}
+	if top.Page.NextCursor != "" {
+		t.Errorf("Top next cursor = %q; want a single page", top.Page.NextCursor)
+	}
+
+	if top, _ := feed.Top(ctx, bob, 1); len(top.Page.Items) != 1 || top.Page.Items[0].PostID != liked.ID {
+		t.Errorf("Top(1) = %+v; want the liked post alone", top.Page.Items)
+	}
+}
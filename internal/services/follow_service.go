@@ -1,30 +1,69 @@
 package services
 
 import (
+	"context"
+
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
 type FollowService struct {
-	FollowRepository *repositories.FollowRepository
+	FollowRepository repositories.FollowRepository
+	Tx               repositories.TxManager
+	Metrics          *metrics.Metrics
+
+	// Feed, when set, keeps the follower's home timeline in step.
+	Feed *FeedService
 }
 
-// FollowUser allows a user to follow another user
-func (service *FollowService) FollowUser(followerID, followedID uuid.UUID) error {
-	return service.FollowRepository.FollowUser(followerID, followedID)
+// FollowUser allows a user to follow another user. The followers and
+// followings rows and the follower's timeline are written in one
+// transaction.
+func (service *FollowService) FollowUser(ctx context.Context, followerID, followedID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "FollowService.FollowUser")
+	defer span.End()
+
+	err := service.Tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := service.FollowRepository.FollowUser(ctx, followerID, followedID); err != nil || service.Feed == nil {
+			return err
+		}
+		return service.Feed.Follow(ctx, followerID, followedID)
+	})
+	if err != nil {
+		return err
+	}
+	service.Metrics.Followed()
+	return nil
 }
 
 // UnfollowUser allows a user to unfollow another user
-func (service *FollowService) UnfollowUser(followerID, followedID uuid.UUID) error {
-	return service.FollowRepository.UnfollowUser(followerID, followedID)
+func (service *FollowService) UnfollowUser(ctx context.Context, followerID, followedID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "FollowService.UnfollowUser")
+	defer span.End()
+
+	return service.Tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := service.FollowRepository.UnfollowUser(ctx, followerID, followedID); err != nil || service.Feed == nil {
+			return err
+		}
+		return service.Feed.Unfollow(ctx, followerID, followedID)
+	})
 }
 
-// GetFollowers retrieves a list of followers for a user
-func (service *FollowService) GetFollowers(userID uuid.UUID) ([]uuid.UUID, error) {
-	return service.FollowRepository.GetFollowers(userID)
+// GetFollowers retrieves a page of the followers of a user
+func (service *FollowService) GetFollowers(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error) {
+	ctx, span := tracing.Start(ctx, "FollowService.GetFollowers")
+	defer span.End()
+
+	return service.FollowRepository.GetFollowers(ctx, userID, page)
 }
 
-// GetFollowings retrieves a list of users that a user is following
-func (service *FollowService) GetFollowings(userID uuid.UUID) ([]uuid.UUID, error) {
-	return service.FollowRepository.GetFollowings(userID)
+// GetFollowings retrieves a page of the users that a user is following
+func (service *FollowService) GetFollowings(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error) {
+	ctx, span := tracing.Start(ctx, "FollowService.GetFollowings")
+	defer span.End()
+
+	return service.FollowRepository.GetFollowings(ctx, userID, page)
 }
@@ -0,0 +1,39 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/repotest"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+func TestFollowServiceFollowAndUnfollow(t *testing.T) {
+	repos := memory.NewRepositories()
+	svc := &services.FollowService{FollowRepository: repos.Follows, Tx: repos.Tx}
+	ctx := context.Background()
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+
+	if err := svc.FollowUser(ctx, alice, bob); err != nil {
+		t.Fatalf("FollowUser: %v", err)
+	}
+
+	followers, err := svc.GetFollowers(ctx, bob, pagination.First())
+	if err != nil || len(followers.Items) != 1 || followers.Items[0] != alice {
+		t.Fatalf("GetFollowers = %v, %v; want [alice]", followers, err)
+	}
+	followings, err := svc.GetFollowings(ctx, alice, pagination.First())
+	if err != nil || len(followings.Items) != 1 || followings.Items[0] != bob {
+		t.Fatalf("GetFollowings = %v, %v; want [bob]", followings, err)
+	}
+
+	if err := svc.UnfollowUser(ctx, alice, bob); err != nil {
+		t.Fatalf("UnfollowUser: %v", err)
+	}
+	if followers, _ := svc.GetFollowers(ctx, bob, pagination.First()); len(followers.Items) != 0 {
+		t.Errorf("GetFollowers after unfollow = %v; want empty", followers)
+	}
+}
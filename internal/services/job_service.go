@@ -3,21 +3,32 @@ package services
 import (
 	"context"
 
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
 type JobService struct {
-	Repo *repositories.JobRepository
+	Repo    repositories.JobRepository
+	Metrics *metrics.Metrics
 }
 
 func (s *JobService) CreateJobPost(ctx context.Context, post *models.JobPost) (*models.JobPost, error) {
-	if err := s.Repo.CreateJobPost(post); err != nil {
+	ctx, span := tracing.Start(ctx, "JobService.CreateJobPost")
+	defer span.End()
+
+	if err := s.Repo.CreateJobPost(ctx, post); err != nil {
 		return nil, err
 	}
+	s.Metrics.JobPosted()
 	return post, nil
 }
 
-func (s *JobService) GetAllJobPosts(ctx context.Context) ([]models.JobPost, error) {
-	return s.Repo.GetAll(ctx)
+func (s *JobService) GetAllJobPosts(ctx context.Context, page pagination.Params) (pagination.Page[models.JobPost], error) {
+	ctx, span := tracing.Start(ctx, "JobService.GetAllJobPosts")
+	defer span.End()
+
+	return s.Repo.GetAll(ctx, page)
 }
@@ -5,24 +5,32 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
 type NotificationService struct {
-	NotificationRepository *repositories.NotificationRepository
+	NotificationRepository repositories.NotificationRepository
 }
 
-func NewNotificationService(repo *repositories.NotificationRepository) *NotificationService {
+func NewNotificationService(repo repositories.NotificationRepository) *NotificationService {
 	return &NotificationService{NotificationRepository: repo}
 }
 
 func (s *NotificationService) CreateNotification(ctx context.Context, n *models.Notification) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.CreateNotification")
+	defer span.End()
+
 	if n.ID == uuid.Nil {
 		n.ID = uuid.New()
 	}
 	return s.NotificationRepository.Create(ctx, n)
 }
 
-func (s *NotificationService) GetNotificationsForUser(ctx context.Context, userID uuid.UUID) ([]models.Notification, error) {
-	return s.NotificationRepository.GetByUserID(ctx, userID)
+func (s *NotificationService) GetNotificationsForUser(ctx context.Context, userID uuid.UUID, page pagination.Params) (pagination.Page[models.Notification], error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetNotificationsForUser")
+	defer span.End()
+
+	return s.NotificationRepository.GetByUserID(ctx, userID, page)
 }
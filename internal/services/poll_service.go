@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+)
+
+var (
+	// ErrPollNotFound is returned when a post has no poll.
+	ErrPollNotFound = apperrors.NotFound("poll_not_found", "Poll not found")
+	// ErrPollClosed is returned when a user votes in a poll that has ended.
+	ErrPollClosed = apperrors.Unprocessable("poll_closed", "This poll has ended")
+	// ErrAlreadyVoted is returned when a user votes twice in a poll.
+	ErrAlreadyVoted = apperrors.Conflict("already_voted", "You have already voted in this poll")
+	// ErrPollEndsInPast is returned when a poll would end before it starts.
+	ErrPollEndsInPast = apperrors.InvalidField("ends_at", "must be in the future")
+	// ErrDuplicatePollOption is returned when a poll offers the same option
+	// twice.
+	ErrDuplicatePollOption = apperrors.InvalidField("options", "must be different from each other")
+	// ErrSingleChoice is returned when a user picks several options of a
+	// single choice poll.
+	ErrSingleChoice = apperrors.InvalidField("option_ids", "must be a single option in a single choice poll")
+	// ErrUnknownPollOption is returned when a user votes for an option of
+	// another poll.
+	ErrUnknownPollOption = apperrors.InvalidField("option_ids", "must be options of the poll")
+)
+
+// NotificationPollEnded tells authors their poll ended.
+const NotificationPollEnded = "poll_ended"
+
+// defaultPollBatch is how many ended polls NotifyEnded reports per run when
+// PollService.BatchSize is not set.
+const defaultPollBatch = 100
+
+// PollService creates poll posts, takes their votes and tells authors when
+// their polls end.
+type PollService struct {
+	Repo  repositories.PollRepository
+	Posts *PostService
+	Tx    repositories.TxManager
+
+	// Notifications, when set, tells authors their polls ended.
+	Notifications repositories.NotificationRepository
+
+	// BatchSize bounds the polls reported by one NotifyEnded run;
+	// defaultPollBatch when zero.
+	BatchSize int
+
+	// Now returns the current time; time.Now when nil.
+	Now func() time.Time
+}
+
+func (s *PollService) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+func (s *PollService) batchSize() int {
+	if s.BatchSize > 0 {
+		return s.BatchSize
+	}
+	return defaultPollBatch
+}
+
+// CreatePoll publishes a poll post, which asks the question in its text.
+func (s *PollService) CreatePoll(ctx context.Context, post *models.ContentPost) (*models.ContentPost, error) {
+	ctx, span := tracing.Start(ctx, "PollService.CreatePoll")
+	defer span.End()
+
+	if !post.Poll.EndsAt.After(s.now()) {
+		return nil, ErrPollEndsInPast
+	}
+	seen := make(map[string]bool, len(post.Poll.Options))
+	for _, o := range post.Poll.Options {
+		label := strings.ToLower(o.Label)
+		if seen[label] {
+			return nil, ErrDuplicatePollOption
+		}
+		seen[label] = true
+	}
+	return s.Posts.CreatePost(ctx, post)
+}
+
+// GetPoll returns the poll of a post viewerID can see, with their choices.
+func (s *PollService) GetPoll(ctx context.Context, viewerID, postID uuid.UUID) (*models.Poll, error) {
+	ctx, span := tracing.Start(ctx, "PollService.GetPoll")
+	defer span.End()
+
+	if _, err := s.Posts.visiblePost(ctx, viewerID, postID); err != nil {
+		return nil, err
+	}
+	poll, err := s.Repo.GetPoll(ctx, viewerID, postID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, ErrPollNotFound
+	}
+	return poll, err
+}
+
+// Vote records the choice of userID in the poll of a post they can see and
+// returns the poll with its results. A user votes once, for a single option
+// unless the poll is multiple choice.
+func (s *PollService) Vote(ctx context.Context, userID, postID uuid.UUID, optionIDs []uuid.UUID) (*models.Poll, error) {
+	ctx, span := tracing.Start(ctx, "PollService.Vote")
+	defer span.End()
+
+	poll, err := s.GetPoll(ctx, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !s.now().Before(poll.EndsAt):
+		return nil, ErrPollClosed
+	case !poll.MultipleChoice && len(optionIDs) > 1:
+		return nil, ErrSingleChoice
+	}
+
+	err = s.Tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.Repo.Vote(ctx, postID, userID, optionIDs); err != nil {
+			return err
+		}
+		poll, err = s.Repo.GetPoll(ctx, userID, postID)
+		return err
+	})
+	switch {
+	case errors.Is(err, apperrors.ErrConflict):
+		return nil, ErrAlreadyVoted
+	case errors.Is(err, apperrors.ErrNotFound):
+		return nil, ErrUnknownPollOption
+	case err != nil:
+		return nil, err
+	}
+	return poll, nil
+}
+
+// NotifyEnded tells the authors of the polls that ended since the last run,
+// up to BatchSize of them, in one transaction.
+func (s *PollService) NotifyEnded(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "PollService.NotifyEnded")
+	defer span.End()
+
+	return s.Tx.WithTx(ctx, func(ctx context.Context) error {
+		ended, err := s.Repo.ClaimEnded(ctx, s.now(), s.batchSize())
+		if err != nil || s.Notifications == nil {
+			return err
+		}
+		for _, postID := range ended {
+			post, err := s.Posts.Repo.GetPostByID(ctx, uuid.Nil, postID)
+			if err != nil {
+				return err
+			}
+			err = s.Notifications.Create(ctx, &models.Notification{
+				ID:              uuid.New(),
+				RecipientUserID: post.UserID,
+				SenderUserID:    post.UserID,
+				Type:            NotificationPollEnded,
+				EntityID:        post.ID,
+				EntityType:      EntityTypePost,
+				Message:         "Your poll has ended",
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/repotest"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+func TestPollServiceTakesOneVotePerUserUntilThePollEnds(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	posts := &services.PostService{Repo: repos.Posts, Tx: repos.Tx}
+	now := time.Now()
+	polls := &services.PollService{Repo: repos.Polls, Posts: posts, Tx: repos.Tx, Notifications: repos.Notifications, Now: func() time.Time { return now }}
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+	carol := repotest.CreateUser(t, repos, "carol")
+
+	newPoll := func(endsAt time.Time, labels ...string) *models.ContentPost {
+		poll := &models.Poll{EndsAt: endsAt}
+		for _, l := range labels {
+			poll.Options = append(poll.Options, models.PollOption{Label: l})
+		}
+		return &models.ContentPost{UserID: alice, PostContent: "Best shift?", Poll: poll}
+	}
+	if _, err := polls.CreatePoll(ctx, newPoll(now.Add(-time.Minute), "Morning", "Night")); !errors.Is(err, services.ErrPollEndsInPast) {
+		t.Errorf("CreatePoll ending in the past error = %v; want ErrPollEndsInPast", err)
+	}
+	if _, err := polls.CreatePoll(ctx, newPoll(now.Add(time.Hour), "Night", "night")); !errors.Is(err, services.ErrDuplicatePollOption) {
+		t.Errorf("CreatePoll with the same option twice error = %v; want ErrDuplicatePollOption", err)
+	}
+	post, err := polls.CreatePoll(ctx, newPoll(now.Add(time.Hour), "Morning", "Night"))
+	if err != nil {
+		t.Fatalf("CreatePoll: %v", err)
+	}
+	morning, night := post.Poll.Options[0].ID, post.Poll.Options[1].ID
+
+	if _, err := polls.Vote(ctx, bob, post.ID, []uuid.UUID{morning, night}); !errors.Is(err, services.ErrSingleChoice) {
+		t.Errorf("Vote for 2 options error = %v; want ErrSingleChoice", err)
+	}
+	if _, err := polls.Vote(ctx, bob, post.ID, []uuid.UUID{uuid.New()}); !errors.Is(err, services.ErrUnknownPollOption) {
+		t.Errorf("Vote for another option error = %v; want ErrUnknownPollOption", err)
+	}
+	poll, err := polls.Vote(ctx, bob, post.ID, []uuid.UUID{night})
+	if err != nil || poll.TotalVoters != 1 || poll.Options[1].Votes != 1 || len(poll.Choices) != 1 {
+		t.Fatalf("Vote = %+v, %v; want bob's vote counted", poll, err)
+	}
+	if _, err := polls.Vote(ctx, bob, post.ID, []uuid.UUID{morning}); !errors.Is(err, services.ErrAlreadyVoted) {
+		t.Errorf("second Vote error = %v; want ErrAlreadyVoted", err)
+	}
+	if _, err := polls.GetPoll(ctx, bob, repotest.CreatePost(t, repos, alice, "No poll").ID); !errors.Is(err, services.ErrPollNotFound) {
+		t.Errorf("GetPoll of a text post error = %v; want ErrPollNotFound", err)
+	}
+
+	// Nothing has ended yet.
+	if err := polls.NotifyEnded(ctx); err != nil {
+		t.Fatalf("NotifyEnded: %v", err)
+	}
+	if got, _ := repos.Notifications.GetByUserID(ctx, alice, pagination.First()); len(got.Items) != 0 {
+		t.Fatalf("notifications before the end = %+v; want none", got.Items)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := polls.Vote(ctx, carol, post.ID, []uuid.UUID{morning}); !errors.Is(err, services.ErrPollClosed) {
+		t.Errorf("Vote after the end error = %v; want ErrPollClosed", err)
+	}
+	for range 2 {
+		if err := polls.NotifyEnded(ctx); err != nil {
+			t.Fatalf("NotifyEnded: %v", err)
+		}
+	}
+	notes, _ := repos.Notifications.GetByUserID(ctx, alice, pagination.First())
+	if len(notes.Items) != 1 || notes.Items[0].Type != services.NotificationPollEnded || notes.Items[0].EntityID != post.ID {
+		t.Errorf("notifications = %+v; want one for the ended poll", notes.Items)
+	}
+}
@@ -1,28 +1,87 @@
 package services
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
+// ErrPostNotFound is returned when the post being commented on does not exist.
+var ErrPostNotFound = apperrors.NotFound("post_not_found", "Post not found")
+
 type PostCommentService struct {
-	PostCommentRepository *repositories.PostCommentRepository
+	PostCommentRepository repositories.PostCommentRepository
+
+	// Entities, when set, parses the hashtags and mentions of comments in
+	// the transaction run by Tx that creates them.
+	Entities *EntityService
+	Tx       repositories.TxManager
+
+	// Posts, when set, hides the posts a user cannot see from them: they
+	// can neither read nor write comments on them.
+	Posts repositories.PostRepository
 }
 
-func (service *PostCommentService) CommentOnPost(userID, postID uuid.UUID, comment string) error {
-	exists, err := service.PostCommentRepository.PostExists(postID)
-	if err != nil {
+func (service *PostCommentService) CommentOnPost(ctx context.Context, userID, postID uuid.UUID, comment string) error {
+	ctx, span := tracing.Start(ctx, "PostCommentService.CommentOnPost")
+	defer span.End()
+
+	if err := service.checkPost(ctx, userID, postID); err != nil {
 		return err
 	}
-	if !exists {
-		return fmt.Errorf("post not found")
+	c := &models.PostComment{UserID: userID, PostID: postID, Comment: comment}
+	if service.Entities == nil {
+		return service.PostCommentRepository.CreateComment(ctx, c)
+	}
+	var err error
+	if c.Entities, err = service.Entities.Parse(ctx, comment); err != nil {
+		return err
+	}
+	create := func(ctx context.Context) error {
+		if err := service.PostCommentRepository.CreateComment(ctx, c); err != nil {
+			return err
+		}
+		return service.Entities.IndexComment(ctx, c)
+	}
+	if service.Tx == nil {
+		return create(ctx)
 	}
-	return service.PostCommentRepository.CreateComment(userID, postID, comment)
+	return service.Tx.WithTx(ctx, create)
 }
 
-func (service *PostCommentService) GetPostComments(postID uuid.UUID) ([]models.PostComment, error) {
-	return service.PostCommentRepository.GetComments(postID)
+// GetPostComments returns a page of the comments on a post viewerID can see.
+func (service *PostCommentService) GetPostComments(ctx context.Context, viewerID, postID uuid.UUID, page pagination.Params) (pagination.Page[models.PostComment], error) {
+	ctx, span := tracing.Start(ctx, "PostCommentService.GetPostComments")
+	defer span.End()
+
+	if err := service.checkPost(ctx, viewerID, postID); err != nil {
+		return pagination.Page[models.PostComment]{}, err
+	}
+	return service.PostCommentRepository.GetComments(ctx, postID, page)
+}
+
+// checkPost returns ErrPostNotFound unless postID exists and, when Posts is
+// set, viewerID can see it.
+func (service *PostCommentService) checkPost(ctx context.Context, viewerID, postID uuid.UUID) error {
+	var (
+		found bool
+		err   error
+	)
+	if service.Posts != nil {
+		found, err = service.Posts.CanView(ctx, viewerID, postID)
+	} else {
+		found, err = service.PostCommentRepository.PostExists(ctx, postID)
+	}
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrPostNotFound
+	}
+	return nil
 }
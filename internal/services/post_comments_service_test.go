@@ -0,0 +1,32 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/repotest"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+func TestPostCommentServiceCommentOnPost(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	svc := &services.PostCommentService{PostCommentRepository: repos.PostComments}
+	alice := repotest.CreateUser(t, repos, "alice")
+	post := repotest.CreatePost(t, repos, alice, "hello")
+
+	if err := svc.CommentOnPost(ctx, alice, uuid.New(), "lost"); err == nil {
+		t.Error("CommentOnPost on a missing post succeeded")
+	}
+
+	if err := svc.CommentOnPost(ctx, alice, post.ID, "first!"); err != nil {
+		t.Fatalf("CommentOnPost: %v", err)
+	}
+	comments, err := svc.GetPostComments(ctx, alice, post.ID, pagination.First())
+	if err != nil || len(comments.Items) != 1 {
+		t.Fatalf("GetPostComments = %v, %v; want one comment", comments, err)
+	}
+}
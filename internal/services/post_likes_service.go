@@ -1,25 +1,70 @@
 package services
 
 import (
+	"context"
+
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
 type PostLikeService struct {
-	PostLikeRepository *repositories.PostLikeRepository
+	PostLikeRepository repositories.PostLikeRepository
+	Metrics            *metrics.Metrics
+
+	// Posts, when set, hides the posts a user cannot see from them: they
+	// can neither like them nor list their likes.
+	Posts repositories.PostRepository
 }
 
 // LikePost allows a user to like a post
-func (service *PostLikeService) LikePost(userID, postID uuid.UUID) error {
-	return service.PostLikeRepository.CreateLike(userID, postID)
+func (service *PostLikeService) LikePost(ctx context.Context, userID, postID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "PostLikeService.LikePost")
+	defer span.End()
+
+	if err := service.checkPost(ctx, userID, postID); err != nil {
+		return err
+	}
+	if err := service.PostLikeRepository.CreateLike(ctx, userID, postID); err != nil {
+		return err
+	}
+	service.Metrics.PostLiked()
+	return nil
 }
 
 // UnlikePost allows a user to remove a like from a post
-func (service *PostLikeService) UnlikePost(userID, postID uuid.UUID) error {
-	return service.PostLikeRepository.RemoveLike(userID, postID)
+func (service *PostLikeService) UnlikePost(ctx context.Context, userID, postID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "PostLikeService.UnlikePost")
+	defer span.End()
+
+	return service.PostLikeRepository.RemoveLike(ctx, userID, postID)
+}
+
+// GetPostLikes retrieves a page of the likes of a post viewerID can see
+func (service *PostLikeService) GetPostLikes(ctx context.Context, viewerID, postID uuid.UUID, page pagination.Params) (pagination.Page[uuid.UUID], error) {
+	ctx, span := tracing.Start(ctx, "PostLikeService.GetPostLikes")
+	defer span.End()
+
+	if err := service.checkPost(ctx, viewerID, postID); err != nil {
+		return pagination.Page[uuid.UUID]{}, err
+	}
+	return service.PostLikeRepository.GetLikes(ctx, postID, page)
 }
 
-// GetPostLikes retrieves all likes for a specific post
-func (service *PostLikeService) GetPostLikes(postID uuid.UUID) ([]uuid.UUID, error) {
-	return service.PostLikeRepository.GetLikes(postID)
+// checkPost returns ErrPostNotFound when Posts is set and viewerID cannot
+// see postID.
+func (service *PostLikeService) checkPost(ctx context.Context, viewerID, postID uuid.UUID) error {
+	if service.Posts == nil {
+		return nil
+	}
+	visible, err := service.Posts.CanView(ctx, viewerID, postID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return ErrPostNotFound
+	}
+	return nil
 }
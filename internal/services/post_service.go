@@ -5,37 +5,371 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+)
+
+var (
+	// ErrNoPostsForUser is returned when a user has not posted anything yet.
+	ErrNoPostsForUser = apperrors.NotFound("posts_not_found", "No posts found for this user")
+	// ErrNotPostAuthor is returned when a user edits or deletes a post they
+	// may not change.
+	ErrNotPostAuthor = apperrors.Forbidden("not_post_author", "Only the author can change this post")
+	// ErrRepostNotEditable is returned when a user edits a repost, which
+	// has no text of its own.
+	ErrRepostNotEditable = apperrors.Unprocessable("repost_not_editable", "A repost has no text to edit")
+	// ErrAlreadyReposted is returned when a user reposts a post twice.
+	ErrAlreadyReposted = apperrors.Conflict("already_reposted", "You have already reposted this post")
+	// ErrRepostNotFound is returned when a user undoes a repost they did
+	// not make.
+	ErrRepostNotFound = apperrors.NotFound("repost_not_found", "You have not reposted this post")
+	// ErrPostNotShareable is returned when a user reposts or quotes a post
+	// that is not public.
+	ErrPostNotShareable = apperrors.Forbidden("post_not_shareable", "Only public posts can be shared")
+)
+
+// Notification types of shares.
+const (
+	NotificationRepost = "repost"
+	NotificationQuote  = "quote"
 )
 
 type PostService struct {
-	Repo *repositories.PostRepository
+	Repo    repositories.PostRepository
+	Metrics *metrics.Metrics
+
+	// Feed, when set, adds new posts to the home timelines of the author's
+	// followers in the transaction run by Tx that creates them.
+	Feed *FeedService
+	Tx   repositories.TxManager
+
+	// Entities, when set, parses the hashtags and mentions of posts as they
+	// are written, indexes the hashtags and notifies mentioned users.
+	Entities *EntityService
+
+	// Notifications, when set, tells authors their posts were shared.
+	Notifications repositories.NotificationRepository
+
+	// Profiles, when set, gives new posts without a visibility the default
+	// visibility of their author; they are public otherwise.
+	Profiles repositories.UserProfileRepository
 }
 
-func NewPostService(repo *repositories.PostRepository) *PostService {
+func NewPostService(repo repositories.PostRepository) *PostService {
 	return &PostService{Repo: repo}
 }
 
 func (s *PostService) CreatePost(ctx context.Context, p *models.ContentPost) (*models.ContentPost, error) {
-	createdPost, err := s.Repo.CreatePost(ctx, p)
+	ctx, span := tracing.Start(ctx, "PostService.CreatePost")
+	defer span.End()
+
+	return s.create(ctx, p, nil)
+}
+
+// create stores p and publishes it; then, in the same transaction, runs
+// after when it is set.
+func (s *PostService) create(ctx context.Context, p *models.ContentPost, after func(ctx context.Context, created *models.ContentPost) error) (*models.ContentPost, error) {
+	if p.Visibility == "" {
+		var err error
+		if p.Visibility, err = s.defaultVisibility(ctx, p.UserID); err != nil {
+			return nil, err
+		}
+	}
+	if s.Entities != nil {
+		var err error
+		if p.Entities, err = s.Entities.Parse(ctx, p.PostContent); err != nil {
+			return nil, err
+		}
+	}
+
+	var createdPost *models.ContentPost
+	err := s.withTx(ctx, func(ctx context.Context) error {
+		var err error
+		createdPost, err = s.Repo.CreatePost(ctx, p)
+		if err != nil {
+			return err
+		}
+		if s.Entities != nil {
+			if err := s.Entities.IndexPost(ctx, createdPost, nil); err != nil {
+				return err
+			}
+		}
+		if s.Feed != nil {
+			if err := s.Feed.Publish(ctx, createdPost); err != nil {
+				return err
+			}
+		}
+		if after == nil {
+			return nil
+		}
+		return after(ctx, createdPost)
+	})
 	if err != nil {
 		return nil, err
 	}
+	s.Metrics.PostCreated()
 	return createdPost, nil
 }
 
-func (s *PostService) GetAllContentPosts(ctx context.Context) ([]models.PostWithDetails, error) {
-	return s.Repo.GetAllWithDetails(ctx)
+// defaultVisibility returns the visibility userID picked for new posts.
+func (s *PostService) defaultVisibility(ctx context.Context, userID uuid.UUID) (string, error) {
+	if s.Profiles == nil {
+		return models.VisibilityPublic, nil
+	}
+	profile, err := s.Profiles.GetByUserID(ctx, userID.String())
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return models.VisibilityPublic, nil
+	case err != nil:
+		return "", err
+	}
+	return profile.DefaultVisibility, nil
+}
+
+// withTx runs fn in a transaction when Tx is set.
+func (s *PostService) withTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.Tx == nil {
+		return fn(ctx)
+	}
+	return s.Tx.WithTx(ctx, fn)
+}
+
+// GetAllContentPosts returns a page of the posts of every user that
+// viewerID can see.
+func (s *PostService) GetAllContentPosts(ctx context.Context, viewerID uuid.UUID, page pagination.Params) (pagination.Page[models.PostWithDetails], error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetAllContentPosts")
+	defer span.End()
+
+	return s.Repo.GetAllWithDetails(ctx, viewerID, page)
 }
 
-func (s *PostService) GetPostsByUserID(userID uuid.UUID) ([]models.ContentPost, error) {
-	posts, err := s.Repo.GetPostsByUserID(userID)
+// GetPostsByUserID returns a page of the posts of userID that viewerID can
+// see. The first page is ErrNoPostsForUser when there are none.
+func (s *PostService) GetPostsByUserID(ctx context.Context, viewerID, userID uuid.UUID, page pagination.Params) (pagination.Page[models.ContentPost], error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetPostsByUserID")
+	defer span.End()
+
+	posts, err := s.Repo.GetPostsByUserID(ctx, viewerID, userID, page)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.ContentPost]{}, err
 	}
-	if len(posts) == 0 {
-		return nil, errors.New("no posts found for this user")
+	if len(posts.Items) == 0 && page.After == nil {
+		return pagination.Page[models.ContentPost]{}, ErrNoPostsForUser
 	}
 	return posts, nil
 }
+
+// GetPost returns a post with the poll choices of viewerID, or
+// ErrPostNotFound. It does not check that viewerID can see the post.
+func (s *PostService) GetPost(ctx context.Context, viewerID, postID uuid.UUID) (*models.ContentPost, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetPost")
+	defer span.End()
+
+	post, err := s.Repo.GetPostByID(ctx, viewerID, postID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	return post, err
+}
+
+// visiblePost returns a post viewerID can see, or ErrPostNotFound, which
+// does not tell hidden posts from missing ones.
+func (s *PostService) visiblePost(ctx context.Context, viewerID, postID uuid.UUID) (*models.ContentPost, error) {
+	visible, err := s.Repo.CanView(ctx, viewerID, postID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrPostNotFound
+	}
+	return s.GetPost(ctx, viewerID, postID)
+}
+
+// UpdatePost replaces the text of a post by userID. The text it replaces is
+// kept as a revision; only users it did not mention are notified. Posts
+// userID cannot see are ErrPostNotFound rather than ErrNotPostAuthor.
+func (s *PostService) UpdatePost(ctx context.Context, userID, postID uuid.UUID, content string) (*models.ContentPost, error) {
+	ctx, span := tracing.Start(ctx, "PostService.UpdatePost")
+	defer span.End()
+
+	post, err := s.visiblePost(ctx, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID {
+		return nil, ErrNotPostAuthor
+	}
+	if post.ShareType == models.ShareRepost {
+		return nil, ErrRepostNotEditable
+	}
+
+	var parsed []models.Entity
+	if s.Entities != nil {
+		if parsed, err = s.Entities.Parse(ctx, content); err != nil {
+			return nil, err
+		}
+	}
+
+	var updated *models.ContentPost
+	err = s.withTx(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = s.Repo.UpdatePost(ctx, userID, postID, content, parsed)
+		if err != nil || s.Entities == nil {
+			return err
+		}
+		return s.Entities.IndexPost(ctx, updated, post.Entities)
+	})
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	return updated, err
+}
+
+// DeletePost deletes a post by userID, or any post when asAdmin is set,
+// with its likes, comments, revisions and reposts. Other users' posts that
+// userID cannot see are ErrPostNotFound. It returns the deleted post so the
+// caller can remove its media.
+func (s *PostService) DeletePost(ctx context.Context, userID, postID uuid.UUID, asAdmin bool) (*models.ContentPost, error) {
+	ctx, span := tracing.Start(ctx, "PostService.DeletePost")
+	defer span.End()
+
+	load := s.visiblePost
+	if asAdmin {
+		load = s.GetPost
+	}
+	post, err := load(ctx, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID && !asAdmin {
+		return nil, ErrNotPostAuthor
+	}
+
+	if err := s.withTx(ctx, func(ctx context.Context) error { return s.Repo.DeletePost(ctx, postID) }); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	return post, nil
+}
+
+// SetVisibility changes who can see a post by userID. Posts userID cannot
+// see are ErrPostNotFound rather than ErrNotPostAuthor.
+func (s *PostService) SetVisibility(ctx context.Context, userID, postID uuid.UUID, visibility string) (*models.ContentPost, error) {
+	ctx, span := tracing.Start(ctx, "PostService.SetVisibility")
+	defer span.End()
+
+	post, err := s.visiblePost(ctx, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID {
+		return nil, ErrNotPostAuthor
+	}
+
+	updated, err := s.Repo.SetVisibility(ctx, userID, postID, visibility)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	return updated, err
+}
+
+// GetRevisions returns a page of the earlier versions of a post viewerID
+// can see, latest first.
+func (s *PostService) GetRevisions(ctx context.Context, viewerID, postID uuid.UUID, page pagination.Params) (pagination.Page[models.PostRevision], error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetRevisions")
+	defer span.End()
+
+	if _, err := s.visiblePost(ctx, viewerID, postID); err != nil {
+		return pagination.Page[models.PostRevision]{}, err
+	}
+	return s.Repo.GetRevisions(ctx, postID, page)
+}
+
+// Repost shares postID with the followers of userID as is. Reposting a
+// repost shares the post it reposts. Only public posts can be shared.
+func (s *PostService) Repost(ctx context.Context, userID, postID uuid.UUID) (*models.ContentPost, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Repost")
+	defer span.End()
+
+	post, err := s.share(ctx, userID, postID, models.ShareRepost, "")
+	if errors.Is(err, apperrors.ErrConflict) {
+		return nil, ErrAlreadyReposted
+	}
+	return post, err
+}
+
+// Quote shares postID with the followers of userID together with content.
+// Quoting a repost quotes the post it reposts.
+func (s *PostService) Quote(ctx context.Context, userID, postID uuid.UUID, content string) (*models.ContentPost, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Quote")
+	defer span.End()
+
+	return s.share(ctx, userID, postID, models.ShareQuote, content)
+}
+
+func (s *PostService) share(ctx context.Context, userID, postID uuid.UUID, shareType, content string) (*models.ContentPost, error) {
+	original, err := s.visiblePost(ctx, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	if original.ShareType == models.ShareRepost {
+		if original.SharedPostID == nil {
+			return nil, ErrPostNotFound
+		}
+		if original, err = s.visiblePost(ctx, userID, *original.SharedPostID); err != nil {
+			return nil, err
+		}
+	}
+	if original.Visibility != models.VisibilityPublic {
+		return nil, ErrPostNotShareable
+	}
+
+	p := &models.ContentPost{UserID: userID, PostContent: content, SharedPostID: &original.ID, ShareType: shareType}
+	post, err := s.create(ctx, p, func(ctx context.Context, created *models.ContentPost) error {
+		return s.notifyShare(ctx, original, created)
+	})
+	if errors.Is(err, apperrors.ErrNotFound) {
+		// The original was deleted meanwhile.
+		return nil, ErrPostNotFound
+	}
+	return post, err
+}
+
+// notifyShare tells the author of original that share shares it, unless
+// they shared it themselves.
+func (s *PostService) notifyShare(ctx context.Context, original, share *models.ContentPost) error {
+	if s.Notifications == nil || original.UserID == share.UserID {
+		return nil
+	}
+	n := &models.Notification{
+		ID:              uuid.New(),
+		RecipientUserID: original.UserID,
+		SenderUserID:    share.UserID,
+		Type:            NotificationRepost,
+		EntityID:        share.ID,
+		EntityType:      EntityTypePost,
+		Message:         "Your post was reposted",
+	}
+	if share.ShareType == models.ShareQuote {
+		n.Type, n.Message = NotificationQuote, "Your post was quoted"
+	}
+	return s.Notifications.Create(ctx, n)
+}
+
+// Unrepost deletes the repost of postID by userID.
+func (s *PostService) Unrepost(ctx context.Context, userID, postID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "PostService.Unrepost")
+	defer span.End()
+
+	err := s.Repo.DeleteRepost(ctx, userID, postID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return ErrRepostNotFound
+	}
+	return err
+}
@@ -0,0 +1,244 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/repotest"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+)
+
+func TestPostServiceGetPostsByUserID(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	svc := services.NewPostService(repos.Posts)
+	alice := repotest.CreateUser(t, repos, "alice")
+
+	if _, err := svc.GetPostsByUserID(ctx, alice, alice, pagination.First()); err == nil {
+		t.Fatal("GetPostsByUserID without posts succeeded")
+	}
+
+	created, err := svc.CreatePost(ctx, &models.ContentPost{UserID: alice, PostContent: "Hiring chefs!"})
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+
+	posts, err := svc.GetPostsByUserID(ctx, alice, alice, pagination.First())
+	if err != nil || len(posts.Items) != 1 || posts.Items[0].ID != created.ID {
+		t.Fatalf("GetPostsByUserID = %v, %v; want the created post", posts, err)
+	}
+}
+
+func TestPostServiceGetAllContentPosts(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	svc := services.NewPostService(repos.Posts)
+	alice := repotest.CreateUser(t, repos, "alice")
+	repotest.CreateProfile(t, repos, alice, "Alice Example")
+
+	if _, err := svc.CreatePost(ctx, &models.ContentPost{UserID: alice, PostContent: "Hello"}); err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+
+	feed, err := svc.GetAllContentPosts(ctx, alice, pagination.First())
+	if err != nil || len(feed.Items) != 1 || feed.Items[0].FullName != "Alice Example" {
+		t.Fatalf("GetAllContentPosts = %v, %v; want one post by Alice", feed, err)
+	}
+}
+
+func TestPostServiceEditAndDeleteAreForTheAuthor(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	svc := services.NewPostService(repos.Posts)
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+	post := repotest.CreatePost(t, repos, alice, "Hirign chefs!")
+
+	if _, err := svc.UpdatePost(ctx, bob, post.ID, "Spam"); !errors.Is(err, services.ErrNotPostAuthor) {
+		t.Errorf("UpdatePost by another user error = %v; want ErrNotPostAuthor", err)
+	}
+	edited, err := svc.UpdatePost(ctx, alice, post.ID, "Hiring chefs!")
+	if err != nil || edited.PostContent != "Hiring chefs!" || edited.EditedAt == nil {
+		t.Fatalf("UpdatePost = %+v, %v; want the edited post", edited, err)
+	}
+	revisions, err := svc.GetRevisions(ctx, alice, post.ID, pagination.First())
+	if err != nil || len(revisions.Items) != 1 || revisions.Items[0].PostContent != "Hirign chefs!" {
+		t.Errorf("GetRevisions = %+v, %v; want the original text", revisions, err)
+	}
+
+	if _, err := svc.DeletePost(ctx, bob, post.ID, false); !errors.Is(err, services.ErrNotPostAuthor) {
+		t.Errorf("DeletePost by another user error = %v; want ErrNotPostAuthor", err)
+	}
+	if _, err := svc.DeletePost(ctx, bob, post.ID, true); err != nil {
+		t.Fatalf("DeletePost by an admin: %v", err)
+	}
+	if _, err := svc.GetRevisions(ctx, alice, post.ID, pagination.First()); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("GetRevisions of a deleted post error = %v; want ErrPostNotFound", err)
+	}
+	if _, err := svc.DeletePost(ctx, alice, post.ID, false); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("DeletePost twice error = %v; want ErrPostNotFound", err)
+	}
+}
+
+func TestPostServiceSharesReachFollowersAndNotifyTheAuthor(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	feed := &services.FeedService{Timelines: repos.Timelines, Posts: repos.Posts}
+	posts := &services.PostService{Repo: repos.Posts, Feed: feed, Tx: repos.Tx, Notifications: repos.Notifications}
+	follows := &services.FollowService{FollowRepository: repos.Follows, Tx: repos.Tx, Feed: feed}
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+	carol := repotest.CreateUser(t, repos, "carol")
+	for id, name := range map[uuid.UUID]string{alice: "Alice", bob: "Bob", carol: "Carol"} {
+		repotest.CreateProfile(t, repos, id, name)
+	}
+	if err := follows.FollowUser(ctx, carol, bob); err != nil {
+		t.Fatalf("FollowUser: %v", err)
+	}
+	original := repotest.CreatePost(t, repos, alice, "Grand opening")
+
+	repost, err := posts.Repost(ctx, bob, original.ID)
+	if err != nil {
+		t.Fatalf("Repost: %v", err)
+	}
+	if _, err := posts.Repost(ctx, bob, original.ID); !errors.Is(err, services.ErrAlreadyReposted) {
+		t.Errorf("second Repost error = %v; want ErrAlreadyReposted", err)
+	}
+	if _, err := posts.UpdatePost(ctx, bob, repost.ID, "mine now"); !errors.Is(err, services.ErrRepostNotEditable) {
+		t.Errorf("UpdatePost of a repost error = %v; want ErrRepostNotEditable", err)
+	}
+	// Quoting the repost quotes the original.
+	quote, err := posts.Quote(ctx, carol, repost.ID, "Can't wait")
+	if err != nil || *quote.SharedPostID != original.ID {
+		t.Fatalf("Quote of a repost = %+v, %v; want a quote of the original", quote, err)
+	}
+
+	home, err := feed.Home(ctx, carol, pagination.First())
+	if err != nil || len(home.Page.Items) != 2 || home.Page.Items[1].PostID != repost.ID ||
+		home.Page.Items[1].SharedPost == nil || home.Page.Items[1].SharedPost.PostContent != "Grand opening" {
+		t.Fatalf("Home of a follower = %+v, %v; want bob's repost with the original embedded", home.Page.Items, err)
+	}
+
+	notes, err := repos.Notifications.GetByUserID(ctx, alice, pagination.First())
+	if err != nil || len(notes.Items) != 2 || notes.Items[0].Type != services.NotificationQuote || notes.Items[1].Type != services.NotificationRepost {
+		t.Errorf("notifications of the author = %+v, %v; want a repost and a quote", notes.Items, err)
+	}
+	if _, err := posts.Repost(ctx, alice, original.ID); err != nil {
+		t.Fatalf("Repost by the author: %v", err)
+	}
+	if notes, _ := repos.Notifications.GetByUserID(ctx, alice, pagination.First()); len(notes.Items) != 2 {
+		t.Errorf("the author was notified of their own repost: %+v", notes.Items)
+	}
+
+	if err := posts.Unrepost(ctx, bob, original.ID); err != nil {
+		t.Fatalf("Unrepost: %v", err)
+	}
+	if err := posts.Unrepost(ctx, bob, original.ID); !errors.Is(err, services.ErrRepostNotFound) {
+		t.Errorf("second Unrepost error = %v; want ErrRepostNotFound", err)
+	}
+}
+
+func TestPostServiceHiddenPostsAreNotFoundForStrangers(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	svc := &services.PostService{Repo: repos.Posts, Tx: repos.Tx}
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+	post, err := svc.CreatePost(ctx, &models.ContentPost{UserID: alice, PostContent: "Note to self", Visibility: models.VisibilityOnlyMe})
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+
+	if _, err := svc.UpdatePost(ctx, bob, post.ID, "Spam"); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("UpdatePost by a stranger error = %v; want ErrPostNotFound", err)
+	}
+	if _, err := svc.DeletePost(ctx, bob, post.ID, false); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("DeletePost by a stranger error = %v; want ErrPostNotFound", err)
+	}
+	if _, err := svc.SetVisibility(ctx, bob, post.ID, models.VisibilityPublic); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("SetVisibility by a stranger error = %v; want ErrPostNotFound", err)
+	}
+	if _, err := svc.DeletePost(ctx, bob, post.ID, true); err != nil {
+		t.Errorf("DeletePost by an admin: %v", err)
+	}
+}
+
+func TestPostServiceVisibilityLimitsTheAudience(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	entities := &services.EntityService{Users: repos.Users, Hashtags: repos.Hashtags, Notifications: repos.Notifications, Posts: repos.Posts}
+	posts := &services.PostService{Repo: repos.Posts, Tx: repos.Tx, Entities: entities, Profiles: repos.UserProfiles}
+	comments := &services.PostCommentService{PostCommentRepository: repos.PostComments, Posts: repos.Posts}
+	likes := &services.PostLikeService{PostLikeRepository: repos.PostLikes, Posts: repos.Posts}
+	alice := repotest.CreateUser(t, repos, "alice")
+	bob := repotest.CreateUser(t, repos, "bob")
+	carol := repotest.CreateUser(t, repos, "carol")
+	profile := repotest.CreateProfile(t, repos, alice, "Alice")
+	profile.DefaultVisibility = models.VisibilityFollowers
+	if _, err := repos.UserProfiles.Update(ctx, alice.String(), profile); err != nil {
+		t.Fatalf("Update profile: %v", err)
+	}
+	if err := repos.Follows.FollowUser(ctx, bob, alice); err != nil {
+		t.Fatalf("FollowUser: %v", err)
+	}
+
+	post, err := posts.CreatePost(ctx, &models.ContentPost{UserID: alice, PostContent: "Staff party, @bob and @carol!"})
+	if err != nil || post.Visibility != models.VisibilityFollowers {
+		t.Fatalf("CreatePost = %+v, %v; want the author's default visibility", post, err)
+	}
+	for _, c := range []struct {
+		name string
+		user uuid.UUID
+		want int
+	}{{"follower", bob, 1}, {"stranger", carol, 0}} {
+		if got, _ := repos.Notifications.GetByUserID(ctx, c.user, pagination.First()); len(got.Items) != c.want {
+			t.Errorf("mentions of the %s = %+v; want %d", c.name, got.Items, c.want)
+		}
+	}
+
+	// The post is hidden from carol as if it did not exist.
+	if err := comments.CommentOnPost(ctx, carol, post.ID, "Hi"); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("CommentOnPost by a stranger error = %v; want ErrPostNotFound", err)
+	}
+	if _, err := comments.GetPostComments(ctx, carol, post.ID, pagination.First()); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("GetPostComments by a stranger error = %v; want ErrPostNotFound", err)
+	}
+	if err := likes.LikePost(ctx, carol, post.ID); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("LikePost by a stranger error = %v; want ErrPostNotFound", err)
+	}
+	if _, err := likes.GetPostLikes(ctx, carol, post.ID, pagination.First()); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("GetPostLikes by a stranger error = %v; want ErrPostNotFound", err)
+	}
+	if _, err := posts.Repost(ctx, carol, post.ID); !errors.Is(err, services.ErrPostNotFound) {
+		t.Errorf("Repost by a stranger error = %v; want ErrPostNotFound", err)
+	}
+	if _, err := posts.GetPostsByUserID(ctx, carol, alice, pagination.First()); !errors.Is(err, services.ErrNoPostsForUser) {
+		t.Errorf("GetPostsByUserID by a stranger error = %v; want ErrNoPostsForUser", err)
+	}
+
+	// Followers can interact with it but not share it.
+	if err := comments.CommentOnPost(ctx, bob, post.ID, "See you there"); err != nil {
+		t.Errorf("CommentOnPost by a follower: %v", err)
+	}
+	if err := likes.LikePost(ctx, bob, post.ID); err != nil {
+		t.Errorf("LikePost by a follower: %v", err)
+	}
+	if _, err := posts.Repost(ctx, bob, post.ID); !errors.Is(err, services.ErrPostNotShareable) {
+		t.Errorf("Repost of a followers-only post error = %v; want ErrPostNotShareable", err)
+	}
+
+	if _, err := posts.SetVisibility(ctx, bob, post.ID, models.VisibilityPublic); !errors.Is(err, services.ErrNotPostAuthor) {
+		t.Errorf("SetVisibility by another user error = %v; want ErrNotPostAuthor", err)
+	}
+	if _, err := posts.SetVisibility(ctx, alice, post.ID, models.VisibilityPublic); err != nil {
+		t.Fatalf("SetVisibility: %v", err)
+	}
+	if _, err := posts.Repost(ctx, carol, post.ID); err != nil {
+		t.Errorf("Repost of a public post: %v", err)
+	}
+}
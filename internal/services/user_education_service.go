@@ -6,24 +6,37 @@ import (
 	"github.com/google/uuid"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
 type UserEducationService struct {
-	Repo *repositories.UserEducationRepository
+	Repo repositories.UserEducationRepository
 }
 
 func (s *UserEducationService) Create(ctx context.Context, edu *models.UserEducation) error {
-	return s.Repo.Create(edu)
+	ctx, span := tracing.Start(ctx, "UserEducationService.Create")
+	defer span.End()
+
+	return s.Repo.Create(ctx, edu)
 }
 
 func (s *UserEducationService) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserEducation, error) {
-	return s.Repo.GetByUserID(userID)
+	ctx, span := tracing.Start(ctx, "UserEducationService.GetByUserID")
+	defer span.End()
+
+	return s.Repo.GetByUserID(ctx, userID)
 }
 
 func (s *UserEducationService) Update(ctx context.Context, edu *models.UserEducation) error {
-	return s.Repo.Update(edu)
+	ctx, span := tracing.Start(ctx, "UserEducationService.Update")
+	defer span.End()
+
+	return s.Repo.Update(ctx, edu)
 }
 
 func (s *UserEducationService) Delete(ctx context.Context, eduID uuid.UUID) error {
-	return s.Repo.Delete(eduID)
+	ctx, span := tracing.Start(ctx, "UserEducationService.Delete")
+	defer span.End()
+
+	return s.Repo.Delete(ctx, eduID)
 }
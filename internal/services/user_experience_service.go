@@ -6,30 +6,43 @@ import (
 	"github.com/google/uuid"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
 type UserExperienceService struct {
-	UserExperienceRepository *repositories.UserExperienceRepository
+	UserExperienceRepository repositories.UserExperienceRepository
 }
 
-func NewUserExperienceService(repo *repositories.UserExperienceRepository) *UserExperienceService {
+func NewUserExperienceService(repo repositories.UserExperienceRepository) *UserExperienceService {
 	return &UserExperienceService{UserExperienceRepository: repo}
 }
 
 func (s *UserExperienceService) Create(ctx context.Context, exp *models.UserExperience) error {
+	ctx, span := tracing.Start(ctx, "UserExperienceService.Create")
+	defer span.End()
+
 	// Call the repository to save the user experience
 	return s.UserExperienceRepository.Create(ctx, exp)
 }
 
 func (s *UserExperienceService) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.UserExperience, error) {
+	ctx, span := tracing.Start(ctx, "UserExperienceService.GetByUserID")
+	defer span.End()
+
 	// Fetch user experiences by user ID from the repository
 	return s.UserExperienceRepository.GetByUserID(ctx, userID)
 }
 
 func (s *UserExperienceService) Update(ctx context.Context, exp *models.UserExperience) error {
+	ctx, span := tracing.Start(ctx, "UserExperienceService.Update")
+	defer span.End()
+
 	return s.UserExperienceRepository.Update(ctx, exp)
 }
 
 func (s *UserExperienceService) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "UserExperienceService.Delete")
+	defer span.End()
+
 	return s.UserExperienceRepository.Delete(ctx, id)
 }
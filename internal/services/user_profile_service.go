@@ -2,27 +2,40 @@ package services
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
+// ErrProfileNotFound is returned when a user has no profile.
+var ErrProfileNotFound = apperrors.NotFound("profile_not_found", "Profile not found")
+
 type UserProfileService struct {
-	Repo *repositories.UserProfileRepository
+	Repo repositories.UserProfileRepository
 }
 
-func NewUserProfileService(repo *repositories.UserProfileRepository) *UserProfileService {
+func NewUserProfileService(repo repositories.UserProfileRepository) *UserProfileService {
 	return &UserProfileService{Repo: repo}
 }
 
 func (s *UserProfileService) Create(ctx context.Context, profile *models.UserProfile) (*models.UserProfile, error) {
+	ctx, span := tracing.Start(ctx, "UserProfileService.Create")
+	defer span.End()
+
 	profile.ID = uuid.New()
 	profile.CreatedAt = time.Now()
 	profile.UpdatedAt = time.Now()
+	if profile.DefaultVisibility == "" {
+		profile.DefaultVisibility = models.VisibilityPublic
+	}
 
-	err := s.Repo.Create(profile)
+	err := s.Repo.Create(ctx, profile)
 	if err != nil {
 		return nil, err
 	}
@@ -30,18 +43,34 @@ func (s *UserProfileService) Create(ctx context.Context, profile *models.UserPro
 }
 
 func (s *UserProfileService) GetByUserID(ctx context.Context, userID string) (*models.UserProfile, error) {
-	return s.Repo.GetByUserID(userID)
+	ctx, span := tracing.Start(ctx, "UserProfileService.GetByUserID")
+	defer span.End()
+
+	profile, err := s.Repo.GetByUserID(ctx, userID)
+	if errors.Is(err, apperrors.ErrNotFound) || errors.Is(err, apperrors.ErrValidation) {
+		return nil, ErrProfileNotFound
+	}
+	return profile, err
 }
 
-func (s *UserProfileService) GetAll(ctx context.Context) ([]*models.UserProfile, error) {
-	return s.Repo.GetAll()
+func (s *UserProfileService) GetAll(ctx context.Context, page pagination.Params) (pagination.Page[*models.UserProfile], error) {
+	ctx, span := tracing.Start(ctx, "UserProfileService.GetAll")
+	defer span.End()
+
+	return s.Repo.GetAll(ctx, page)
 }
 
 func (s *UserProfileService) Update(ctx context.Context, userID string, updated *models.UserProfile) (*models.UserProfile, error) {
+	ctx, span := tracing.Start(ctx, "UserProfileService.Update")
+	defer span.End()
+
 	updated.UpdatedAt = time.Now()
-	return s.Repo.Update(userID, updated)
+	return s.Repo.Update(ctx, userID, updated)
 }
 
 func (s *UserProfileService) Delete(ctx context.Context, userID string) error {
-	return s.Repo.Delete(userID)
+	ctx, span := tracing.Start(ctx, "UserProfileService.Delete")
+	defer span.End()
+
+	return s.Repo.Delete(ctx, userID)
 }
@@ -6,24 +6,37 @@ import (
 	"github.com/google/uuid"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
 	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
 type VideoProfileService struct {
-	Repo *repositories.VideoProfileRepository
+	Repo repositories.VideoProfileRepository
 }
 
 func (s *VideoProfileService) Create(ctx context.Context, video *models.VideoProfile) error {
-	return s.Repo.Create(video)
+	ctx, span := tracing.Start(ctx, "VideoProfileService.Create")
+	defer span.End()
+
+	return s.Repo.Create(ctx, video)
 }
 
 func (s *VideoProfileService) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.VideoProfile, error) {
-	return s.Repo.GetByUserID(userID)
+	ctx, span := tracing.Start(ctx, "VideoProfileService.GetByUserID")
+	defer span.End()
+
+	return s.Repo.GetByUserID(ctx, userID)
 }
 
 func (s *VideoProfileService) Update(ctx context.Context, video *models.VideoProfile) error {
-	return s.Repo.Update(video)
+	ctx, span := tracing.Start(ctx, "VideoProfileService.Update")
+	defer span.End()
+
+	return s.Repo.Update(ctx, video)
 }
 
 func (s *VideoProfileService) Delete(ctx context.Context, videoID uuid.UUID) error {
-	return s.Repo.Delete(videoID)
+	ctx, span := tracing.Start(ctx, "VideoProfileService.Delete")
+	defer span.End()
+
+	return s.Repo.Delete(ctx, videoID)
 }
@@ -0,0 +1,99 @@
+// Package session sets the cookies of browser sessions: the HttpOnly "token"
+// cookie holding the access token and the "csrf_token" cookie that scripts
+// echo back in the X-CSRF-Token header (double-submit CSRF protection).
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+)
+
+const (
+	// TokenCookie holds the access token of a browser session.
+	TokenCookie = "token"
+	// CSRFCookie holds the CSRF token, readable by scripts.
+	CSRFCookie = "csrf_token"
+	// CSRFHeader must repeat the CSRF cookie on unsafe requests authenticated
+	// by the token cookie.
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// Cookies sets session cookies with the attributes of the environment.
+type Cookies struct {
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// NewCookies derives the cookie attributes from the configuration. Cookies
+// are Secure in production and SameSite=Lax unless COOKIE_SAMESITE says
+// otherwise; SameSite=None, needed by clients on another site, implies
+// Secure as browsers reject it without.
+func NewCookies(cfg config.Config) Cookies {
+	c := Cookies{Domain: cfg.COOKIEDOMAIN, Secure: cfg.IsProduction(), SameSite: http.SameSiteLaxMode}
+	switch strings.ToLower(strings.TrimSpace(cfg.CookieSameSite)) {
+	case "strict":
+		c.SameSite = http.SameSiteStrictMode
+	case "none":
+		c.SameSite = http.SameSiteNoneMode
+		c.Secure = true
+	}
+	return c
+}
+
+// Start sets the cookies of a new session lasting maxAge seconds and
+// returns its CSRF token.
+func (c Cookies) Start(ctx *gin.Context, token string, maxAge int) (string, error) {
+	csrf, err := NewCSRFToken()
+	if err != nil {
+		return "", err
+	}
+	c.set(ctx, TokenCookie, token, maxAge, true)
+	c.set(ctx, CSRFCookie, csrf, maxAge, false)
+	return csrf, nil
+}
+
+// End clears the session cookies.
+func (c Cookies) End(ctx *gin.Context) {
+	c.set(ctx, TokenCookie, "", -1, true)
+	c.set(ctx, CSRFCookie, "", -1, false)
+}
+
+// IssueCSRF sets a fresh CSRF cookie for a session that has none, such as
+// one started before CSRF protection existed.
+func (c Cookies) IssueCSRF(ctx *gin.Context) error {
+	csrf, err := NewCSRFToken()
+	if err != nil {
+		return err
+	}
+	c.set(ctx, CSRFCookie, csrf, 0, false)
+	return nil
+}
+
+func (c Cookies) set(ctx *gin.Context, name, value string, maxAge int, httpOnly bool) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   c.Domain,
+		MaxAge:   maxAge,
+		Secure:   c.Secure,
+		HttpOnly: httpOnly,
+		SameSite: c.SameSite,
+	})
+}
+
+// NewCSRFToken returns a random, URL-safe token.
+func NewCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("session: generating csrf token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
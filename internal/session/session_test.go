@@ -0,0 +1,28 @@
+package session
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+)
+
+func TestNewCookies(t *testing.T) {
+	tests := []struct {
+		cfg      config.Config
+		secure   bool
+		sameSite http.SameSite
+	}{
+		{config.Config{}, false, http.SameSiteLaxMode},
+		{config.Config{Environment: "production"}, true, http.SameSiteLaxMode},
+		{config.Config{Environment: "production", CookieSameSite: "strict"}, true, http.SameSiteStrictMode},
+		{config.Config{CookieSameSite: "None"}, true, http.SameSiteNoneMode},
+	}
+
+	for _, tt := range tests {
+		c := NewCookies(tt.cfg)
+		if c.Secure != tt.secure || c.SameSite != tt.sameSite {
+			t.Errorf("%+v: Secure=%v SameSite=%v; want %v, %v", tt.cfg, c.Secure, c.SameSite, tt.secure, tt.sameSite)
+		}
+	}
+}
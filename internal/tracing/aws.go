@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"context"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AWSMiddleware adds a client span around every AWS SDK operation. Register
+// it through the APIOptions of a service client, for example
+// s3.Options.APIOptions.
+func AWSMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("OTelSpan", awsSpan), middleware.Before)
+}
+
+func awsSpan(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	service, operation := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+	ctx, span := Start(ctx, service+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("aws-api"),
+			semconv.RPCService(service),
+			semconv.RPCMethod(operation),
+		),
+	)
+	defer span.End()
+
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	if requestID, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+		span.SetAttributes(semconv.AWSRequestID(requestID))
+	}
+	RecordError(span, err)
+	return out, metadata, err
+}
@@ -0,0 +1,103 @@
+// Package tracing sets up OpenTelemetry tracing: W3C trace-context
+// propagation, an OTLP exporter that can be pointed at a collector or left
+// off, and the helpers the HTTP, service, SQL and S3 layers use to start
+// their spans.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+)
+
+const (
+	instrumentationName = "github.com/sagar-rathod-devops/do-host-network-backend"
+	defaultServiceName  = "do-host-network-backend"
+)
+
+// Options configures Setup.
+type Options struct {
+	// ServiceName is reported as service.name.
+	ServiceName string
+	// Endpoint is the OTLP/HTTP URL of the collector, for example
+	// http://localhost:4318. Spans are not exported when it is empty.
+	Endpoint string
+	// SampleRatio is the fraction of new traces that are recorded; zero
+	// records all of them. Requests that arrive with a traceparent follow
+	// the caller's decision.
+	SampleRatio float64
+}
+
+// Setup installs the W3C trace-context propagator and, when an endpoint is
+// configured, a tracer provider exporting over OTLP. The returned function
+// flushes pending spans and must be called before the process exits.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP exporter: %w", err)
+	}
+
+	name := opts.ServiceName
+	if name == "" {
+		name = defaultServiceName
+	}
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(name)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("describing tracing resource: %w", err)
+	}
+
+	ratio := opts.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the span carried by ctx. It
+// uses the global tracer provider, so it costs next to nothing while tracing
+// is off.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// RecordError marks span as failed with err, which is redacted first since
+// error messages can carry email addresses. A nil err is ignored.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	msg := logging.Redact(err.Error())
+	span.AddEvent("exception", trace.WithAttributes(
+		semconv.ExceptionType(fmt.Sprintf("%T", err)),
+		semconv.ExceptionMessage(msg),
+	))
+	span.SetStatus(codes.Error, msg)
+}
@@ -1,7 +1,15 @@
 package main
 
-import "github.com/sagar-rathod-devops/do-host-network-backend/routes"
+import (
+	"log/slog"
+	"os"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/routes"
+)
 
 func main() {
-	routes.RunServer()
+	if err := routes.RunServer(); err != nil {
+		slog.Error("server stopped", "err", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+)
+
+// AccessLog makes logger the logger of the request context and writes one
+// line per request with its status and latency once the response is
+// complete. It must run after RequestID, so lines carry the request ID, and
+// before ErrorHandler, so they see the status of error responses. The query
+// string is left out as it may carry secrets.
+func AccessLog(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Request = ctx.Request.WithContext(logging.NewContext(ctx.Request.Context(), logger))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("route", deprecation.Route(ctx)),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", max(ctx.Writer.Size(), 0)),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+		if user, ok := ctx.Get("user"); ok {
+			if u, ok := user.(models.User); ok {
+				attrs = append(attrs, slog.String("user_id", u.ID))
+			}
+		}
+		logger.LogAttrs(ctx.Request.Context(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+)
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+
+	router := gin.New()
+	router.Use(RequestID(), AccessLog(logging.New(&buf, logging.Options{Format: "json"})), ErrorHandler())
+	router.GET("/posts/:post_id", func(ctx *gin.Context) {
+		ctx.Error(apperrors.NotFound("post_not_found", "Post not found"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/42?token=s3cret", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("decoding %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"msg":        "request",
+		"level":      "WARN",
+		"route":      "/posts/:post_id",
+		"path":       "/posts/42",
+		"status":     float64(http.StatusNotFound),
+		"request_id": rec.Header().Get("X-Request-ID"),
+	}
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("%s = %v; want %v", key, line[key], value)
+		}
+	}
+	if bytes.Contains(buf.Bytes(), []byte("s3cret")) {
+		t.Errorf("query string logged: %s", buf.String())
+	}
+}
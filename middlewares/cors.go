@@ -0,0 +1,112 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/requestid"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/session"
+)
+
+// CORSOptions configures which browser origins may call the API.
+type CORSOptions struct {
+	// AllowedOrigins lists origins such as https://app.example.com. "*"
+	// allows any origin without credentials.
+	AllowedOrigins []string
+
+	// AllowCredentials lets browsers send cookies on cross-origin requests.
+	// It cannot be combined with "*": any site could then act as the user.
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache a preflight response.
+	MaxAge time.Duration
+}
+
+// Validate returns an error when opts allow credentials from any origin.
+func (opts CORSOptions) Validate() error {
+	if opts.AllowCredentials && slices.Contains(opts.AllowedOrigins, "*") {
+		return errors.New(`cors: "*" cannot be an allowed origin when credentials are allowed`)
+	}
+	return nil
+}
+
+var (
+	corsMethods = strings.Join([]string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}, ", ")
+	corsHeaders = strings.Join([]string{
+		"Authorization", "Content-Type", session.CSRFHeader, requestid.Header, idempotency.Header,
+		"traceparent", "tracestate",
+	}, ", ")
+	corsExposed = strings.Join([]string{
+		requestid.Header, idempotency.ReplayedHeader, "Deprecation", "Sunset", "Link",
+		"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After",
+	}, ", ")
+)
+
+// CORS answers preflight requests and adds the CORS headers to responses
+// for allowed origins. Requests from other origins are served without them,
+// so browsers refuse to expose the response; their preflights are rejected.
+// It panics when opts allow credentials from any origin; see
+// CORSOptions.Validate.
+func CORS(opts CORSOptions) gin.HandlerFunc {
+	if err := opts.Validate(); err != nil {
+		panic(err)
+	}
+	allowed := make(map[string]bool, len(opts.AllowedOrigins))
+	for _, origin := range opts.AllowedOrigins {
+		allowed[strings.ToLower(strings.TrimRight(origin, "/"))] = true
+	}
+	maxAge := strconv.Itoa(int(opts.MaxAge / time.Second))
+
+	return func(ctx *gin.Context) {
+		origin := ctx.GetHeader("Origin")
+		if origin == "" {
+			ctx.Next()
+			return
+		}
+		ctx.Writer.Header().Add("Vary", "Origin")
+
+		preflight := ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != ""
+		if !allowed["*"] && !allowed[strings.ToLower(origin)] {
+			if preflight {
+				ctx.Error(apperrors.Forbidden("origin_not_allowed", "Origin not allowed"))
+				ctx.Abort()
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		if allowed["*"] {
+			ctx.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			ctx.Header("Access-Control-Allow-Origin", origin)
+		}
+		if opts.AllowCredentials {
+			ctx.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			ctx.Header("Access-Control-Expose-Headers", corsExposed)
+			ctx.Next()
+			return
+		}
+
+		ctx.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		ctx.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		ctx.Header("Access-Control-Allow-Methods", corsMethods)
+		ctx.Header("Access-Control-Allow-Headers", corsHeaders)
+		if opts.MaxAge > 0 {
+			ctx.Header("Access-Control-Max-Age", maxAge)
+		}
+		ctx.AbortWithStatus(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,115 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func corsRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(), CORS(CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com", "http://localhost:3000/"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}))
+	router.GET("/posts", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	return router
+}
+
+func TestCORSPreflight(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/posts", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	corsRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d; want 204", rec.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "http://localhost:3000",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           "600",
+	}
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("%s = %q; want %q", header, got, value)
+		}
+	}
+	if rec.Header().Get("Access-Control-Allow-Headers") == "" {
+		t.Error("no Access-Control-Allow-Headers")
+	}
+}
+
+func TestCORSRejectsUnknownOrigins(t *testing.T) {
+	router := corsRouter()
+
+	req := httptest.NewRequest(http.MethodOptions, "/posts", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("preflight status = %d; want 403", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/posts", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("simple request: status %d, allow origin %q; want 200 without CORS headers",
+			rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestCORSSimpleRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	corsRouter().ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if rec.Header().Get("Access-Control-Expose-Headers") == "" {
+		t.Error("no Access-Control-Expose-Headers")
+	}
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(CORSOptions{AllowedOrigins: []string{"*"}}))
+	router.GET("/posts", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	req.Header.Set("Origin", "https://anywhere.example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q; want *", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q; want none", got)
+	}
+}
+
+func TestCORSRejectsAnyOriginWithCredentials(t *testing.T) {
+	opts := CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+	if opts.Validate() == nil {
+		t.Fatal("Validate accepted any origin with credentials")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("CORS accepted any origin with credentials")
+		}
+	}()
+	CORS(opts)
+}
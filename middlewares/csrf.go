@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/session"
+)
+
+// CSRF enforces double-submit CSRF protection on requests DeserializeUser
+// authenticated with the session cookie: unsafe methods must repeat the CSRF
+// cookie in the X-CSRF-Token header, which a cross-site form or script
+// cannot do. Bearer-token requests are exempt since browsers never attach
+// the header on their own. It must run after DeserializeUser.
+//
+// Sessions without a CSRF cookie get one on their next safe request.
+func CSRF(cookies session.Cookies) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString(AuthSourceKey) != AuthSourceCookie {
+			ctx.Next()
+			return
+		}
+
+		expected, _ := ctx.Cookie(session.CSRFCookie)
+		if safeMethod(ctx.Request.Method) {
+			if expected == "" {
+				if err := cookies.IssueCSRF(ctx); err != nil {
+					ctx.Error(err)
+					ctx.Abort()
+					return
+				}
+			}
+			ctx.Next()
+			return
+		}
+
+		got := ctx.GetHeader(session.CSRFHeader)
+		if expected == "" || subtle.ConstantTimeCompare([]byte(got), []byte(expected)) != 1 {
+			ctx.Error(apperrors.Forbidden("csrf_token_invalid", "Missing or invalid CSRF token"))
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
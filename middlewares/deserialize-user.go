@@ -1,20 +1,29 @@
 package middlewares
 
 import (
-	"database/sql"
-	"net/http"
+	"errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sagar-rathod-devops/do-host-network-backend/config"
-	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/session"
 	"github.com/sagar-rathod-devops/do-host-network-backend/utils"
 )
 
+// AuthSourceKey is the context key under which DeserializeUser records how
+// the request was authenticated: AuthSourceBearer or AuthSourceCookie.
+const (
+	AuthSourceKey    = "auth_source"
+	AuthSourceBearer = "bearer"
+	AuthSourceCookie = "cookie"
+)
+
 // DeserializeUser is a middleware to validate and fetch the user from the database based on the provided access token
-func DeserializeUser(db *sql.DB) gin.HandlerFunc {
+func DeserializeUser(users repositories.UserRepository, tokenSecret string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var token string
+		source := AuthSourceBearer
 
 		// 1. Try Authorization header
 		authHeader := ctx.GetHeader("Authorization")
@@ -24,43 +33,42 @@ func DeserializeUser(db *sql.DB) gin.HandlerFunc {
 
 		// 2. Fallback to cookie
 		if token == "" {
-			cookie, err := ctx.Cookie("token")
+			cookie, err := ctx.Cookie(session.TokenCookie)
 			if err == nil {
 				token = cookie
+				source = AuthSourceCookie
 			}
 		}
 
 		if token == "" {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "fail", "message": "You are not logged in"})
+			ctx.Error(apperrors.Unauthorized("not_logged_in", "You are not logged in"))
+			ctx.Abort()
 			return
 		}
 
 		// Validate token
-		config, _ := config.LoadConfig(".")
-		sub, err := utils.ValidateToken(token, config.TokenSecret)
+		sub, err := utils.ValidateToken(token, tokenSecret)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "fail", "message": err.Error()})
+			ctx.Error(apperrors.Unauthorized("invalid_token", "Invalid or expired token"))
+			ctx.Abort()
 			return
 		}
 
 		// Fetch user
-		var user models.User
-		query := `SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE id = $1`
-		row := db.QueryRow(query, sub)
-
-		err = row.Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+		userID, _ := sub.(string)
+		user, err := users.GetUserByID(ctx.Request.Context(), userID)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-			} else {
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user data"})
+			if errors.Is(err, apperrors.ErrNotFound) || errors.Is(err, apperrors.ErrValidation) {
+				err = apperrors.Unauthorized("user_not_found", "User not found")
 			}
+			ctx.Error(err)
 			ctx.Abort()
 			return
 		}
 
 		// Attach user to context using "user" key
-		ctx.Set("user", user)
+		ctx.Set("user", *user)
+		ctx.Set(AuthSourceKey, source)
 		ctx.Next()
 	}
 }
@@ -0,0 +1,104 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/requestid"
+)
+
+// ErrorBody is the JSON envelope of every error response:
+//
+//	{"error": {"code": "not_found", "message": "Post not found", "request_id": "..."}}
+type ErrorBody struct {
+	Error APIError `json:"error"`
+}
+
+// APIError describes a failed request. Code is stable and meant for
+// programs; Message is meant for people.
+type APIError struct {
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Details   []apperrors.FieldError `json:"details,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+var statusByKind = map[error]int{
+	apperrors.ErrNotFound:      http.StatusNotFound,
+	apperrors.ErrConflict:      http.StatusConflict,
+	apperrors.ErrForbidden:     http.StatusForbidden,
+	apperrors.ErrUnauthorized:  http.StatusUnauthorized,
+	apperrors.ErrValidation:    http.StatusBadRequest,
+	apperrors.ErrRateLimited:   http.StatusTooManyRequests,
+	apperrors.ErrUnprocessable: http.StatusUnprocessableEntity,
+	apperrors.ErrTooLarge:      http.StatusRequestEntityTooLarge,
+}
+
+// ErrorHandler renders the last error a handler attached with ctx.Error as
+// an ErrorBody. Errors that are not apperrors are reported as internal errors
+// without their text, which is logged instead.
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		err := ctx.Errors.Last().Err
+		status, body := errorResponse(err)
+		body.Error.RequestID = requestid.FromContext(ctx.Request.Context())
+		if status >= http.StatusInternalServerError {
+			reqCtx := ctx.Request.Context()
+			logging.FromContext(reqCtx).ErrorContext(reqCtx, "request failed",
+				"method", ctx.Request.Method, "path", ctx.Request.URL.Path, "err", err)
+		}
+
+		ctx.AbortWithStatusJSON(status, body)
+	}
+}
+
+// Recovery turns a panic into an internal error rendered by ErrorHandler.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(ctx *gin.Context, recovered any) {
+		ctx.Error(fmt.Errorf("panic: %v", recovered))
+		ctx.Abort()
+	})
+}
+
+// NoRoute reports unknown paths in the error envelope.
+func NoRoute(ctx *gin.Context) {
+	ctx.Error(apperrors.NotFound("route_not_found", "Route not found"))
+}
+
+func errorResponse(err error) (int, ErrorBody) {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		status, ok := statusByKind[appErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		return status, ErrorBody{Error: APIError{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+			Details: appErr.Fields,
+		}}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, ErrorBody{Error: APIError{
+			Code:    "timeout",
+			Message: "The request took too long",
+		}}
+	}
+
+	return http.StatusInternalServerError, ErrorBody{Error: APIError{
+		Code:    "internal_error",
+		Message: "Internal server error",
+	}}
+}
@@ -0,0 +1,101 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/requestid"
+)
+
+func serveError(t *testing.T, err error, header string) (*httptest.ResponseRecorder, ErrorBody) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID(), ErrorHandler(), Recovery())
+	router.GET("/", func(ctx *gin.Context) {
+		if err == nil {
+			panic("boom")
+		}
+		ctx.Error(err)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set(requestid.Header, header)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var body ErrorBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	return rec, body
+}
+
+func TestErrorHandlerMapsKinds(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{apperrors.NotFound("post_not_found", "Post not found"), http.StatusNotFound, "post_not_found"},
+		{apperrors.Wrap(apperrors.ErrConflict, errors.New("duplicate")), http.StatusConflict, "conflict"},
+		{apperrors.Unauthorized("invalid_token", "Invalid token"), http.StatusUnauthorized, "invalid_token"},
+		{apperrors.InvalidField("user_id", "must be a valid UUID"), http.StatusBadRequest, "validation_failed"},
+		{errors.New("connection refused"), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		rec, body := serveError(t, tt.err, "")
+		if rec.Code != tt.status || body.Error.Code != tt.code {
+			t.Errorf("%v: got %d %q; want %d %q", tt.err, rec.Code, body.Error.Code, tt.status, tt.code)
+		}
+		if body.Error.RequestID == "" || body.Error.RequestID != rec.Header().Get(requestid.Header) {
+			t.Errorf("%v: request_id %q does not match header %q", tt.err, body.Error.RequestID, rec.Header().Get(requestid.Header))
+		}
+	}
+}
+
+func TestErrorHandlerHidesInternalCause(t *testing.T) {
+	rec, body := serveError(t, errors.New("pq: password authentication failed"), "")
+	if strings.Contains(rec.Body.String(), "password") {
+		t.Errorf("internal error leaked: %s", rec.Body)
+	}
+	if body.Error.Message != "Internal server error" {
+		t.Errorf("message = %q", body.Error.Message)
+	}
+}
+
+func TestErrorHandlerReportsFieldDetails(t *testing.T) {
+	_, body := serveError(t, apperrors.InvalidField("email", "is required"), "")
+	if len(body.Error.Details) != 1 || body.Error.Details[0].Field != "email" {
+		t.Errorf("details = %+v; want one entry for email", body.Error.Details)
+	}
+}
+
+func TestRecoveryRendersEnvelope(t *testing.T) {
+	rec, body := serveError(t, nil, "")
+	if rec.Code != http.StatusInternalServerError || body.Error.Code != "internal_error" {
+		t.Errorf("got %d %q; want 500 internal_error", rec.Code, body.Error.Code)
+	}
+}
+
+func TestRequestIDReusesValidHeader(t *testing.T) {
+	rec, body := serveError(t, apperrors.NotFound("x", "x"), "abc-123")
+	if got := rec.Header().Get(requestid.Header); got != "abc-123" || body.Error.RequestID != "abc-123" {
+		t.Errorf("request id = %q / %q; want abc-123", got, body.Error.RequestID)
+	}
+
+	rec, _ = serveError(t, apperrors.NotFound("x", "x"), "bad\x01id")
+	if got := rec.Header().Get(requestid.Header); got == "" || got == "bad\x01id" {
+		t.Errorf("request id = %q; want a generated one", got)
+	}
+}
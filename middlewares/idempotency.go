@@ -0,0 +1,133 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+)
+
+var (
+	errIdempotencyKeyTooLong   = apperrors.InvalidField(idempotency.Header, "must be at most 255 characters")
+	errIdempotencyKeyReused    = apperrors.Unprocessable("idempotency_key_reused", "This Idempotency-Key was already used for a different request")
+	errIdempotencyBodyTooLarge = apperrors.TooLarge("idempotency_body_too_large", fmt.Sprintf("Requests with an Idempotency-Key must have a body of at most %d MB", idempotency.MaxBodySize>>20))
+	errIdempotencyKeyInUse     = apperrors.Conflict("idempotency_key_in_use", "A request with this Idempotency-Key is still being processed")
+)
+
+// Idempotency makes unsafe requests carrying an Idempotency-Key header safe
+// to retry. The first successful response under a key is stored and
+// replayed, marked with Idempotent-Replayed, to retries with the same
+// method, path and body, which may be at most idempotency.MaxBodySize.
+// Reusing the key for a different request gets 422, retrying while the first
+// attempt still runs gets 409. Failed responses are not stored, so the
+// request can be retried as is or corrected. Keys are scoped to the user, so
+// it must run after DeserializeUser. Like RateLimit it lets requests through
+// when the store fails.
+func Idempotency(store idempotency.Store, policy idempotency.Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotency.Header)
+		if key == "" || !unsafeMethod(ctx.Request.Method) {
+			ctx.Next()
+			return
+		}
+		if len(key) > idempotency.MaxKeyLength {
+			ctx.Error(errIdempotencyKeyTooLong)
+			ctx.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, idempotency.MaxBodySize))
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			ctx.Error(errIdempotencyBodyTooLarge)
+			ctx.Abort()
+			return
+		case err != nil:
+			ctx.Error(apperrors.Validation("Could not read the request body"))
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := idempotency.Fingerprint(ctx.Request.Method, deprecation.Path(ctx), ctx.ContentType(), body)
+		key = clientSubject(ctx) + ":" + key
+
+		// The stored state must follow the outcome even if the client has
+		// gone away in the meantime.
+		reqCtx := ctx.Request.Context()
+		storeCtx := context.WithoutCancel(reqCtx)
+		logger := logging.FromContext(reqCtx)
+
+		lock, record, err := store.Reserve(reqCtx, key, fingerprint, policy)
+		switch {
+		case err != nil:
+			logger.WarnContext(reqCtx, "idempotency store failed", "err", err)
+			ctx.Next()
+			return
+		case record == nil:
+		case record.Fingerprint != fingerprint:
+			ctx.Error(errIdempotencyKeyReused)
+			ctx.Abort()
+			return
+		case record.Response == nil:
+			ctx.Error(errIdempotencyKeyInUse)
+			ctx.Abort()
+			return
+		default:
+			ctx.Header(idempotency.ReplayedHeader, "true")
+			ctx.Data(record.Response.Status, record.Response.ContentType, record.Response.Body)
+			ctx.Abort()
+			return
+		}
+
+		w := &recordingWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = w
+		ctx.Next()
+
+		if status := w.Status(); len(ctx.Errors) == 0 && status < http.StatusBadRequest {
+			err = store.Complete(storeCtx, lock, idempotency.Response{
+				Status:      status,
+				ContentType: w.Header().Get("Content-Type"),
+				Body:        w.body.Bytes(),
+			})
+		} else {
+			err = store.Release(storeCtx, lock)
+		}
+		if err != nil {
+			logger.WarnContext(reqCtx, "idempotency store failed", "err", err)
+		}
+	}
+}
+
+func unsafeMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// recordingWriter keeps a copy of the body written through it.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+)
+
+func TestIdempotencyDoesNotStoreFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+
+	router := gin.New()
+	router.Use(ErrorHandler(), Idempotency(idempotency.NewMemoryStore(), idempotency.Policy{TTL: time.Hour, LockTimeout: time.Minute}))
+	router.POST("/things", func(ctx *gin.Context) {
+		calls++
+		if calls == 1 {
+			ctx.Error(apperrors.Conflict("busy", "Try again"))
+			return
+		}
+		ctx.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(`{}`))
+		req.Header.Set(idempotency.Header, "k")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(); rec.Code != http.StatusConflict {
+		t.Fatalf("first status = %d; want 409", rec.Code)
+	}
+	if rec := send(); rec.Code != http.StatusCreated || rec.Header().Get(idempotency.ReplayedHeader) != "" {
+		t.Fatalf("retry after a failure = %d; want it to run again", rec.Code)
+	}
+	if rec := send(); rec.Code != http.StatusCreated || rec.Body.String() != `{"call":2}` || calls != 2 {
+		t.Fatalf("second retry = %d %s after %d calls; want the stored response", rec.Code, rec.Body, calls)
+	}
+}
+
+func TestIdempotencyKeysAreBoundToThePath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(), Idempotency(idempotency.NewMemoryStore(), idempotency.Policy{TTL: time.Hour, LockTimeout: time.Minute}))
+	router.DELETE("/things/:id", func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) })
+	router.POST("/things", func(ctx *gin.Context) { ctx.Status(http.StatusCreated) })
+
+	send := func(method, path string, body io.Reader) int {
+		req := httptest.NewRequest(method, path, body)
+		req.Header.Set(idempotency.Header, "k")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := send(http.MethodDelete, "/things/1", nil); code != http.StatusNoContent {
+		t.Fatalf("first delete = %d; want 204", code)
+	}
+	if code := send(http.MethodDelete, "/things/2", nil); code != http.StatusUnprocessableEntity {
+		t.Errorf("delete of another thing with the same key = %d; want 422", code)
+	}
+
+	body := strings.NewReader(strings.Repeat("x", idempotency.MaxBodySize+1))
+	if code := send(http.MethodPost, "/things", body); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized body = %d; want 413", code)
+	}
+}
@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
+)
+
+// Metrics records the latency and final status of every request by route
+// template, so /posts/:post_id is one series however many posts there are.
+// Unknown routes and methods are folded into "unmatched" and "OTHER" to keep
+// clients from creating series at will. Like AccessLog it must run before
+// ErrorHandler.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := deprecation.Route(ctx)
+		if route == "" {
+			route = "unmatched"
+		}
+		m.ObserveHTTP(metricMethod(ctx.Request.Method), route, ctx.Writer.Status(), time.Since(start))
+	}
+}
+
+func metricMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "OTHER"
+}
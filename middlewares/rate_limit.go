@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ratelimit"
+)
+
+// RateLimit applies the first of rules matching the route of the request.
+// Buckets are keyed by the authenticated user, so it must run after
+// DeserializeUser to limit users, and by client IP otherwise. Every limited
+// response carries the X-RateLimit-* headers; rejected ones also carry
+// Retry-After. When the store fails the request is let through, as an
+// outage of the limiter should not take the API down with it.
+func RateLimit(store ratelimit.Store, rules ...ratelimit.Rule) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rule, ok := ratelimit.Match(rules, ctx.Request.Method, deprecation.Route(ctx))
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		res, err := store.Take(ctx.Request.Context(), rule.Name+":"+clientSubject(ctx), rule.Limit)
+		if err != nil {
+			reqCtx := ctx.Request.Context()
+			logging.FromContext(reqCtx).WarnContext(reqCtx, "rate limit store failed", "rule", rule.Name, "err", err)
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		ctx.Header("X-RateLimit-Reset", ceilSeconds(res.ResetAfter))
+		if !res.Allowed {
+			ctx.Header("Retry-After", ceilSeconds(res.RetryAfter))
+			ctx.Error(apperrors.RateLimited())
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// clientSubject identifies who sent a request: the authenticated user, or
+// the client IP for anonymous requests. The IP only comes from
+// X-Forwarded-For behind the trusted proxies of the router.
+func clientSubject(ctx *gin.Context) string {
+	if user, ok := ctx.Get("user"); ok {
+		if u, ok := user.(models.User); ok {
+			return "user:" + u.ID
+		}
+	}
+	return "ip:" + ctx.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
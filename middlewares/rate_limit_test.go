@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/models"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ratelimit"
+)
+
+func TestRateLimitRejectsWithHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(), RateLimit(ratelimit.NewMemoryStore(),
+		ratelimit.Rule{Name: "uploads", Routes: []string{"/uploads"}, Limit: ratelimit.PerMinute(1)},
+		ratelimit.Rule{Name: "default", Limit: ratelimit.PerMinute(100)},
+	))
+	router.POST("/uploads", func(ctx *gin.Context) { ctx.Status(http.StatusCreated) })
+	router.GET("/posts", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	if rec := serve(http.MethodPost, "/uploads"); rec.Code != http.StatusCreated || rec.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("first upload: %d, remaining %q", rec.Code, rec.Header().Get("X-RateLimit-Remaining"))
+	}
+	rec := serve(http.MethodPost, "/uploads")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" {
+		t.Fatalf("second upload: %d, Retry-After %q; want 429 after 60s", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := serve(http.MethodGet, "/posts"); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "100" {
+		t.Fatalf("other rule: %d, limit %q", rec.Code, rec.Header().Get("X-RateLimit-Limit"))
+	}
+}
+
+func TestRateLimitKeysByUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(), func(ctx *gin.Context) {
+		ctx.Set("user", models.User{ID: ctx.GetHeader("X-User")})
+	}, RateLimit(ratelimit.NewMemoryStore(), ratelimit.Rule{Name: "user", Limit: ratelimit.PerMinute(1)}))
+	router.GET("/", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	for _, tt := range []struct {
+		user   string
+		status int
+	}{{"alice", http.StatusOK}, {"bob", http.StatusOK}, {"alice", http.StatusTooManyRequests}} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", tt.user)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status %d; want %d", tt.user, rec.Code, tt.status)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/requestid"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID assigns every request an ID, reusing a well-formed X-Request-ID
+// sent by the client or a proxy. The ID is echoed in the response header and
+// stored in the request context.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(requestid.Header)
+		if !validRequestID(id) {
+			id = requestid.New()
+		}
+
+		ctx.Header(requestid.Header, id)
+		ctx.Request = ctx.Request.WithContext(requestid.NewContext(ctx.Request.Context(), id))
+		ctx.Next()
+	}
+}
+
+// validRequestID accepts short IDs made of printable ASCII so a client cannot
+// inject control characters into logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeout bounds the context of every request by d. Services and
+// repositories use the request context, so queries still running when the
+// deadline passes or the client disconnects are cancelled.
+func RequestTimeout(d time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if d <= 0 {
+			ctx.Next()
+			return
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), d)
+		defer cancel()
+
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+	}
+}
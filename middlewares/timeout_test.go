@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestTimeoutSetsDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var remaining time.Duration
+	router := gin.New()
+	router.Use(RequestTimeout(time.Second))
+	router.GET("/", func(ctx *gin.Context) {
+		deadline, ok := ctx.Request.Context().Deadline()
+		if !ok {
+			t.Error("request context has no deadline")
+		}
+		remaining = time.Until(deadline)
+		ctx.Status(http.StatusNoContent)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if remaining <= 0 || remaining > time.Second {
+		t.Errorf("remaining time = %v; want within (0, 1s]", remaining)
+	}
+}
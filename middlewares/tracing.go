@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+)
+
+// Tracing starts a server span for every request, continuing the trace of
+// the caller when it sends a W3C traceparent header. The span is renamed to
+// the route template once the handler has run. It must run before
+// AccessLog so log records carry the trace ID.
+func Tracing() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := ctx.Request
+		parent := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+		spanCtx, span := tracing.Start(parent, req.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(metricMethod(req.Method)),
+				semconv.URLPath(req.URL.Path),
+				semconv.ClientAddress(ctx.ClientIP()),
+				semconv.UserAgentOriginal(req.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		ctx.Request = req.WithContext(spanCtx)
+		ctx.Next()
+
+		if route := deprecation.Route(ctx); route != "" {
+			span.SetName(req.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		status := ctx.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
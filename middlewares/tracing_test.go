@@ -0,0 +1,90 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestTracingContinuesCallerTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := recordSpans(t)
+	var buf bytes.Buffer
+
+	router := gin.New()
+	router.Use(Tracing(), AccessLog(logging.New(&buf, logging.Options{Format: "json"})))
+	router.GET("/posts/:post_id", func(ctx *gin.Context) {
+		_, span := tracing.Start(ctx.Request.Context(), "PostService.GetPost")
+		span.End()
+		ctx.Status(http.StatusInternalServerError)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/posts/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans; want 2", len(spans))
+	}
+	child, server := spans[0], spans[1]
+	if server.Name() != "GET /posts/:post_id" {
+		t.Errorf("server span name = %q", server.Name())
+	}
+	if got := server.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("trace ID = %s; want %s", got, traceID)
+	}
+	if got := server.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID = %s", got)
+	}
+	if child.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Errorf("service span is not a child of the server span")
+	}
+	if server.Status().Code != codes.Error {
+		t.Errorf("status = %v; want error", server.Status().Code)
+	}
+	var sawStatus bool
+	for _, attr := range server.Attributes() {
+		if attr == semconv.HTTPResponseStatusCode(http.StatusInternalServerError) {
+			sawStatus = true
+		}
+	}
+	if !sawStatus {
+		t.Errorf("status code attribute missing: %v", server.Attributes())
+	}
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("decoding %q: %v", buf.String(), err)
+	}
+	if line["trace_id"] != traceID {
+		t.Errorf("access log trace_id = %v; want %s", line["trace_id"], traceID)
+	}
+}
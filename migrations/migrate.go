@@ -3,42 +3,47 @@ package migrations
 import (
 	"database/sql"
 	"fmt"
-	"io/ioutil"
-	"log"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/queries"
 )
 
 // Migrate runs the database migrations
 func Migrate(db *sql.DB) error {
-	queries := []struct {
+	files := []struct {
 		fileName string
 		fn       func(*sql.DB, string) error
 	}{
-		{"queries/create_all_tables.sql", runSQLFile},
-		// {"queries/create_users_table.sql", runSQLFile},
-		// {"queries/create_otps_table.sql", runSQLFile},
+		{"create_all_tables.sql", runSQLFile},
+		{"create_rate_limit_buckets_table.sql", runSQLFile},
+		{"create_idempotency_keys_table.sql", runSQLFile},
+		{"create_home_timeline_table.sql", runSQLFile},
+		{"create_post_revisions_table.sql", runSQLFile},
+		{"create_post_attachments_table.sql", runSQLFile},
+		{"create_hashtags_tables.sql", runSQLFile},
+		{"create_post_shares.sql", runSQLFile},
+		{"create_post_visibility.sql", runSQLFile},
+		{"create_post_drafts_table.sql", runSQLFile},
+		{"create_post_polls_table.sql", runSQLFile},
+		// {"create_users_table.sql", runSQLFile},
+		// {"create_otps_table.sql", runSQLFile},
 	}
 
-	for _, q := range queries {
+	for _, q := range files {
 		if err := q.fn(db, q.fileName); err != nil {
 			return err
 		}
 	}
-
-	fmt.Println("👍 Migration complete")
 	return nil
 }
 
 func runSQLFile(db *sql.DB, fileName string) error {
-	query, err := ioutil.ReadFile(fileName)
+	query, err := queries.Files.ReadFile(fileName)
 	if err != nil {
-		log.Printf("Error reading SQL file %s: %v", fileName, err)
-		return err
+		return fmt.Errorf("reading %s: %w", fileName, err)
 	}
 
-	_, err = db.Exec(string(query))
-	if err != nil {
-		log.Printf("Error executing SQL file %s: %v", fileName, err)
-		return err
+	if _, err := db.Exec(string(query)); err != nil {
+		return fmt.Errorf("executing %s: %w", fileName, err)
 	}
 	return nil
 }
@@ -0,0 +1,32 @@
+-- #hashtags and @mentions of posts and comments. The entities columns hold
+-- them as parsed when the text was written; hashtags are also indexed so
+-- their posts and trends can be queried.
+ALTER TABLE content_post ADD COLUMN IF NOT EXISTS entities JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE post_comments ADD COLUMN IF NOT EXISTS entities JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE IF NOT EXISTS hashtags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tag VARCHAR(100) UNIQUE NOT NULL,   -- Lower-cased, without the #
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS post_hashtags (
+    post_id UUID NOT NULL,
+    hashtag_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, hashtag_id),
+    FOREIGN KEY (post_id) REFERENCES content_post(id) ON DELETE CASCADE,
+    FOREIGN KEY (hashtag_id) REFERENCES hashtags(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS comment_hashtags (
+    comment_id UUID NOT NULL,
+    hashtag_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, hashtag_id),
+    FOREIGN KEY (comment_id) REFERENCES post_comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (hashtag_id) REFERENCES hashtags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_hashtags_hashtag ON post_hashtags (hashtag_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_comment_hashtags_hashtag ON comment_hashtags (hashtag_id, created_at);
@@ -0,0 +1,14 @@
+-- Home timelines written when a post is published. Only authors with few
+-- followers are fanned out; the posts of the others are read on request.
+CREATE TABLE IF NOT EXISTS home_timeline (
+    user_id UUID NOT NULL,      -- Owner of the timeline
+    post_id UUID NOT NULL,
+    author_id UUID NOT NULL,    -- Author of the post, to drop their posts on unfollow
+    PRIMARY KEY (user_id, post_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES content_post(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_followers_followed_id ON followers (followed_id);
+CREATE INDEX IF NOT EXISTS idx_content_post_user_created ON content_post (user_id, created_at DESC, id DESC);
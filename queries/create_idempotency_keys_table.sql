@@ -0,0 +1,12 @@
+-- Idempotency keys of unsafe requests and the responses replayed for retries.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key TEXT PRIMARY KEY,                   -- Client key scoped by subject, e.g. "user:<uuid>:<key>"
+    fingerprint TEXT NOT NULL,              -- Hash of the method, route and body of the first request
+    status INTEGER,                         -- Stored response; NULL while the request runs
+    content_type TEXT,
+    body BYTEA,
+    locked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
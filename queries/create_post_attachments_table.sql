@@ -0,0 +1,16 @@
+-- Ordered media attachments of content posts. content_post.media_url keeps
+-- the URL of the first attachment for clients that read a single file.
+CREATE TABLE IF NOT EXISTS post_attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL,
+    position INT NOT NULL,              -- Order of the attachment within the post, from 0
+    url TEXT NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INT,                          -- Pixels, for images
+    height INT,
+    duration_seconds DOUBLE PRECISION,  -- For videos
+    alt_text TEXT NOT NULL DEFAULT '',
+    UNIQUE (post_id, position),
+    FOREIGN KEY (post_id) REFERENCES content_post(id) ON DELETE CASCADE
+);
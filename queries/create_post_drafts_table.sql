@@ -0,0 +1,19 @@
+-- Posts written ahead of time. A row is a draft until it has a publish_at,
+-- then a scheduled post: the scheduler publishes it once publish_at has
+-- passed and deletes the row. An empty visibility publishes the post with
+-- the author's default visibility.
+CREATE TABLE IF NOT EXISTS post_drafts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    post_content TEXT NOT NULL,
+    visibility VARCHAR(20) NOT NULL DEFAULT ''
+        CHECK (visibility IN ('', 'public', 'followers', 'only_me')),
+    publish_at TIMESTAMPTZ,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_drafts_user_id ON post_drafts (user_id, created_at DESC);
+-- The scheduler scans the scheduled posts by publish time.
+CREATE INDEX IF NOT EXISTS idx_post_drafts_publish_at ON post_drafts (publish_at) WHERE publish_at IS NOT NULL;
@@ -0,0 +1,50 @@
+-- Poll posts. The text of a poll post is its question; the poll has 2 to 4
+-- options and takes votes until ends_at. A user votes once, for one option
+-- or, in a multiple choice poll, for several: post_poll_voters holds one row
+-- per voter and every vote belongs to one. ended_notified_at is set once the
+-- author has been told the poll ended.
+ALTER TABLE content_post ADD COLUMN IF NOT EXISTS post_type VARCHAR(20) NOT NULL DEFAULT 'text'
+    CHECK (post_type IN ('text', 'poll'));
+
+CREATE TABLE IF NOT EXISTS post_polls (
+    post_id UUID PRIMARY KEY,
+    multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    ends_at TIMESTAMPTZ NOT NULL,
+    ended_notified_at TIMESTAMPTZ,
+    FOREIGN KEY (post_id) REFERENCES content_post(id) ON DELETE CASCADE
+);
+
+-- The notifier scans the polls still to report by end time.
+CREATE INDEX IF NOT EXISTS idx_post_polls_ends_at ON post_polls (ends_at) WHERE ended_notified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS post_poll_options (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL,
+    position INT NOT NULL,              -- Order of the option within the poll, from 0
+    label VARCHAR(100) NOT NULL,
+    UNIQUE (post_id, position),
+    UNIQUE (post_id, id),
+    FOREIGN KEY (post_id) REFERENCES post_polls(post_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS post_poll_voters (
+    post_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id),
+    FOREIGN KEY (post_id) REFERENCES post_polls(post_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- The option of a vote must belong to the poll voted on.
+CREATE TABLE IF NOT EXISTS post_poll_votes (
+    post_id UUID NOT NULL,
+    option_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id, option_id),
+    FOREIGN KEY (post_id, option_id) REFERENCES post_poll_options(post_id, id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id, user_id) REFERENCES post_poll_voters(post_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_poll_votes_option_id ON post_poll_votes (option_id);
@@ -0,0 +1,14 @@
+-- Earlier versions of edited posts. content_post.edited_at marks a post as
+-- edited; each edit stores the text it replaced here.
+ALTER TABLE content_post ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL,
+    post_content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,      -- When this version was written
+    replaced_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES content_post(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_revisions_post_id ON post_revisions (post_id, replaced_at DESC);
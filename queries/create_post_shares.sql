@@ -0,0 +1,10 @@
+-- Reposts and quote posts. A share is a post of its own that references the
+-- post it shares: a repost has no text, a quote adds the sharer's. Reposts
+-- are deleted with the original; quotes outlive it without a reference.
+ALTER TABLE content_post ADD COLUMN IF NOT EXISTS shared_post_id UUID REFERENCES content_post(id) ON DELETE SET NULL;
+ALTER TABLE content_post ADD COLUMN IF NOT EXISTS share_type VARCHAR(10) NOT NULL DEFAULT ''
+    CHECK (share_type IN ('', 'repost', 'quote'));
+
+-- A user reposts a post at most once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_content_post_one_repost ON content_post (user_id, shared_post_id) WHERE share_type = 'repost';
+CREATE INDEX IF NOT EXISTS idx_content_post_shared_post_id ON content_post (shared_post_id);
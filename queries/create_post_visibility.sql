@@ -0,0 +1,7 @@
+-- Who can see a post: everyone, the author's followers or the author only.
+-- New posts take the visibility their author picked as the default on their
+-- profile unless they choose one.
+ALTER TABLE content_post ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) NOT NULL DEFAULT 'public'
+    CHECK (visibility IN ('public', 'followers', 'only_me'));
+ALTER TABLE user_profile ADD COLUMN IF NOT EXISTS default_visibility VARCHAR(20) NOT NULL DEFAULT 'public'
+    CHECK (default_visibility IN ('public', 'followers', 'only_me'));
@@ -0,0 +1,9 @@
+-- Token buckets of the rate limiter, shared by every replica.
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key TEXT PRIMARY KEY,                   -- Rule name and subject, e.g. "user:user:<uuid>"
+    tokens DOUBLE PRECISION NOT NULL,       -- Tokens left after the last request
+    allowed BOOLEAN NOT NULL,               -- Whether the last request got a token
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
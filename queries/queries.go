@@ -0,0 +1,8 @@
+// Package queries embeds the SQL migration files so migrations do not depend
+// on the working directory of the process or test.
+package queries
+
+import "embed"
+
+//go:embed *.sql
+var Files embed.FS
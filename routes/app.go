@@ -0,0 +1,343 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/controllers"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/pagination"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ranking"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ratelimit"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/services"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/session"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
+	"github.com/sagar-rathod-devops/do-host-network-backend/middlewares"
+	"github.com/sagar-rathod-devops/do-host-network-backend/migrations"
+	"github.com/sagar-rathod-devops/do-host-network-backend/utils"
+)
+
+const (
+	defaultServerPort     = "8000"
+	defaultRequestTimeout = 30 * time.Second
+	defaultCORSMaxAge     = 10 * time.Minute
+
+	serverReadHeaderTimeout = 10 * time.Second
+	serverReadTimeout       = 60 * time.Second
+	serverIdleTimeout       = 120 * time.Second
+
+	// serverWriteGrace is added to the request timeout so a handler that runs
+	// into it can still send its error response.
+	serverWriteGrace = 15 * time.Second
+
+	tracingShutdownTimeout = 5 * time.Second
+
+	// scheduledPostsEvery is how often due scheduled posts are published.
+	scheduledPostsEvery = 30 * time.Second
+	// endedPollsEvery is how often authors are told their polls ended.
+	endedPollsEvery = time.Minute
+)
+
+// Dependencies holds everything the application needs from the outside world.
+type Dependencies struct {
+	Config       config.Config
+	DB           *sql.DB
+	Repositories repositories.Repositories
+	Uploader     controllers.Uploader
+	Mailer       services.Mailer
+
+	// Logger receives the access log and error reports; the default logger
+	// when nil.
+	Logger *slog.Logger
+}
+
+// App is the application container: it owns the wired controllers and the
+// HTTP router built on top of them.
+type App struct {
+	Config config.Config
+	DB     *sql.DB
+	Router *gin.Engine
+
+	logger      *slog.Logger
+	metrics     *metrics.Metrics
+	rateLimits  ratelimit.Store
+	idempotency idempotency.Store
+	drafts      *services.DraftService
+	polls       *services.PollService
+}
+
+// NewApp wires services and controllers from the injected dependencies. It
+// performs no I/O itself, so tests can pass in-memory repositories and fakes.
+func NewApp(deps Dependencies) (*App, error) {
+	if deps.Repositories.Users == nil {
+		return nil, errors.New("app: repositories are required")
+	}
+	if deps.Uploader == nil {
+		return nil, errors.New("app: uploader is required")
+	}
+	if deps.Mailer == nil {
+		return nil, errors.New("app: mailer is required")
+	}
+
+	cfg := deps.Config
+	cors := middlewares.CORSOptions{
+		AllowedOrigins:   cfg.AllowedOrigins(),
+		AllowCredentials: true,
+		MaxAge:           corsMaxAge(cfg),
+	}
+	if err := cors.Validate(); err != nil {
+		return nil, fmt.Errorf("app: CLIENT_ORIGIN: %w", err)
+	}
+
+	repos := deps.Repositories
+	logger := deps.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	m := metrics.New()
+	if deps.DB != nil {
+		m.RegisterDBStats(deps.DB)
+	}
+	uploader := m.InstrumentUploader(deps.Uploader)
+
+	// Initialize services
+	authService := &services.AuthService{
+		Tx:              repos.Tx,
+		UserRepository:  repos.Users,
+		OTPRepository:   repos.OTPs,
+		Mailer:          m.InstrumentMailer(deps.Mailer),
+		Metrics:         m,
+		TokenSecret:     cfg.TokenSecret,
+		TokenExpiration: 3600,
+		OTPLifespan:     300,
+	}
+	rankers, err := ranking.NewExperiment(cfg.FeedRankers)
+	if err != nil {
+		return nil, err
+	}
+	feedService := &services.FeedService{Timelines: repos.Timelines, Posts: repos.Posts, FanOutLimit: cfg.FeedFanOutLimit, Rankers: rankers}
+	postService := services.NewPostService(repos.Posts)
+	postService.Metrics = m
+	postService.Feed = feedService
+	postService.Tx = repos.Tx
+	entityService := &services.EntityService{Users: repos.Users, Hashtags: repos.Hashtags, Notifications: repos.Notifications, Posts: repos.Posts}
+	postService.Entities = entityService
+	postService.Notifications = repos.Notifications
+	postService.Profiles = repos.UserProfiles
+	jobService := &services.JobService{Repo: repos.Jobs, Metrics: m}
+	userProfileService := services.NewUserProfileService(repos.UserProfiles)
+	videoService := &services.VideoProfileService{Repo: repos.VideoProfiles}
+	educationService := &services.UserEducationService{Repo: repos.UserEducation}
+	userExperienceService := services.NewUserExperienceService(repos.UserExperience)
+	postLikeService := &services.PostLikeService{PostLikeRepository: repos.PostLikes, Metrics: m, Posts: repos.Posts}
+	postCommentService := &services.PostCommentService{PostCommentRepository: repos.PostComments, Entities: entityService, Tx: repos.Tx, Posts: repos.Posts}
+	followService := &services.FollowService{FollowRepository: repos.Follows, Tx: repos.Tx, Metrics: m, Feed: feedService}
+	notificationService := services.NewNotificationService(repos.Notifications)
+	draftService := &services.DraftService{Repo: repos.Drafts, Posts: postService, Tx: repos.Tx, Notifications: repos.Notifications}
+	pollService := &services.PollService{Repo: repos.Polls, Posts: postService, Tx: repos.Tx, Notifications: repos.Notifications}
+
+	cookies := session.NewCookies(cfg)
+
+	// Initialize controllers, one module per domain
+	modules := []RouteRegistrar{
+		&controllers.AuthController{AuthService: authService, Cookies: cookies},
+		&controllers.PostController{PostService: postService, Uploader: uploader, IsAdmin: cfg.IsAdmin},
+		&controllers.DraftController{DraftService: draftService},
+		&controllers.PollController{PollService: pollService},
+		&controllers.FeedController{FeedService: feedService, IsAdmin: cfg.IsAdmin},
+		&controllers.JobController{JobService: jobService},
+		controllers.NewUserProfileController(userProfileService, uploader),
+		&controllers.VideoProfileController{VideoProfileService: videoService, Uploader: uploader},
+		controllers.NewUploadController(uploader),
+		&controllers.UserEducationController{Service: educationService},
+		controllers.NewUserExperienceController(userExperienceService),
+		&controllers.PostLikeController{PostLikeService: postLikeService},
+		&controllers.PostCommentController{PostCommentService: postCommentService},
+		&controllers.HashtagController{EntityService: entityService},
+		&controllers.FollowController{FollowService: followService},
+		controllers.NewNotificationController(notificationService),
+	}
+
+	limits := rateLimitStore(cfg, repos)
+	keys := idempotencyStore(repos)
+	perIP, perUser := rateLimitRules(cfg)
+
+	observe := []gin.HandlerFunc{
+		middlewares.Tracing(),
+		middlewares.AccessLog(logger),
+		middlewares.Metrics(m),
+	}
+	global := []gin.HandlerFunc{
+		middlewares.CORS(cors),
+		middlewares.RateLimit(limits, perIP...),
+		middlewares.RequestTimeout(requestTimeout(cfg)),
+	}
+	auth := gin.HandlersChain{
+		middlewares.DeserializeUser(repos.Users, cfg.TokenSecret),
+		middlewares.RateLimit(limits, perUser...),
+		middlewares.CSRF(cookies),
+		middlewares.Idempotency(keys, idempotencyPolicy(cfg)),
+	}
+	router, err := NewRouter(RouterOptions{
+		Observe:        observe,
+		Global:         global,
+		Auth:           auth,
+		Root:           []RootModule{&metricsModule{metrics: m, token: cfg.MetricsToken}},
+		TrustedProxies: cfg.TrustedProxies(),
+	}, modules...)
+	if err != nil {
+		return nil, fmt.Errorf("app: %w", err)
+	}
+
+	return &App{
+		Config: cfg,
+		DB:     deps.DB,
+		Router: router,
+
+		logger:      logger,
+		metrics:     m,
+		rateLimits:  limits,
+		idempotency: keys,
+		drafts:      draftService,
+		polls:       pollService,
+	}, nil
+}
+
+// Run starts the HTTP server on the configured port.
+func (a *App) Run() error {
+	port := a.Config.ServerPort
+	if port == "" {
+		port = defaultServerPort
+	}
+
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           a.Router,
+		ReadHeaderTimeout: serverReadHeaderTimeout,
+		ReadTimeout:       serverReadTimeout,
+		WriteTimeout:      requestTimeout(a.Config) + serverWriteGrace,
+		IdleTimeout:       serverIdleTimeout,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEvery(ctx, a.logger, a.metrics, "rate_limit_prune", rateLimitPruneEvery, func(ctx context.Context) error {
+		return a.rateLimits.Prune(ctx, rateLimitIdle)
+	})
+	go runEvery(ctx, a.logger, a.metrics, "idempotency_prune", idempotencyPruneEvery, a.idempotency.Prune)
+	go runEvery(ctx, a.logger, a.metrics, "scheduled_posts", scheduledPostsEvery, a.drafts.PublishDue)
+	go runEvery(ctx, a.logger, a.metrics, "ended_polls", endedPollsEvery, a.polls.NotifyEnded)
+
+	a.logger.Info("server listening", "port", port)
+	return server.ListenAndServe()
+}
+
+// corsMaxAge returns the configured preflight cache duration or the default.
+func corsMaxAge(cfg config.Config) time.Duration {
+	if cfg.CORSMaxAge > 0 {
+		return cfg.CORSMaxAge
+	}
+	return defaultCORSMaxAge
+}
+
+// requestTimeout returns the configured per-request deadline or the default.
+func requestTimeout(cfg config.Config) time.Duration {
+	if cfg.RequestTimeout > 0 {
+		return cfg.RequestTimeout
+	}
+	return defaultRequestTimeout
+}
+
+// RunServer loads the configuration, connects to the database, runs the
+// migrations and serves the application until the server stops.
+func RunServer() error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	logger := newLogger(cfg)
+	slog.SetDefault(logger)
+
+	if cfg.DBQueryTimeout > 0 {
+		repositories.DefaultQueryTimeout = cfg.DBQueryTimeout
+	}
+	if cfg.PageSizeMax > 0 {
+		pagination.MaxLimit = cfg.PageSizeMax
+	}
+	if cfg.PageSizeDefault > 0 {
+		pagination.DefaultLimit = min(cfg.PageSizeDefault, pagination.MaxLimit)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: cfg.TracingServiceName,
+		Endpoint:    cfg.TracingEndpoint,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("setting up tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("flushing traces", "error", err)
+		}
+	}()
+	if cfg.TracingEndpoint != "" {
+		logger.Info("exporting traces", "endpoint", cfg.TracingEndpoint)
+	}
+
+	db, err := config.ConnectDB(&cfg)
+	if err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+	defer db.Close()
+	logger.Info("connected to database", "host", cfg.DBHost, "name", cfg.DBName)
+
+	if err := migrations.Migrate(db); err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+	logger.Info("migrations complete")
+
+	uploader, err := utils.NewS3Uploader(cfg)
+	if err != nil {
+		return fmt.Errorf("creating S3 uploader: %w", err)
+	}
+
+	app, err := NewApp(Dependencies{
+		Config:       cfg,
+		DB:           db,
+		Repositories: repositories.NewPostgres(db),
+		Uploader:     uploader,
+		Mailer:       utils.NewSMTPMailer(cfg, logger),
+		Logger:       logger,
+	})
+	if err != nil {
+		return err
+	}
+
+	return app.Run()
+}
+
+// newLogger writes JSON in production and text elsewhere unless LOG_FORMAT
+// says otherwise.
+func newLogger(cfg config.Config) *slog.Logger {
+	format := cfg.LogFormat
+	if format == "" && cfg.IsProduction() {
+		format = "json"
+	}
+	return logging.New(os.Stdout, logging.Options{Format: format, Level: cfg.LogLevel})
+}
@@ -0,0 +1,265 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/controllers"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/logging"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories/memory"
+)
+
+type fakeUploader struct{}
+
+func (fakeUploader) UploadFile(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, key string) (string, error) {
+	return "https://uploads.example.com/" + key, nil
+}
+
+func (fakeUploader) DeleteFile(ctx context.Context, url string) error {
+	return nil
+}
+
+// recordingUploader remembers the URLs of the files it was asked to delete.
+type recordingUploader struct {
+	fakeUploader
+	deleted []string
+}
+
+func (u *recordingUploader) DeleteFile(ctx context.Context, url string) error {
+	u.deleted = append(u.deleted, url)
+	return nil
+}
+
+type fakeMailer struct {
+	lastBody string
+}
+
+func (m *fakeMailer) SendEmail(to, subject, body string) error {
+	m.lastBody = body
+	return nil
+}
+
+func newTestApp(t *testing.T) (*App, *fakeMailer) {
+	t.Helper()
+	return newTestAppWithConfig(t, config.Config{})
+}
+
+func newTestAppWithConfig(t *testing.T, cfg config.Config) (*App, *fakeMailer) {
+	t.Helper()
+	return newTestAppWith(t, cfg, fakeUploader{})
+}
+
+func newTestAppWithUploader(t *testing.T, uploader controllers.Uploader) (*App, *fakeMailer) {
+	t.Helper()
+	return newTestAppWith(t, config.Config{}, uploader)
+}
+
+func newTestAppWith(t *testing.T, cfg config.Config, uploader controllers.Uploader) (*App, *fakeMailer) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg.TokenSecret = "test-secret"
+	mailer := &fakeMailer{}
+	app, err := NewApp(Dependencies{
+		Config:       cfg,
+		Repositories: memory.NewRepositories(),
+		Uploader:     uploader,
+		Mailer:       mailer,
+		Logger:       logging.Discard(),
+	})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	return app, mailer
+}
+
+func doJSON(t *testing.T, router http.Handler, method, path, token string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNewAppRequiresDependencies(t *testing.T) {
+	if _, err := NewApp(Dependencies{}); err == nil {
+		t.Fatal("NewApp without dependencies succeeded")
+	}
+}
+
+func TestNewAppRejectsAnyOriginWithCredentials(t *testing.T) {
+	_, err := NewApp(Dependencies{
+		Config:       config.Config{ClientOrigin: "https://app.example.com,*"},
+		Repositories: memory.NewRepositories(),
+		Uploader:     fakeUploader{},
+		Mailer:       &fakeMailer{},
+	})
+	if err == nil {
+		t.Fatal(`NewApp with CLIENT_ORIGIN "*" succeeded`)
+	}
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/posts", "", nil)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d; want 401", rec.Code)
+	}
+
+	var body struct {
+		Error struct {
+			Code      string `json:"code"`
+			RequestID string `json:"request_id"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	if body.Error.Code != "not_logged_in" || body.Error.RequestID == "" {
+		t.Errorf("error = %+v; want not_logged_in with a request id", body.Error)
+	}
+}
+
+func TestUnknownRouteUsesErrorEnvelope(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	rec := doJSON(t, app.Router, http.MethodGet, "/no/such/route", "", nil)
+	if rec.Code != http.StatusNotFound || !bytes.Contains(rec.Body.Bytes(), []byte(`"route_not_found"`)) {
+		t.Fatalf("got %d %s; want 404 route_not_found", rec.Code, rec.Body)
+	}
+}
+
+// registerAndLogin signs up and verifies a user through the API and returns
+// a session token.
+func registerAndLogin(t *testing.T, app *App, mailer *fakeMailer, username string) string {
+	t.Helper()
+
+	email := username + "@example.com"
+	rec := doJSON(t, app.Router, http.MethodPost, APIV1+"/auth/register", "", map[string]string{
+		"email": email, "username": username, "password": "s3cret-pass",
+	})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register status = %d; body %s", rec.Code, rec.Body)
+	}
+
+	otp := regexp.MustCompile(`Your OTP is: (\d+)`).FindStringSubmatch(mailer.lastBody)
+	if otp == nil {
+		t.Fatalf("no OTP in email %q", mailer.lastBody)
+	}
+	rec = doJSON(t, app.Router, http.MethodPost, APIV1+"/auth/verify-otp", "", map[string]string{
+		"email": email, "otp": otp[1],
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("verify status = %d; body %s", rec.Code, rec.Body)
+	}
+
+	rec = doJSON(t, app.Router, http.MethodPost, APIV1+"/auth/login", "", map[string]string{
+		"emailOrUsername": username, "password": "s3cret-pass",
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login status = %d; body %s", rec.Code, rec.Body)
+	}
+	var login struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &login); err != nil || login.Token == "" {
+		t.Fatalf("login body %s: %v", rec.Body, err)
+	}
+	return login.Token
+}
+
+func TestRegisterLoginAndBrowse(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token := registerAndLogin(t, app, mailer, "alice")
+
+	rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/posts", token, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("feed status = %d; body %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Deprecation") != "" {
+		t.Error("versioned route is marked deprecated")
+	}
+}
+
+func TestHomeFeedOfNewUserListsPopularPosts(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token := registerAndLogin(t, app, mailer, "heidi")
+
+	rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/feed", token, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("home feed status = %d; body %s", rec.Code, rec.Body)
+	}
+	var feed struct {
+		Items  []json.RawMessage `json:"items"`
+		Source string            `json:"source"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	if feed.Source != "popular" || feed.Items == nil {
+		t.Errorf("home feed = %s; want an empty list of popular posts", rec.Body)
+	}
+}
+
+func TestTopFeedScoresAreForAdminsOnly(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token := registerAndLogin(t, app, mailer, "ivan")
+
+	rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/feed?mode=top", token, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("top feed status = %d; body %s", rec.Code, rec.Body)
+	}
+	if rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/feed?mode=top&debug=true", token, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("top feed with debug by a non-admin status = %d; want 403", rec.Code)
+	}
+	if rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/feed?mode=best", token, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("feed with an unknown mode status = %d; want 400", rec.Code)
+	}
+}
+
+func TestRegisterRejectsInvalidInput(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	rec := doJSON(t, app.Router, http.MethodPost, APIV1+"/auth/register", "", map[string]string{
+		"email": "", "username": "alice", "password": "s3cret-pass",
+	})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d; want 400", rec.Code)
+	}
+
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details []struct {
+				Field string `json:"field"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	if body.Error.Code != "validation_failed" || len(body.Error.Details) != 1 || body.Error.Details[0].Field != "email" {
+		t.Errorf("error = %+v; want validation_failed on email", body.Error)
+	}
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
+)
+
+// runEvery runs fn every interval until ctx is cancelled, recording each run
+// as the background job named job.
+func runEvery(ctx context.Context, logger *slog.Logger, m *metrics.Metrics, job string, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			err := fn(ctx)
+			m.ObserveBackground(job, time.Since(start), err)
+			if err != nil {
+				logger.Error("background job failed", "job", job, "err", err)
+			}
+		}
+	}
+}
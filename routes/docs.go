@@ -0,0 +1,93 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
+	"github.com/sagar-rathod-devops/do-host-network-backend/middlewares"
+)
+
+const (
+	specPath = "/openapi.json"
+	docsPath = "/docs"
+)
+
+var apiInfo = openapi.Info{
+	Title:       "Do Host Network API",
+	Version:     "1.0.0",
+	Description: "Authenticate with the token returned by " + APIV1 + "/auth/login, either as a bearer token or the \"token\" cookie. Errors share one JSON envelope. Unversioned paths are deprecated aliases of " + APIV1 + ".",
+}
+
+// docsModule serves the OpenAPI document of the other modules together with
+// a docs UI that renders it.
+type docsModule struct {
+	modules []RouteRegistrar
+	legacy  *legacyModule
+	root    []RootModule
+}
+
+func (m *docsModule) Mount(router *gin.Engine) {
+	doc := m.document()
+	router.GET(specPath, openapi.SpecHandler(doc))
+	router.GET(docsPath, openapi.DocsHandler(specPath))
+}
+
+func (m *docsModule) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodGet,
+			Path:      specPath,
+			Tag:       "docs",
+			Summary:   "OpenAPI description of this API",
+			Public:    true,
+			Responses: map[int]any{http.StatusOK: map[string]any{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      docsPath,
+			Tag:       "docs",
+			Summary:   "Browsable API reference",
+			Public:    true,
+			Produces:  "text/html",
+			Responses: map[int]any{http.StatusOK: openapi.Binary{}},
+		},
+	}
+}
+
+// document describes the versioned routes, their legacy aliases as
+// deprecated copies, and the root routes including the docs themselves.
+func (m *docsModule) document() *openapi.Document {
+	b := openapi.NewBuilder(apiInfo, middlewares.ErrorBody{})
+	b.Add(m.OpenAPI()...)
+	for _, module := range m.root {
+		b.Add(module.OpenAPI()...)
+	}
+
+	current := make(map[string]openapi.Operation)
+	for _, module := range m.modules {
+		d, ok := module.(openapi.Documenter)
+		if !ok {
+			continue
+		}
+		for _, op := range d.OpenAPI() {
+			op.Path = APIV1 + op.Path
+			current[op.Method+" "+op.Path] = op
+			b.Add(op)
+		}
+	}
+
+	for _, alias := range m.legacy.aliases {
+		op, ok := current[alias.SuccessorKey()]
+		if !ok {
+			continue
+		}
+		op.Method = alias.Method
+		op.Path = alias.Path
+		op.Summary = "Deprecated alias of " + alias.SuccessorKey()
+		op.Deprecated = true
+		b.Add(op)
+	}
+
+	return b.Document()
+}
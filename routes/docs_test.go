@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
+)
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	rec := doJSON(t, app.Router, http.MethodGet, specPath, "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d", specPath, rec.Code)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decoding spec: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %q; want %q", doc.OpenAPI, openapi.Version)
+	}
+
+	documented := 0
+	for _, route := range app.Router.Routes() {
+		item, ok := doc.Paths[openapi.PathFor(route.Path)]
+		if !ok || (*item)[strings.ToLower(route.Method)] == nil {
+			t.Errorf("%s %s is not in the OpenAPI document", route.Method, route.Path)
+			continue
+		}
+		documented++
+	}
+
+	total := 0
+	for _, item := range doc.Paths {
+		total += len(*item)
+	}
+	if total != documented {
+		t.Errorf("document has %d operations but only %d routes are registered", total, documented)
+	}
+}
+
+func TestDocsUIIsServed(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	rec := doJSON(t, app.Router, http.MethodGet, docsPath, "", nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("GET %s = %d %s", docsPath, rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), `fetch("`+specPath+`")`) {
+		t.Error("docs page does not load the spec")
+	}
+}
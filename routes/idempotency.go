@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+const (
+	idempotencyKeyTTL     = 24 * time.Hour
+	idempotencyPruneEvery = time.Hour
+)
+
+// idempotencyPolicy keeps keys for a day. No request outlives the request
+// timeout plus the write grace, so a key held longer than that belongs to a
+// request that was lost.
+func idempotencyPolicy(cfg config.Config) idempotency.Policy {
+	return idempotency.Policy{
+		TTL:         idempotencyKeyTTL,
+		LockTimeout: requestTimeout(cfg) + serverWriteGrace,
+	}
+}
+
+func idempotencyStore(repos repositories.Repositories) idempotency.Store {
+	if repos.Idempotency == nil {
+		return idempotency.NewMemoryStore()
+	}
+	return repos.Idempotency
+}
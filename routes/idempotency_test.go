@@ -0,0 +1,76 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/idempotency"
+)
+
+func TestCreateRetriesWithIdempotencyKeyAreReplayed(t *testing.T) {
+	app, mailer := newTestApp(t)
+	registerAndLogin(t, app, mailer, "frank")
+
+	rec := doJSON(t, app.Router, http.MethodPost, APIV1+"/auth/login", "", map[string]string{
+		"emailOrUsername": "frank", "password": "s3cret-pass",
+	})
+	var login struct {
+		Token  string `json:"token"`
+		UserID string `json:"userID"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &login); err != nil {
+		t.Fatal(err)
+	}
+
+	post := func(path, key, title string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{
+			"job_title":          title,
+			"company_name":       "Hotel",
+			"job_description":    "Cook",
+			"last_date_to_apply": time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		})
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+login.Token)
+		req.Header.Set(idempotency.Header, key)
+		rec := httptest.NewRecorder()
+		app.Router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := post(APIV1+"/jobs", "job-1", "Chef")
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first status = %d; body %s", first.Code, first.Body)
+	}
+
+	// The legacy alias is the same route, so its retry is recognized too.
+	for _, path := range []string{APIV1 + "/jobs", "/posts/job"} {
+		retry := post(path, "job-1", "Chef")
+		if retry.Code != first.Code || retry.Body.String() != first.Body.String() || retry.Header().Get(idempotency.ReplayedHeader) != "true" {
+			t.Fatalf("retry via %s = %d %s (replayed %q); want the first response replayed",
+				path, retry.Code, retry.Body, retry.Header().Get(idempotency.ReplayedHeader))
+		}
+	}
+
+	if rec := post(APIV1+"/jobs", "job-1", "Waiter"); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("reused key status = %d; want 422", rec.Code)
+	}
+	if rec := post(APIV1+"/jobs", "job-2", "Waiter"); rec.Code != http.StatusCreated || rec.Header().Get(idempotency.ReplayedHeader) != "" {
+		t.Fatalf("new key status = %d; want a fresh 201", rec.Code)
+	}
+
+	var jobs struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	rec = doJSON(t, app.Router, http.MethodGet, APIV1+"/jobs", login.Token, nil)
+	if err := json.Unmarshal(rec.Body.Bytes(), &jobs); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	if len(jobs.Items) != 2 {
+		t.Fatalf("created %d jobs; want 2", len(jobs.Items))
+	}
+}
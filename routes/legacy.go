@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
+)
+
+// legacyPolicy dates the retirement of the unversioned routes, which were
+// replaced by APIV1.
+var legacyPolicy = deprecation.Policy{
+	DeprecatedAt: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+	SunsetAt:     time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+}
+
+// legacyModule mounts the pre-v1 aliases of the other modules and reports
+// how often each one is still called.
+type legacyModule struct {
+	aliases []deprecation.Alias
+	usage   *deprecation.Usage
+}
+
+func newLegacyModule(modules []RouteRegistrar) *legacyModule {
+	m := &legacyModule{usage: deprecation.NewUsage()}
+	for _, module := range modules {
+		if l, ok := module.(LegacyRegistrar); ok {
+			for _, alias := range l.LegacyRoutes() {
+				alias.Successor = APIV1 + alias.Successor
+				m.aliases = append(m.aliases, alias)
+			}
+		}
+	}
+	return m
+}
+
+// RegisterRoutes mounts the usage report.
+func (m *legacyModule) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.GET("/meta/deprecated-routes", m.report)
+}
+
+func (m *legacyModule) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodGet,
+			Path:      "/meta/deprecated-routes",
+			Tag:       "meta",
+			Summary:   "Calls to deprecated routes since the server started",
+			Responses: map[int]any{http.StatusOK: []deprecation.Stat{}},
+		},
+	}
+}
+
+// mount registers every alias at its legacy path. The deprecation headers
+// are set before authentication so rejected calls carry them too.
+func (m *legacyModule) mount(router *gin.Engine, auth gin.HandlersChain) {
+	for _, alias := range m.aliases {
+		handlers := []gin.HandlerFunc{legacyPolicy.Middleware(alias, m.usage)}
+		if alias.Protected {
+			handlers = append(handlers, auth...)
+		}
+		router.Handle(alias.Method, alias.Path, append(handlers, alias.Handler)...)
+	}
+}
+
+func (m *legacyModule) report(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, m.usage.Snapshot())
+}
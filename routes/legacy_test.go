@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/deprecation"
+)
+
+func TestLegacyAliasesAreDeprecated(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token := registerAndLogin(t, app, mailer, "alice")
+
+	rec := doJSON(t, app.Router, http.MethodGet, "/posts/all-content", token, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("legacy feed status = %d; body %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Deprecation") == "" || rec.Header().Get("Sunset") == "" {
+		t.Errorf("missing deprecation headers: %v", rec.Header())
+	}
+	if got, want := rec.Header().Get("Link"), `</api/v1/posts>; rel="successor-version"`; got != want {
+		t.Errorf("Link = %q; want %q", got, want)
+	}
+
+	// Rejected calls are marked and counted too.
+	rec = doJSON(t, app.Router, http.MethodGet, "/user/profile/"+"00000000-0000-0000-0000-000000000001", "", nil)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("Deprecation") == "" {
+		t.Errorf("unauthenticated legacy call = %d with Deprecation %q", rec.Code, rec.Header().Get("Deprecation"))
+	}
+	if got, want := rec.Header().Get("Link"), `</api/v1/profiles/00000000-0000-0000-0000-000000000001>; rel="successor-version"`; got != want {
+		t.Errorf("Link = %q; want %q", got, want)
+	}
+
+	rec = doJSON(t, app.Router, http.MethodGet, APIV1+"/meta/deprecated-routes", token, nil)
+	var stats []deprecation.Stat
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	calls := make(map[string]uint64)
+	for _, s := range stats {
+		calls[s.Route] = s.Calls
+	}
+	if calls["GET /posts/all-content"] != 1 || calls["GET /user/profile/:user_id"] != 1 {
+		t.Errorf("usage = %+v", stats)
+	}
+}
+
+func TestLegacyAliasUsesSuccessorHandler(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token := registerAndLogin(t, app, mailer, "alice")
+
+	rec := doJSON(t, app.Router, http.MethodPost, "/auth/login", "", map[string]string{
+		"emailOrUsername": "alice", "password": "wrong-password",
+	})
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("legacy login with a wrong password = %d; want 401", rec.Code)
+	}
+
+	rec = doJSON(t, app.Router, http.MethodGet, "/auth/logout", token, nil)
+	if rec.Code != http.StatusOK {
+		t.Errorf("legacy logout = %d; body %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/apperrors"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/openapi"
+)
+
+const metricsPath = "/metrics"
+
+// metricsModule serves the metrics to Prometheus. When token is set scrapes
+// must present it as a bearer token.
+type metricsModule struct {
+	metrics *metrics.Metrics
+	token   string
+}
+
+func (m *metricsModule) Mount(router *gin.Engine) {
+	router.GET(metricsPath, m.authorize, gin.WrapH(m.metrics.Registry.Handler()))
+}
+
+func (m *metricsModule) OpenAPI() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodGet,
+			Path:      metricsPath,
+			Tag:       "meta",
+			Summary:   "Prometheus metrics; requires METRICS_TOKEN as a bearer token when configured",
+			Public:    true,
+			Produces:  metrics.ContentType,
+			Responses: map[int]any{http.StatusOK: openapi.Binary{}},
+			Errors:    []int{http.StatusUnauthorized},
+		},
+	}
+}
+
+func (m *metricsModule) authorize(ctx *gin.Context) {
+	if m.token == "" {
+		return
+	}
+	want := "Bearer " + m.token
+	if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), []byte(want)) != 1 {
+		ctx.Error(apperrors.Unauthorized("invalid_metrics_token", "A valid metrics token is required"))
+		ctx.Abort()
+	}
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/metrics"
+)
+
+func TestMetricsEndpointReportsRouteTemplates(t *testing.T) {
+	app, _ := newTestApp(t)
+	doJSON(t, app.Router, http.MethodGet, APIV1+"/posts", "", nil)
+
+	rec := httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != metrics.ContentType {
+		t.Errorf("Content-Type = %q; want %q", got, metrics.ContentType)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `http_request_duration_seconds_count{method="GET",route="`+APIV1+`/posts"`) {
+		t.Errorf("metrics missing posts route:\n%s", body)
+	}
+}
+
+func TestMetricsEndpointRequiresToken(t *testing.T) {
+	app, _ := newTestAppWithConfig(t, config.Config{MetricsToken: "scrape"})
+
+	rec := httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d; want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape")
+	rec = httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; want 200", rec.Code)
+	}
+}
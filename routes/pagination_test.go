@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestJobBoardIsPaged(t *testing.T) {
+	app, mailer := newTestApp(t)
+	registerAndLogin(t, app, mailer, "grace")
+
+	rec := doJSON(t, app.Router, http.MethodPost, APIV1+"/auth/login", "", map[string]string{
+		"emailOrUsername": "grace", "password": "s3cret-pass",
+	})
+	var login struct {
+		Token  string `json:"token"`
+		UserID string `json:"userID"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &login); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, title := range []string{"Chef", "Waiter", "Concierge"} {
+		rec := doJSON(t, app.Router, http.MethodPost, APIV1+"/jobs", login.Token, map[string]string{
+			"job_title":          title,
+			"company_name":       "Hotel",
+			"job_description":    "Serve guests",
+			"last_date_to_apply": time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("create %s status = %d; body %s", title, rec.Code, rec.Body)
+		}
+	}
+
+	type page struct {
+		Items []struct {
+			JobTitle string `json:"job_title"`
+		} `json:"items"`
+		NextCursor string `json:"next_cursor"`
+	}
+	get := func(query string) page {
+		t.Helper()
+		rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/jobs"+query, login.Token, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /jobs%s status = %d; body %s", query, rec.Code, rec.Body)
+		}
+		var p page
+		if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+		return p
+	}
+
+	first := get("?limit=2")
+	if len(first.Items) != 2 || first.Items[0].JobTitle != "Concierge" || first.NextCursor == "" {
+		t.Fatalf("first page = %+v; want the two newest jobs and a cursor", first)
+	}
+	second := get("?limit=2&cursor=" + first.NextCursor)
+	if len(second.Items) != 1 || second.Items[0].JobTitle != "Chef" || second.NextCursor != "" {
+		t.Fatalf("second page = %+v; want the oldest job and no cursor", second)
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=1000", "?cursor=not-a-cursor"} {
+		rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/jobs"+query, login.Token, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET /jobs%s status = %d; want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestCreateJobIgnoresUserIDField(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token, userID := loginAs(t, app, mailer, "nina")
+	_, otherID := loginAs(t, app, mailer, "omar")
+
+	rec := doJSON(t, app.Router, http.MethodPost, APIV1+"/jobs", token, map[string]string{
+		"user_id":         otherID,
+		"job_title":       "Chef",
+		"company_name":    "Hotel",
+		"job_description": "Cook",
+	})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d; body %s", rec.Code, rec.Body)
+	}
+
+	rec = doJSON(t, app.Router, http.MethodGet, APIV1+"/jobs", token, nil)
+	var jobs struct {
+		Items []struct {
+			UserID string `json:"user_id"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &jobs); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	if len(jobs.Items) != 1 || jobs.Items[0].UserID != userID {
+		t.Fatalf("jobs = %s; want one job posted by %s", rec.Body, userID)
+	}
+}
@@ -0,0 +1,265 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testFile struct {
+	field, name string
+	content     []byte
+}
+
+func postMultipart(t *testing.T, router http.Handler, path, token string, fields map[string][]string, files ...testFile) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, values := range fields {
+		for _, v := range values {
+			w.WriteField(name, v)
+		}
+	}
+	for _, f := range files {
+		part, err := w.CreateFormFile(f.field, f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(f.content)
+	}
+	w.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func loginAs(t *testing.T, app *App, mailer *fakeMailer, username string) (token, userID string) {
+	t.Helper()
+	registerAndLogin(t, app, mailer, username)
+	rec := doJSON(t, app.Router, http.MethodPost, APIV1+"/auth/login", "", map[string]string{
+		"emailOrUsername": username, "password": "s3cret-pass",
+	})
+	var login struct {
+		Token  string `json:"token"`
+		UserID string `json:"userID"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &login); err != nil {
+		t.Fatal(err)
+	}
+	return login.Token, login.UserID
+}
+
+func TestPostAttachmentsKeepOrderAndMetadata(t *testing.T) {
+	uploader := &recordingUploader{}
+	app, mailer := newTestAppWithUploader(t, uploader)
+	token, userID := loginAs(t, app, mailer, "judy")
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 3, 2))); err != nil {
+		t.Fatal(err)
+	}
+	pdf := []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n")
+
+	rec := postMultipart(t, app.Router, APIV1+"/posts", token,
+		map[string][]string{"post_content": {"Menu and kitchen"}, "alt_text": {"Our menu", "The kitchen"}},
+		testFile{"attachments", "menu.pdf", pdf},
+		testFile{"attachments", "kitchen.png", img.Bytes()},
+	)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d; body %s", rec.Code, rec.Body)
+	}
+
+	rec = doJSON(t, app.Router, http.MethodGet, APIV1+"/users/"+userID+"/posts", token, nil)
+	var posts struct {
+		Items []struct {
+			MediaURL    string `json:"media_url"`
+			Attachments []struct {
+				URL       string `json:"url"`
+				MimeType  string `json:"mime_type"`
+				SizeBytes int64  `json:"size_bytes"`
+				Width     *int   `json:"width"`
+				Height    *int   `json:"height"`
+				AltText   string `json:"alt_text"`
+			} `json:"attachments"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &posts); err != nil || len(posts.Items) != 1 {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	got := posts.Items[0].Attachments
+	if len(got) != 2 || got[0].MimeType != "application/pdf" || got[0].AltText != "Our menu" || got[0].Width != nil ||
+		got[1].MimeType != "image/png" || got[1].AltText != "The kitchen" || got[1].SizeBytes != int64(img.Len()) {
+		t.Fatalf("attachments = %s; want the PDF then the image with their alt texts", rec.Body)
+	}
+	if got[1].Width == nil || *got[1].Width != 3 || got[1].Height == nil || *got[1].Height != 2 {
+		t.Errorf("image dimensions = %v x %v; want 3 x 2", got[1].Width, got[1].Height)
+	}
+	if posts.Items[0].MediaURL != got[0].URL {
+		t.Errorf("media_url = %q; want the first attachment %q", posts.Items[0].MediaURL, got[0].URL)
+	}
+
+	rec = postMultipart(t, app.Router, APIV1+"/posts", token,
+		map[string][]string{"post_content": {"Installer"}},
+		testFile{"attachments", "readme.pdf", pdf},
+		testFile{"attachments", "setup.exe", []byte("MZ\x90\x00\x03\x00\x00\x00")},
+	)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("create with an executable status = %d; want 400", rec.Code)
+	}
+	if len(uploader.deleted) != 1 || !strings.HasSuffix(uploader.deleted[0], "_readme.pdf") {
+		t.Errorf("deleted uploads = %v; want the PDF uploaded before the executable", uploader.deleted)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, APIV1+"/posts", strings.NewReader("--x\r\nbroken"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec = httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("create with a broken multipart body status = %d; want 400", rec.Code)
+	}
+}
+
+// mp4Box encodes an ISO base media box around payload.
+func mp4Box(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(out, typ...), body...)
+}
+
+// testMP4 is a 2.5 second 640x360 movie whose header follows its media
+// data, as written by encoders that do not optimise for streaming.
+func testMP4() []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], 2500)
+	audio := make([]byte, 84)
+	video := make([]byte, 84)
+	binary.BigEndian.PutUint32(video[76:], 640<<16)
+	binary.BigEndian.PutUint32(video[80:], 360<<16)
+	return bytes.Join([][]byte{
+		mp4Box("ftyp", []byte("mp42"), make([]byte, 4), []byte("isom")),
+		mp4Box("mdat", make([]byte, 64)),
+		mp4Box("moov", mp4Box("mvhd", mvhd),
+			mp4Box("trak", mp4Box("tkhd", audio)),
+			mp4Box("trak", mp4Box("tkhd", video))),
+	}, nil)
+}
+
+func TestVideoAttachmentMetadataIsReadFromTheFile(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token, userID := loginAs(t, app, mailer, "karl")
+
+	rec := postMultipart(t, app.Router, APIV1+"/posts", token,
+		map[string][]string{"post_content": {"Our lobby"}, "duration_seconds": {"999"}},
+		testFile{"attachments", "lobby.mp4", testMP4()},
+	)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d; body %s", rec.Code, rec.Body)
+	}
+
+	rec = doJSON(t, app.Router, http.MethodGet, APIV1+"/users/"+userID+"/posts", token, nil)
+	var posts struct {
+		Items []struct {
+			Attachments []struct {
+				MimeType        string   `json:"mime_type"`
+				Width           *int     `json:"width"`
+				Height          *int     `json:"height"`
+				DurationSeconds *float64 `json:"duration_seconds"`
+			} `json:"attachments"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &posts); err != nil || len(posts.Items) != 1 || len(posts.Items[0].Attachments) != 1 {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	got := posts.Items[0].Attachments[0]
+	if got.MimeType != "video/mp4" || got.DurationSeconds == nil || *got.DurationSeconds != 2.5 ||
+		got.Width == nil || *got.Width != 640 || got.Height == nil || *got.Height != 360 {
+		t.Fatalf("attachment = %s; want a 2.5 second 640x360 video", rec.Body)
+	}
+}
+
+func TestCreatePostIgnoresUserIDField(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token, userID := loginAs(t, app, mailer, "liam")
+	_, otherID := loginAs(t, app, mailer, "mia")
+
+	rec := postMultipart(t, app.Router, APIV1+"/posts", token,
+		map[string][]string{"user_id": {otherID}, "post_content": {"Not mia's post"}})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d; body %s", rec.Code, rec.Body)
+	}
+
+	rec = doJSON(t, app.Router, http.MethodGet, APIV1+"/users/"+otherID+"/posts", token, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("posts of the user named in user_id status = %d; want 404", rec.Code)
+	}
+	rec = doJSON(t, app.Router, http.MethodGet, APIV1+"/users/"+userID+"/posts", token, nil)
+	if rec.Code != http.StatusOK {
+		t.Errorf("posts of the current user status = %d; want 200", rec.Code)
+	}
+}
+
+func TestPostEntitiesAndTrendingHashtags(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token, userID := loginAs(t, app, mailer, "kate")
+
+	rec := postMultipart(t, app.Router, APIV1+"/posts", token,
+		map[string][]string{"post_content": {"Now hiring at #Hôtel_Lux, ask @kate"}})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d; body %s", rec.Code, rec.Body)
+	}
+	var created struct {
+		Entities []struct {
+			Type   string  `json:"type"`
+			Start  int     `json:"start"`
+			End    int     `json:"end"`
+			Text   string  `json:"text"`
+			UserID *string `json:"user_id"`
+		} `json:"entities"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	got := created.Entities
+	if len(got) != 2 || got[0].Text != "hôtel_lux" || got[0].Start != 14 || got[0].End != 24 ||
+		got[1].Type != "mention" || got[1].UserID == nil || *got[1].UserID != userID {
+		t.Fatalf("entities = %s; want the hashtag in code points and the resolved mention", rec.Body)
+	}
+
+	rec = doJSON(t, app.Router, http.MethodGet, APIV1+"/hashtags/trending?window=1", token, nil)
+	var trending struct {
+		WindowHours int `json:"window_hours"`
+		Hashtags    []struct {
+			Tag   string `json:"tag"`
+			Count int    `json:"count"`
+		} `json:"hashtags"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &trending); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("trending = %d %s: %v", rec.Code, rec.Body, err)
+	}
+	if trending.WindowHours != 1 || len(trending.Hashtags) != 1 || trending.Hashtags[0].Tag != "hôtel_lux" {
+		t.Errorf("trending = %s; want the new hashtag", rec.Body)
+	}
+
+	if rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/hashtags/%23HÔTEL_LUX/posts", token, nil); rec.Code != http.StatusOK {
+		t.Errorf("posts by hashtag status = %d; body %s", rec.Code, rec.Body)
+	}
+	if rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/hashtags/2024/posts", token, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("posts by a number status = %d; want 400", rec.Code)
+	}
+	if rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/hashtags/trending?window=1000", token, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("trending over 1000 hours status = %d; want 400", rec.Code)
+	}
+}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/ratelimit"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/repositories"
+)
+
+// Default limits in requests per minute.
+const (
+	defaultRateLimitPerIP   = 600
+	defaultRateLimitPerUser = 300
+	defaultRateLimitAuth    = 20
+	defaultRateLimitUploads = 30
+
+	// Buckets refill within a minute, so an hour of idleness is plenty
+	// before they are forgotten.
+	rateLimitPruneEvery = 10 * time.Minute
+	rateLimitIdle       = time.Hour
+)
+
+// uploadRoutes accept file uploads, which cost far more than other writes.
+var uploadRoutes = []string{
+	APIV1 + "/uploads",
+	APIV1 + "/posts",
+	APIV1 + "/profiles",
+	APIV1 + "/profiles/:user_id",
+	APIV1 + "/videos",
+	APIV1 + "/videos/:id",
+}
+
+// rateLimitRules returns the rules applied before authentication, keyed by
+// client IP, and after it, keyed by user. Legacy aliases count against the
+// rules of their successor.
+func rateLimitRules(cfg config.Config) (perIP, perUser []ratelimit.Rule) {
+	perIP = []ratelimit.Rule{
+		{Name: "auth", Routes: []string{APIV1 + "/auth/*"}, Limit: perMinute(cfg.RateLimitAuth, defaultRateLimitAuth)},
+		{Name: "ip", Limit: perMinute(cfg.RateLimitPerIP, defaultRateLimitPerIP)},
+	}
+	perUser = []ratelimit.Rule{
+		{
+			Name:    "uploads",
+			Methods: []string{http.MethodPost, http.MethodPut},
+			Routes:  uploadRoutes,
+			Limit:   perMinute(cfg.RateLimitUploads, defaultRateLimitUploads),
+		},
+		{Name: "user", Limit: perMinute(cfg.RateLimitPerUser, defaultRateLimitPerUser)},
+	}
+	return perIP, perUser
+}
+
+func perMinute(configured, fallback int) ratelimit.Limit {
+	if configured > 0 {
+		return ratelimit.PerMinute(configured)
+	}
+	return ratelimit.PerMinute(fallback)
+}
+
+// rateLimitStore returns the store the repositories provide, which is shared
+// by every replica, unless RATE_LIMIT_STORE asks for process memory.
+func rateLimitStore(cfg config.Config, repos repositories.Repositories) ratelimit.Store {
+	if repos.RateLimits == nil || strings.EqualFold(cfg.RateLimitStore, "memory") {
+		return ratelimit.NewMemoryStore()
+	}
+	return repos.RateLimits
+}
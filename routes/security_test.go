@@ -0,0 +1,157 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/session"
+)
+
+func cookieRequest(method, path string, cookies ...*http.Cookie) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	return req
+}
+
+func TestCookieSessionsRequireCSRFToken(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token := registerAndLogin(t, app, mailer, "carol")
+	sessionCookie := &http.Cookie{Name: session.TokenCookie, Value: token}
+	csrfCookie := &http.Cookie{Name: session.CSRFCookie, Value: "csrf-value"}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"safe method", cookieRequest(http.MethodGet, APIV1+"/posts", sessionCookie), http.StatusOK},
+		{"no header", cookieRequest(http.MethodPost, APIV1+"/auth/logout", sessionCookie, csrfCookie), http.StatusForbidden},
+		{"no cookie", func() *http.Request {
+			req := cookieRequest(http.MethodPost, APIV1+"/auth/logout", sessionCookie)
+			req.Header.Set(session.CSRFHeader, "csrf-value")
+			return req
+		}(), http.StatusForbidden},
+		{"mismatch", func() *http.Request {
+			req := cookieRequest(http.MethodPost, APIV1+"/auth/logout", sessionCookie, csrfCookie)
+			req.Header.Set(session.CSRFHeader, "other")
+			return req
+		}(), http.StatusForbidden},
+		{"match", func() *http.Request {
+			req := cookieRequest(http.MethodPost, APIV1+"/auth/logout", sessionCookie, csrfCookie)
+			req.Header.Set(session.CSRFHeader, "csrf-value")
+			return req
+		}(), http.StatusOK},
+		{"bearer", func() *http.Request {
+			req := cookieRequest(http.MethodPost, APIV1+"/auth/logout")
+			req.Header.Set("Authorization", "Bearer "+token)
+			return req
+		}(), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		app.Router.ServeHTTP(rec, tt.req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d; want %d; body %s", tt.name, rec.Code, tt.status, rec.Body)
+		}
+	}
+}
+
+func TestCookieSessionWithoutCSRFCookieGetsOne(t *testing.T) {
+	app, mailer := newTestApp(t)
+	token := registerAndLogin(t, app, mailer, "dave")
+
+	rec := httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, cookieRequest(http.MethodGet, APIV1+"/posts", &http.Cookie{Name: session.TokenCookie, Value: token}))
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == session.CSRFCookie && c.Value != "" && !c.HttpOnly {
+			return
+		}
+	}
+	t.Fatalf("no readable %s cookie issued: %v", session.CSRFCookie, rec.Result().Cookies())
+}
+
+func TestLoginSetsSessionCookies(t *testing.T) {
+	app, mailer := newTestApp(t)
+	registerAndLogin(t, app, mailer, "erin")
+
+	rec := doJSON(t, app.Router, http.MethodPost, APIV1+"/auth/login", "", map[string]string{
+		"emailOrUsername": "erin", "password": "s3cret-pass",
+	})
+	cookies := map[string]*http.Cookie{}
+	for _, c := range rec.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+
+	tok, csrf := cookies[session.TokenCookie], cookies[session.CSRFCookie]
+	if tok == nil || csrf == nil {
+		t.Fatalf("cookies = %v; want token and csrf cookies", rec.Result().Cookies())
+	}
+	if !tok.HttpOnly || csrf.HttpOnly {
+		t.Errorf("HttpOnly: token %v, csrf %v; want true, false", tok.HttpOnly, csrf.HttpOnly)
+	}
+	if tok.Secure || tok.SameSite != http.SameSiteLaxMode {
+		t.Errorf("development cookie Secure=%v SameSite=%v; want false, Lax", tok.Secure, tok.SameSite)
+	}
+}
+
+func TestAuthRoutesAreRateLimitedPerIP(t *testing.T) {
+	app, mailer := newTestAppWithConfig(t, config.Config{RateLimitAuth: 4})
+	registerAndLogin(t, app, mailer, "frank") // register, verify-otp, login
+
+	login := map[string]string{"emailOrUsername": "frank", "password": "s3cret-pass"}
+	if rec := doJSON(t, app.Router, http.MethodPost, APIV1+"/auth/login", "", login); rec.Code != http.StatusOK {
+		t.Fatalf("fourth call: status %d", rec.Code)
+	}
+
+	// The legacy alias draws from the same bucket as its successor.
+	rec := doJSON(t, app.Router, http.MethodPost, "/auth/login", "", login)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("fifth call: status %d; want 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" || rec.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("headers = %v; want Retry-After and X-RateLimit-Remaining: 0", rec.Header())
+	}
+
+	// Other routes keep their own budget.
+	if rec := doJSON(t, app.Router, http.MethodGet, APIV1+"/jobs", "", nil); rec.Code == http.StatusTooManyRequests {
+		t.Error("rate limit of auth routes applied to other routes")
+	}
+}
+
+// loginFrom posts an empty login claiming to be forwarded for client.
+func loginFrom(app *App, client string) int {
+	req := httptest.NewRequest(http.MethodPost, APIV1+"/auth/login", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", client)
+	rec := httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRateLimitIgnoresForwardedForFromUntrustedClients(t *testing.T) {
+	app, _ := newTestAppWithConfig(t, config.Config{RateLimitAuth: 1})
+
+	if code := loginFrom(app, "203.0.113.1"); code == http.StatusTooManyRequests {
+		t.Fatal("first call was rate limited")
+	}
+	if code := loginFrom(app, "203.0.113.2"); code != http.StatusTooManyRequests {
+		t.Errorf("call with a spoofed X-Forwarded-For: status %d; want 429", code)
+	}
+}
+
+func TestRateLimitTrustsForwardedForFromProxies(t *testing.T) {
+	// httptest requests come from 192.0.2.1.
+	app, _ := newTestAppWithConfig(t, config.Config{RateLimitAuth: 1, Proxies: "192.0.2.0/24"})
+
+	for _, client := range []string{"203.0.113.1", "203.0.113.2"} {
+		if code := loginFrom(app, client); code == http.StatusTooManyRequests {
+			t.Errorf("client %s: rate limited by the bucket of another client", client)
+		}
+	}
+}
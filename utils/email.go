@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"html/template"
+	"log/slog"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -40,38 +41,40 @@ func ParseTemplateDir(dir string) (*template.Template, error) {
 	return template.ParseFiles(paths...)
 }
 
-func SendEmail(to, subject, body string) error {
-	// Load configuration
-	cfg, err := config.LoadConfig(".")
-	if err != nil {
-		fmt.Println("Error loading config:", err)
-		return err
-	}
+// SMTPMailer sends email through the SMTP server from the configuration.
+type SMTPMailer struct {
+	Config config.Config
+	Logger *slog.Logger
+}
+
+func NewSMTPMailer(cfg config.Config, logger *slog.Logger) *SMTPMailer {
+	return &SMTPMailer{Config: cfg, Logger: logger}
+}
 
-	from := cfg.EmailFrom
-	smtpPass := cfg.SMTPPass
-	smtpUser := cfg.SMTPUser
-	smtpHost := cfg.SMTPHost
-	smtpPort := cfg.SMTPPort
+func (m *SMTPMailer) SendEmail(to, subject, body string) error {
+	from := m.Config.EmailFrom
+	smtpPass := m.Config.SMTPPass
+	smtpUser := m.Config.SMTPUser
+	smtpHost := m.Config.SMTPHost
+	smtpPort := m.Config.SMTPPort
 
 	// Create new message
-	m := gomail.NewMessage()
-	m.SetHeader("From", from)
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/html", body)
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", from)
+	msg.SetHeader("To", to)
+	msg.SetHeader("Subject", subject)
+	msg.SetBody("text/html", body)
 
 	// Dialer configuration
 	d := gomail.NewDialer(smtpHost, smtpPort, smtpUser, smtpPass)
 	d.TLSConfig = &tls.Config{InsecureSkipVerify: true} // NOTE: for dev only
 
 	// Send email
-	if err := d.DialAndSend(m); err != nil {
-		fmt.Println("Error sending email:", err)
-		return err
+	if err := d.DialAndSend(msg); err != nil {
+		return fmt.Errorf("sending email: %w", err)
 	}
 
-	fmt.Println("Email sent successfully to", to)
+	m.Logger.Debug("email sent", "to", to, "subject", subject)
 	return nil
 }
 
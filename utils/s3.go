@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_config "github.com/aws/aws-sdk-go-v2/config"
 	aws_credentials "github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	app_config "github.com/sagar-rathod-devops/do-host-network-backend/config"
+	"github.com/sagar-rathod-devops/do-host-network-backend/internal/tracing"
 )
 
 type S3Uploader struct {
@@ -33,7 +35,9 @@ func NewS3Uploader(cfg app_config.Config) (*S3Uploader, error) {
 		return nil, err
 	}
 
-	client := s3.NewFromConfig(awsCfg)
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		o.APIOptions = append(o.APIOptions, tracing.AWSMiddleware)
+	})
 
 	return &S3Uploader{
 		Client:     client,
@@ -42,7 +46,7 @@ func NewS3Uploader(cfg app_config.Config) (*S3Uploader, error) {
 	}, nil
 }
 
-func (u *S3Uploader) UploadFile(file multipart.File, fileHeader *multipart.FileHeader, key string) (string, error) {
+func (u *S3Uploader) UploadFile(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, key string) (string, error) {
 	defer file.Close()
 
 	buf := bytes.NewBuffer(nil)
@@ -50,7 +54,7 @@ func (u *S3Uploader) UploadFile(file multipart.File, fileHeader *multipart.FileH
 		return "", err
 	}
 
-	_, err := u.Client.PutObject(context.TODO(), &s3.PutObjectInput{
+	_, err := u.Client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(u.BucketName),
 		Key:         aws.String(key),
 		Body:        bytes.NewReader(buf.Bytes()),
@@ -60,6 +64,23 @@ func (u *S3Uploader) UploadFile(file multipart.File, fileHeader *multipart.FileH
 		return "", err
 	}
 
-	url := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.BucketName, u.Region, key)
-	return url, nil
+	return u.baseURL() + key, nil
+}
+
+// DeleteFile deletes the object at a URL returned by UploadFile.
+func (u *S3Uploader) DeleteFile(ctx context.Context, url string) error {
+	key, ok := strings.CutPrefix(url, u.baseURL())
+	if !ok || key == "" {
+		return fmt.Errorf("%q is not an object of bucket %s", url, u.BucketName)
+	}
+
+	_, err := u.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(u.BucketName),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (u *S3Uploader) baseURL() string {
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/", u.BucketName, u.Region)
 }